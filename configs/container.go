@@ -8,6 +8,7 @@ import (
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/ory/dockertest"
@@ -19,10 +20,17 @@ var (
 	password   = "secret"
 	database   = "api"
 	dataSource = "%s:%s@tcp(localhost:%s)/%s?parseTime=true"
+
+	postgresUser       = "postgres"
+	postgresDataSource = "postgres://%s:%s@localhost:%s/%s?sslmode=disable"
 )
 
 type Container struct {
-	DB       *sqlx.DB
+	DB *sqlx.DB
+	// URL is the data source of the database, passed to repository.New
+	URL string
+	// Driver is the driver of repository.New, mysql or postgres
+	Driver   string
 	pool     *dockertest.Pool
 	resource *dockertest.Resource
 }
@@ -74,10 +82,70 @@ func ContainerRun(port string) *Container {
 		log.Print(err)
 	}
 
-	os.Setenv("DATABASE_URL", mysqlUrl)
+	return &Container{
+		DB:       db,
+		URL:      mysqlUrl,
+		Driver:   "mysql",
+		pool:     pool,
+		resource: resource,
+	}
+}
+
+// PostgresContainerRun starts PostgreSQL to run the repository tests on it,
+// the migrations are in the postgres directory of the migrations.
+func PostgresContainerRun(port string) *Container {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.Fatalf("Could not connect to docker: %s", err)
+	}
+
+	opts := dockertest.RunOptions{
+		Repository: "postgres",
+		Tag:        "16",
+		Env: []string{
+			"POSTGRES_DB=" + database,
+			"POSTGRES_PASSWORD=" + password,
+		},
+		ExposedPorts: []string{"5432"},
+		PortBindings: map[docker.Port][]docker.PortBinding{
+			"5432": {
+				{HostIP: "0.0.0.0", HostPort: port},
+			},
+		},
+	}
+
+	resource, err := pool.RunWithOptions(&opts, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{
+			Name: "no",
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not start resource: %s", err.Error())
+	}
+
+	var postgresUrl = fmt.Sprintf(postgresDataSource, postgresUser, password, port, database)
+
+	var db *sqlx.DB
+	err = pool.Retry(func() error {
+		db, err = sqlx.Connect("pgx", postgresUrl)
+		return err
+	})
+	if err != nil {
+		log.Fatalf("Could not connect to postgres: %s", err)
+	}
+
+	err = resource.Expire(2 * 60)
+	if err != nil {
+		log.Print(err)
+	}
 
 	return &Container{
-		db, pool, resource,
+		DB:       db,
+		URL:      postgresUrl,
+		Driver:   "postgres",
+		pool:     pool,
+		resource: resource,
 	}
 }
 
@@ -102,7 +170,11 @@ func (c *Container) RunMigrations(migrationsDir string) {
 				log.Fatalf("Error read file: %s, %v", files[i].Name(), err)
 			}
 
-			requests := strings.Split(strings.TrimSpace(string(fileData)), ";")
+			// postgres runs a whole file at once, functions have ; in their body
+			requests := []string{string(fileData)}
+			if c.Driver != "postgres" {
+				requests = strings.Split(strings.TrimSpace(string(fileData)), ";")
+			}
 
 			for _, request := range requests {
 				if len(request) > 5 {
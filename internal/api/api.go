@@ -1,40 +1,108 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	"github.com/lucas-simao/api-tasks/internal/api/handlers"
+	"github.com/lucas-simao/api-tasks/internal/api/openapi"
+	"github.com/lucas-simao/api-tasks/internal/domain/apikeys"
+	"github.com/lucas-simao/api-tasks/internal/domain/authz"
+	"github.com/lucas-simao/api-tasks/internal/domain/health"
+	"github.com/lucas-simao/api-tasks/internal/domain/mfa"
+	"github.com/lucas-simao/api-tasks/internal/domain/organizations"
+	"github.com/lucas-simao/api-tasks/internal/domain/reports"
+	"github.com/lucas-simao/api-tasks/internal/domain/sso"
+	"github.com/lucas-simao/api-tasks/internal/domain/stats"
 	"github.com/lucas-simao/api-tasks/internal/domain/tasks"
+	"github.com/lucas-simao/api-tasks/internal/domain/teams"
 	"github.com/lucas-simao/api-tasks/internal/domain/users"
-
-	"os"
+	"github.com/lucas-simao/api-tasks/internal/logging"
+	"github.com/lucas-simao/api-tasks/internal/metrics"
+	"github.com/lucas-simao/api-tasks/internal/tracing"
 )
 
 type Services struct {
-	Tasks tasks.Service
-	Users users.Service
+	Tasks         tasks.Service
+	Users         users.Service
+	MFA           mfa.Service
+	Reports       reports.Service
+	Stats         stats.Service
+	Teams         teams.Service
+	Organizations organizations.Service
+	Authz         authz.Service
+	ApiKeys       apikeys.Service
+	// SSO is nil unless single sign-on is configured
+	SSO     sso.Service
+	Health  health.Service
+	Metrics *metrics.Metrics
+	Logger  *slog.Logger
 }
 
-var port string = "9000"
+type Config struct {
+	// JWTSecret signs and verifies the tokens of the users.
+	JWTSecret string
+	// OpenAPIValidation rejects requests not matching openapi.yaml.
+	OpenAPIValidation bool
+	// UnversionedSunset is sent in the Sunset header of the routes without
+	// version, when set.
+	UnversionedSunset time.Time
+}
 
-func New(s Services) *echo.Echo {
+func New(s Services, c Config) (*echo.Echo, error) {
 	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+
+	if s.Metrics == nil {
+		s.Metrics = metrics.New()
+	}
+	if s.Logger == nil {
+		s.Logger = slog.Default()
+	}
+
+	e.HTTPErrorHandler = handlers.ErrorHandler(s.Logger)
 
-	e.Use(middleware.Logger())
+	e.Use(middleware.RequestID())
+	e.Use(logging.Middleware(s.Logger))
+	e.Use(s.Metrics.Middleware())
+	e.Use(tracing.Middleware())
 	e.Use(middleware.Recover())
 
-	addRoutes(e, s)
+	spec, err := openapi.Load()
+	if err != nil {
+		return nil, fmt.Errorf("error to load openapi spec: %w", err)
+	}
+
+	if c.OpenAPIValidation {
+		validator, err := openapi.Validator(spec, openapi.ValidatorConfig{Logger: s.Logger})
+		if err != nil {
+			return nil, fmt.Errorf("error to create openapi validator: %w", err)
+		}
+		e.Use(validator)
+	}
 
-	return e
+	if err := addRoutes(e, s, c, spec, Versions(c.UnversionedSunset)); err != nil {
+		return nil, err
+	}
+
+	return e, nil
 }
 
-func Start(e *echo.Echo) {
-	value, ok := os.LookupEnv("PORT")
-	if ok {
-		port = value
+// Start serves e until Shutdown is called, then it returns nil.
+func Start(e *echo.Echo, port string, logger *slog.Logger) error {
+	logger.Info("starting api", slog.String("port", port))
+
+	err := e.Start(fmt.Sprintf(":%s", port))
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
 	}
 
-	e.Logger.Fatal(e.Start(fmt.Sprintf(":%s", port)))
+	return err
 }
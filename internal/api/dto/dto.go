@@ -0,0 +1,13 @@
+// Package dto has the response bodies of each api version. Handlers work with
+// the entity types and a Presenter converts them to the body of the version
+// requested, so entities can change without breaking older clients.
+package dto
+
+import "github.com/lucas-simao/api-tasks/internal/entity"
+
+type Presenter interface {
+	Task(entity.TaskResponse) interface{}
+	Tasks([]entity.TaskResponse) interface{}
+	WorkLog(entity.WorkLogResponse) interface{}
+	WorkLogs([]entity.WorkLogResponse) interface{}
+}
@@ -0,0 +1,83 @@
+package v1
+
+import "github.com/lucas-simao/api-tasks/internal/entity"
+
+type UserOperation struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	Date string `json:"date"`
+}
+
+// Task is the task of v1, an open task has an empty finishedAt and a task not
+// deleted has a deletedBy with zero values.
+type Task struct {
+	Id               int           `json:"id"`
+	Title            string        `json:"title"`
+	Description      string        `json:"description"`
+	UpdatedAt        string        `json:"updatedAt"`
+	FinishedAt       string        `json:"finishedAt"`
+	TimeSpentSeconds int64         `json:"timeSpentSeconds"`
+	CreatedBy        UserOperation `json:"createdBy"`
+	DeletedBy        UserOperation `json:"deletedBy"`
+}
+
+type WorkLog struct {
+	Id              int           `json:"id"`
+	TaskId          int           `json:"taskId"`
+	StartedAt       string        `json:"startedAt"`
+	EndedAt         string        `json:"endedAt"`
+	DurationSeconds int64         `json:"durationSeconds"`
+	Note            string        `json:"note"`
+	LoggedBy        UserOperation `json:"loggedBy"`
+}
+
+type Presenter struct{}
+
+func (Presenter) Task(t entity.TaskResponse) interface{} {
+	return NewTask(t)
+}
+
+func (Presenter) Tasks(tasks []entity.TaskResponse) interface{} {
+	response := make([]Task, 0, len(tasks))
+	for _, t := range tasks {
+		response = append(response, NewTask(t))
+	}
+	return response
+}
+
+func (Presenter) WorkLog(w entity.WorkLogResponse) interface{} {
+	return NewWorkLog(w)
+}
+
+func (Presenter) WorkLogs(workLogs []entity.WorkLogResponse) interface{} {
+	response := make([]WorkLog, 0, len(workLogs))
+	for _, w := range workLogs {
+		response = append(response, NewWorkLog(w))
+	}
+	return response
+}
+
+func NewTask(t entity.TaskResponse) Task {
+	return Task{
+		Id:               t.Id,
+		Title:            t.Title,
+		Description:      t.Description,
+		UpdatedAt:        t.UpdatedAt,
+		FinishedAt:       t.FinishedAt,
+		TimeSpentSeconds: t.TimeSpentSeconds,
+		CreatedBy:        UserOperation(t.CreatedBy),
+		DeletedBy:        UserOperation(t.DeletedBy),
+	}
+}
+
+func NewWorkLog(w entity.WorkLogResponse) WorkLog {
+	return WorkLog{
+		Id:              w.Id,
+		TaskId:          w.TaskId,
+		StartedAt:       w.StartedAt,
+		EndedAt:         w.EndedAt,
+		DurationSeconds: w.DurationSeconds,
+		Note:            w.Note,
+		LoggedBy:        UserOperation(w.LoggedBy),
+	}
+}
@@ -0,0 +1,97 @@
+package v2
+
+import "github.com/lucas-simao/api-tasks/internal/entity"
+
+type UserOperation struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	Date string `json:"date"`
+}
+
+// Task is the task of v2, finishedAt and deletedBy are null while the task is
+// open or not deleted.
+type Task struct {
+	Id               int            `json:"id"`
+	Title            string         `json:"title"`
+	Description      string         `json:"description"`
+	UpdatedAt        string         `json:"updatedAt"`
+	FinishedAt       *string        `json:"finishedAt"`
+	TimeSpentSeconds int64          `json:"timeSpentSeconds"`
+	CreatedBy        UserOperation  `json:"createdBy"`
+	DeletedBy        *UserOperation `json:"deletedBy"`
+}
+
+// WorkLog is the work log of v2, endedAt is null while the timer is running.
+type WorkLog struct {
+	Id              int           `json:"id"`
+	TaskId          int           `json:"taskId"`
+	StartedAt       string        `json:"startedAt"`
+	EndedAt         *string       `json:"endedAt"`
+	DurationSeconds int64         `json:"durationSeconds"`
+	Note            string        `json:"note"`
+	LoggedBy        UserOperation `json:"loggedBy"`
+}
+
+type Presenter struct{}
+
+func (Presenter) Task(t entity.TaskResponse) interface{} {
+	return NewTask(t)
+}
+
+func (Presenter) Tasks(tasks []entity.TaskResponse) interface{} {
+	response := make([]Task, 0, len(tasks))
+	for _, t := range tasks {
+		response = append(response, NewTask(t))
+	}
+	return response
+}
+
+func (Presenter) WorkLog(w entity.WorkLogResponse) interface{} {
+	return NewWorkLog(w)
+}
+
+func (Presenter) WorkLogs(workLogs []entity.WorkLogResponse) interface{} {
+	response := make([]WorkLog, 0, len(workLogs))
+	for _, w := range workLogs {
+		response = append(response, NewWorkLog(w))
+	}
+	return response
+}
+
+func NewTask(t entity.TaskResponse) Task {
+	task := Task{
+		Id:               t.Id,
+		Title:            t.Title,
+		Description:      t.Description,
+		UpdatedAt:        t.UpdatedAt,
+		FinishedAt:       nullable(t.FinishedAt),
+		TimeSpentSeconds: t.TimeSpentSeconds,
+		CreatedBy:        UserOperation(t.CreatedBy),
+	}
+
+	if t.DeletedBy.Id != 0 {
+		deletedBy := UserOperation(t.DeletedBy)
+		task.DeletedBy = &deletedBy
+	}
+
+	return task
+}
+
+func NewWorkLog(w entity.WorkLogResponse) WorkLog {
+	return WorkLog{
+		Id:              w.Id,
+		TaskId:          w.TaskId,
+		StartedAt:       w.StartedAt,
+		EndedAt:         nullable(w.EndedAt),
+		DurationSeconds: w.DurationSeconds,
+		Note:            w.Note,
+		LoggedBy:        UserOperation(w.LoggedBy),
+	}
+}
+
+func nullable(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
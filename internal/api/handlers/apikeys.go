@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/domain/apikeys"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+const (
+	// apiKeyScheme is the scheme of the Authorization header of api keys,
+	// tokens keep the Bearer scheme
+	apiKeyScheme = "ApiKey"
+	apiKeyIdKey  = "apiKeyId"
+)
+
+// AuthenticateApiKey authenticates the requests with an api key as the service
+// user of the key, with the permissions of the key. The session is set like the
+// JWT middleware sets it, which skips these requests, see HasApiKey.
+func AuthenticateApiKey(s apikeys.Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scheme, key, found := strings.Cut(c.Request().Header.Get(echo.HeaderAuthorization), " ")
+			if !found || !strings.EqualFold(scheme, apiKeyScheme) {
+				return next(c)
+			}
+
+			p, err := s.Authenticate(c.Request().Context(), strings.TrimSpace(key))
+			if err != nil {
+				return fmt.Errorf("error to authenticate api key: %w", err)
+			}
+
+			c.Set("user", &jwt.Token{
+				Valid: true,
+				Claims: &entity.JwtCustomClaims{
+					Id:             p.User.Id,
+					Name:           p.User.Name,
+					Username:       p.User.Username,
+					CodeRole:       p.User.CodeRole,
+					OrganizationId: p.User.OrganizationId,
+				},
+			})
+			c.Set(apiKeyIdKey, p.ApiKeyId)
+			c.Set(permissionsKey, entity.NewPermissions(p.Permissions...))
+
+			return next(c)
+		}
+	}
+}
+
+// HasApiKey reports whether the request was authenticated with an api key.
+func HasApiKey(c echo.Context) bool {
+	_, ok := GetApiKeyId(c)
+	return ok
+}
+
+func GetApiKeyId(c echo.Context) (int, bool) {
+	id, ok := c.Get(apiKeyIdKey).(int)
+	return id, ok
+}
+
+func CreateApiKey(s apikeys.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		if err := require(c, entity.PermissionUserManage); err != nil {
+			return err
+		}
+
+		p := entity.ApiKeyRequest{}
+
+		err := bindRequest(c, &p)
+		if err != nil {
+			return err
+		}
+
+		p.UserId = GetAuthSession(c).Id
+
+		key, err := s.CreateApiKey(ctx, p)
+		if err != nil {
+			return fmt.Errorf("error to create api key: %w", err)
+		}
+
+		return c.JSON(http.StatusCreated, key)
+	}
+}
+
+func GetApiKeys(s apikeys.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		if err := require(c, entity.PermissionUserManage); err != nil {
+			return err
+		}
+
+		keys, err := s.GetApiKeys(ctx)
+		if err != nil {
+			return fmt.Errorf("error to get api keys: %w", err)
+		}
+
+		return c.JSON(http.StatusOK, keys)
+	}
+}
+
+func RevokeApiKey(s apikeys.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		id, err := parseIdParam(c)
+		if err != nil {
+			return err
+		}
+
+		if err := require(c, entity.PermissionUserManage); err != nil {
+			return err
+		}
+
+		err = s.RevokeApiKey(ctx, id)
+		if err != nil {
+			return fmt.Errorf("error to revoke api key: %w", err)
+		}
+
+		return c.JSON(http.StatusOK, ResultMessage{
+			Message: fmt.Sprintf("api key %d revoked", id),
+		})
+	}
+}
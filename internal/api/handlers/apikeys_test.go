@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/domain/apikeys"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/tenant"
+	"github.com/stretchr/testify/suite"
+)
+
+type ApiKeysTestSuite struct {
+	suite.Suite
+	ctx     context.Context
+	service apikeys.Service
+	key     string
+	revoked string
+}
+
+func TestApiKeysTestSuite(t *testing.T) {
+	suite.Run(t, new(ApiKeysTestSuite))
+}
+
+func (suite *ApiKeysTestSuite) SetupSuite() {
+	suite.ctx = tenant.WithOrganization(context.Background(), defaultOrganization)
+	suite.service = apikeys.New(repo)
+
+	key, err := suite.service.CreateApiKey(suite.ctx, entity.ApiKeyRequest{
+		Name:        "bms",
+		Permissions: []string{entity.PermissionTaskCreate},
+		ExpiresAt:   time.Now().AddDate(1, 0, 0),
+		UserId:      ManagerUser.Id,
+	})
+	suite.Require().NoError(err)
+	suite.key = key.Key
+
+	revoked, err := suite.service.CreateApiKey(suite.ctx, entity.ApiKeyRequest{
+		Name:        "revoked",
+		Permissions: []string{entity.PermissionTaskCreate},
+		ExpiresAt:   time.Now().AddDate(1, 0, 0),
+		UserId:      ManagerUser.Id,
+	})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.service.RevokeApiKey(suite.ctx, revoked.Id))
+	suite.revoked = revoked.Key
+}
+
+func (suite *ApiKeysTestSuite) TearDownTest() {
+	err := deleteTasks()
+	suite.NoError(err)
+}
+
+func (suite *ApiKeysTestSuite) TestAuthenticateApiKey() {
+	taskId, err := createTask("test api key", "this test shouldn't be deleted by a key", TechnicianUser.Id)
+	suite.NoError(err)
+
+	cases := map[string]struct {
+		method, path, body, authorization string
+		taskId                            int64
+		handler                           echo.HandlerFunc
+		statusCode                        int
+	}{
+		"1 - Should return 201 - created with the permission of the key": {
+			method:        http.MethodPost,
+			path:          "/tasks",
+			body:          `{"title": "alarm", "description": "sensor 12 alarmed"}`,
+			authorization: "ApiKey " + suite.key,
+			handler:       CreateTask(TasksService),
+			statusCode:    http.StatusCreated,
+		},
+		"2 - Should return 403 - key without the permission": {
+			method:        http.MethodDelete,
+			path:          "/tasks/:id",
+			taskId:        taskId,
+			authorization: "ApiKey " + suite.key,
+			handler:       DeleteTaskById(TasksService),
+			statusCode:    http.StatusForbidden,
+		},
+		"3 - Should return 401 - revoked key": {
+			method:        http.MethodPost,
+			path:          "/tasks",
+			body:          `{"title": "alarm", "description": "sensor 12 alarmed"}`,
+			authorization: "ApiKey " + suite.revoked,
+			handler:       CreateTask(TasksService),
+			statusCode:    http.StatusUnauthorized,
+		},
+	}
+
+	keys := make([]string, 0, len(cases))
+	for v := range cases {
+		keys = append(keys, v)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		suite.Run(key, func() {
+			tc := cases[key]
+
+			c, rr := createContext(tc.method, tc.path, strings.NewReader(tc.body))
+			c.Request().Header.Set(echo.HeaderAuthorization, tc.authorization)
+			// set by the api from the session
+			c.SetRequest(c.Request().WithContext(suite.ctx))
+
+			if tc.taskId != 0 {
+				c.SetParamNames("id")
+				c.SetParamValues(strconv.FormatInt(tc.taskId, 10))
+			}
+
+			handler := AuthenticateApiKey(suite.service)(Authorize(AuthzService)(tc.handler))
+
+			err := serve(handler, c)
+
+			suite.NoError(err)
+
+			suite.Equal(tc.statusCode, rr.Code, rr.Body)
+		})
+	}
+}
+
+func (suite *ApiKeysTestSuite) TestBearerKeyIsNotApiKey() {
+	c, _ := createContext(http.MethodGet, "/tasks", nil)
+	c.Request().Header.Set(echo.HeaderAuthorization, "Bearer "+suite.key)
+
+	// the key is left to the authentication of tokens, which rejects it
+	err := AuthenticateApiKey(suite.service)(func(c echo.Context) error {
+		suite.False(HasApiKey(c))
+		return nil
+	})(c)
+
+	suite.NoError(err)
+}
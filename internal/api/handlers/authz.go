@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/domain/authz"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+const permissionsKey = "permissions"
+
+// Authorize loads the permissions of the role of the user, handlers check them
+// with require and scope. It runs after the JWT middleware, api keys bring
+// their own permissions.
+func Authorize(s authz.Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if HasApiKey(c) {
+				return next(c)
+			}
+
+			permissions, err := s.Permissions(c.Request().Context(), GetAuthSession(c).CodeRole)
+			if err != nil {
+				return fmt.Errorf("error to get permissions: %w", err)
+			}
+
+			c.Set(permissionsKey, permissions)
+
+			return next(c)
+		}
+	}
+}
+
+// GetPermissions returns the permissions loaded by Authorize, none when it
+// didn't run.
+func GetPermissions(c echo.Context) entity.Permissions {
+	permissions, _ := c.Get(permissionsKey).(entity.Permissions)
+	return permissions
+}
+
+// require returns a forbidden error unless the user has the permission, or a
+// scope of it.
+func require(c echo.Context, permission string) error {
+	if !GetPermissions(c).Allows(permission) {
+		return forbidden("user don't have the permission %s", permission)
+	}
+
+	return nil
+}
+
+// scope returns the widest scope of the action the user has, a forbidden
+// error when the user has none.
+func scope(c echo.Context, action string) (entity.Scope, error) {
+	s := GetPermissions(c).Scope(action)
+	if s == entity.ScopeNone {
+		return entity.ScopeNone, forbidden("user don't have the permission %s", action)
+	}
+
+	return s, nil
+}
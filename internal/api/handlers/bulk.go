@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/domain/tasks"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+// bulkActionPermissions keeps the bulk actions in line with the single task
+// handlers, restoring a task is undoing its delete.
+var bulkActionPermissions = map[string]string{
+	entity.BulkActionDelete:   entity.PermissionTaskDelete,
+	entity.BulkActionRestore:  entity.PermissionTaskDelete,
+	entity.BulkActionReassign: entity.PermissionTaskReassignTeam,
+	entity.BulkActionFinish:   entity.PermissionTaskUpdateOwn,
+}
+
+func BulkTasks(s tasks.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		p := entity.BulkTaskRequest{}
+
+		err := bindRequest(c, &p)
+		if err != nil {
+			return err
+		}
+
+		session := GetAuthSession(c)
+
+		p.UserId = session.Id
+		p.CodeRole = session.CodeRole
+
+		permission, ok := bulkActionPermissions[p.Action]
+		if !ok || !GetPermissions(c).Allows(permission) {
+			return forbidden("user don't have permission to %s tasks", p.Action)
+		}
+
+		p.Scope = GetPermissions(c).Scope(entity.PermissionTaskDelete)
+
+		response, err := s.BulkTasks(ctx, p)
+		if err != nil {
+			return fmt.Errorf("error to apply bulk action: %w", err)
+		}
+
+		var httpStatus int = http.StatusOK
+
+		if response.Failed > 0 {
+			httpStatus = http.StatusMultiStatus
+		}
+
+		return c.JSON(httpStatus, response)
+	}
+}
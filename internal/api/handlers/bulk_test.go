@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type BulkTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestBulkTestSuite(t *testing.T) {
+	suite.Run(t, new(BulkTestSuite))
+}
+
+func (suite *BulkTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+}
+
+func (suite *BulkTestSuite) TearDownTest() {
+	err := deleteTasks()
+	suite.NoError(err)
+}
+
+func (suite *BulkTestSuite) TestBulkTasks() {
+	firstId, err := createTask("test bulk 1", "this test should be bulk updated", TechnicianUser.Id)
+	suite.NoError(err)
+
+	secondId, err := createTask("test bulk 2", "this test should be bulk updated", TechnicianUser.Id)
+	suite.NoError(err)
+
+	cases := map[string]struct {
+		body       string
+		user       entity.User
+		statusCode int
+		succeeded  int
+	}{
+		"1 - Should return 200 - technician finishes tasks": {
+			body:       fmt.Sprintf(`{ "action": "finish", "ids": [%d, %d]}`, firstId, secondId),
+			user:       TechnicianUser,
+			statusCode: http.StatusOK,
+			succeeded:  2,
+		},
+		"2 - Should return 207 - transaction rolled back": {
+			body:       fmt.Sprintf(`{ "action": "delete", "ids": [%d, 0]}`, firstId),
+			user:       ManagerUser,
+			statusCode: http.StatusMultiStatus,
+			succeeded:  0,
+		},
+		"3 - Should return 207 - per item applies valid ids": {
+			body:       fmt.Sprintf(`{ "action": "delete", "mode": "per-item", "ids": [%d, 0]}`, firstId),
+			user:       ManagerUser,
+			statusCode: http.StatusMultiStatus,
+			succeeded:  1,
+		},
+		"4 - Should return 200 - manager restores tasks": {
+			body:       fmt.Sprintf(`{ "action": "restore", "ids": [%d]}`, firstId),
+			user:       ManagerUser,
+			statusCode: http.StatusOK,
+			succeeded:  1,
+		},
+		"5 - Should return 403 - Technician can't delete tasks": {
+			body:       fmt.Sprintf(`{ "action": "delete", "ids": [%d]}`, firstId),
+			user:       TechnicianUser,
+			statusCode: http.StatusForbidden,
+		},
+		"6 - Should return 403 - Manager can't finish tasks": {
+			body:       fmt.Sprintf(`{ "action": "finish", "ids": [%d]}`, firstId),
+			user:       ManagerUser,
+			statusCode: http.StatusForbidden,
+		},
+		"7 - Should return 400 - reassign without assignee": {
+			body:       fmt.Sprintf(`{ "action": "reassign", "ids": [%d]}`, firstId),
+			user:       ManagerUser,
+			statusCode: http.StatusBadRequest,
+		},
+		"8 - Should return 400 - reassign to a manager": {
+			body:       fmt.Sprintf(`{ "action": "reassign", "assigneeId": %d, "ids": [%d]}`, ManagerUser.Id, firstId),
+			user:       ManagerUser,
+			statusCode: http.StatusBadRequest,
+		},
+		"9 - Should return 400 - unknown action": {
+			body:       fmt.Sprintf(`{ "action": "archive", "ids": [%d]}`, firstId),
+			user:       ManagerUser,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	keys := make([]string, 0, len(cases))
+	for v := range cases {
+		keys = append(keys, v)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		suite.Run(key, func() {
+			c, rr := createContextAuth(http.MethodPost, "/tasks/bulk", strings.NewReader(cases[key].body), cases[key].user)
+
+			handler := BulkTasks(TasksService)
+
+			err := serve(handler, c)
+
+			suite.NoError(err)
+
+			suite.Equal(cases[key].statusCode, rr.Code, rr.Body)
+
+			if rr.Code == http.StatusOK || rr.Code == http.StatusMultiStatus {
+				response := entity.BulkTaskResponse{}
+				suite.NoError(json.Unmarshal(rr.Body.Bytes(), &response))
+				suite.Equal(cases[key].succeeded, response.Succeeded)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+)
+
+const (
+	MIMEApplicationProblemJSON = "application/problem+json"
+
+	codeInternal = "internal_error"
+)
+
+// Problem is the RFC 7807 body of every error response. Clients should rely on
+// Code. Title and Detail are only for humans.
+type Problem struct {
+	Type      string                `json:"type"`
+	Title     string                `json:"title"`
+	Status    int                   `json:"status"`
+	Detail    string                `json:"detail,omitempty"`
+	Instance  string                `json:"instance,omitempty"`
+	Code      string                `json:"code"`
+	RequestId string                `json:"requestId,omitempty"`
+	Errors    []apperror.FieldError `json:"errors,omitempty"`
+}
+
+var kindStatus = map[apperror.Kind]int{
+	apperror.KindInternal:     http.StatusInternalServerError,
+	apperror.KindInvalid:      http.StatusBadRequest,
+	apperror.KindUnauthorized: http.StatusUnauthorized,
+	apperror.KindForbidden:    http.StatusForbidden,
+	apperror.KindNotFound:     http.StatusNotFound,
+	apperror.KindConflict:     http.StatusConflict,
+	apperror.KindUnavailable:  http.StatusServiceUnavailable,
+}
+
+// ErrorHandler returns the echo HTTPErrorHandler, it writes every error
+// returned by handlers and middlewares as application/problem+json. Errors that
+// aren't an apperror.Error or an echo.HTTPError are hidden from clients and
+// logged.
+func ErrorHandler(logger *slog.Logger) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		ctx := c.Request().Context()
+
+		p := NewProblem(err)
+		p.Instance = c.Request().URL.Path
+		p.RequestId = c.Response().Header().Get(echo.HeaderXRequestID)
+
+		if p.Code == codeInternal {
+			logger.ErrorContext(ctx, "request failed", slog.String("method", c.Request().Method), slog.Any("error", err))
+		}
+
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(p.Status)
+		} else {
+			var body []byte
+			body, err = json.Marshal(p)
+			if err == nil {
+				err = c.Blob(p.Status, MIMEApplicationProblemJSON, body)
+			}
+		}
+
+		if err != nil {
+			logger.ErrorContext(ctx, "error to write problem", slog.Any("error", err))
+		}
+	}
+}
+
+// NewProblem maps err to the problem sent to clients.
+func NewProblem(err error) Problem {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) && appErr.Kind != apperror.KindInternal {
+		status := kindStatus[appErr.Kind]
+
+		return Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(status),
+			Status: status,
+			Detail: appErr.Message,
+			Code:   appErr.Code,
+			Errors: appErr.Fields,
+		}
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) && he.Code != http.StatusInternalServerError {
+		p := Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(he.Code),
+			Status: he.Code,
+			Code:   strings.ToLower(strings.ReplaceAll(http.StatusText(he.Code), " ", "_")),
+		}
+
+		if message, ok := he.Message.(string); ok && message != p.Title && he.Code < http.StatusInternalServerError {
+			p.Detail = message
+		}
+
+		return p
+	}
+
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+		Detail: "an unexpected error happened, report it with the request id",
+		Code:   codeInternal,
+	}
+}
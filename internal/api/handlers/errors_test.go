@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+var errInternal = errors.New("dial tcp 10.0.0.1:3306: connect: connection refused")
+
+type ErrorsTestSuite struct {
+	suite.Suite
+}
+
+func TestErrorsTestSuite(t *testing.T) {
+	suite.Run(t, new(ErrorsTestSuite))
+}
+
+func (suite *ErrorsTestSuite) TestProblemResponses() {
+	cases := map[string]struct {
+		path, id, body string
+		handler        echo.HandlerFunc
+		statusCode     int
+		code           string
+		fields         []string
+	}{
+		"1 - Should return 400 - validation with fields": {
+			path:       "/tasks/1",
+			id:         "1",
+			body:       `{"title": "", "description": ""}`,
+			handler:    UpdateTaskById(TasksService),
+			statusCode: http.StatusBadRequest,
+			code:       "validation_failed",
+			fields:     []string{"description", "title"},
+		},
+		"2 - Should return 404 - task not found": {
+			path:       "/tasks/0",
+			id:         "0",
+			handler:    GetTaskById(TasksService),
+			statusCode: http.StatusNotFound,
+			code:       "task_not_found",
+		},
+		"3 - Should return 500 - without the internal error": {
+			path: "/tasks/1",
+			handler: func(echo.Context) error {
+				return errInternal
+			},
+			statusCode: http.StatusInternalServerError,
+			code:       "internal_error",
+		},
+		"4 - Should return 404 - unknown route": {
+			path: "/unknown",
+			handler: func(echo.Context) error {
+				return echo.ErrNotFound
+			},
+			statusCode: http.StatusNotFound,
+			code:       "not_found",
+		},
+	}
+
+	keys := make([]string, 0, len(cases))
+	for v := range cases {
+		keys = append(keys, v)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		suite.Run(key, func() {
+			tc := cases[key]
+
+			c, rr := createContextAuth(http.MethodPut, tc.path, strings.NewReader(tc.body), TechnicianUser)
+
+			if tc.id != "" {
+				c.SetParamNames("id")
+				c.SetParamValues(tc.id)
+			}
+
+			// set by the request id middleware
+			c.Response().Header().Set(echo.HeaderXRequestID, "request-id")
+
+			err := serve(tc.handler, c)
+
+			suite.NoError(err)
+
+			suite.Equal(tc.statusCode, rr.Code, rr.Body)
+			suite.Equal(MIMEApplicationProblemJSON, rr.Header().Get(echo.HeaderContentType))
+			suite.NotContains(rr.Body.String(), errInternal.Error())
+
+			var p Problem
+			suite.NoError(json.Unmarshal(rr.Body.Bytes(), &p))
+
+			suite.Equal(tc.statusCode, p.Status)
+			suite.Equal(tc.code, p.Code)
+			suite.Equal(tc.path, p.Instance)
+			suite.Equal("request-id", p.RequestId)
+
+			var fields []string
+			for _, f := range p.Errors {
+				fields = append(fields, f.Field)
+			}
+			suite.Equal(tc.fields, fields)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/domain/tasks"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+var exportFlushEvery = 500
+
+func ExportTasks(s tasks.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		format := c.QueryParam("format")
+		if format == "" {
+			format = "csv"
+		}
+
+		if format != "csv" && format != "jsonl" {
+			return invalidParam("format", "should be csv or jsonl")
+		}
+
+		session := GetAuthSession(c)
+
+		if session.Id == 0 {
+			return ErrUnauthorized
+		}
+
+		filename := fmt.Sprintf("tasks-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+		var write func(entity.TaskExport) error
+		var flush func() error
+
+		switch format {
+		case "csv":
+			res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+			res.WriteHeader(http.StatusOK)
+
+			w := csv.NewWriter(res)
+
+			err := w.Write(entity.TaskExportColumns)
+			if err != nil {
+				return err
+			}
+
+			write = func(t entity.TaskExport) error {
+				return w.Write(t.Record())
+			}
+			flush = func() error {
+				w.Flush()
+				return w.Error()
+			}
+		case "jsonl":
+			res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+			res.WriteHeader(http.StatusOK)
+
+			enc := json.NewEncoder(res)
+
+			write = func(t entity.TaskExport) error {
+				return enc.Encode(t)
+			}
+			flush = func() error {
+				return nil
+			}
+		}
+
+		var count int
+
+		// the status was already sent, errors from here on only end the stream
+		err := s.ExportTasks(ctx, session.Id, GetPermissions(c).Scope(entity.PermissionTaskRead), func(t entity.TaskExport) error {
+			err := write(t)
+			if err != nil {
+				return err
+			}
+
+			count++
+			if count%exportFlushEvery == 0 {
+				err = flush()
+				if err != nil {
+					return err
+				}
+				res.Flush()
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = flush()
+		if err != nil {
+			return err
+		}
+		res.Flush()
+
+		return nil
+	}
+}
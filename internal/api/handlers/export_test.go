@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExportTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestExportTestSuite(t *testing.T) {
+	suite.Run(t, new(ExportTestSuite))
+}
+
+func (suite *ExportTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+}
+
+func (suite *ExportTestSuite) TearDownTest() {
+	err := deleteTasks()
+	suite.NoError(err)
+}
+
+func (suite *ExportTestSuite) TestExportTasks() {
+	_, err := createTask("test export", "this test should be exported, with comma", TechnicianUser.Id)
+	suite.NoError(err)
+
+	_, err = createTask("test export 2", "this test should be exported too", TechnicianUser.Id)
+	suite.NoError(err)
+
+	cases := map[string]struct {
+		format      string
+		user        entity.User
+		statusCode  int
+		contentType string
+	}{
+		"1 - Should return 200 - csv": {
+			format:      "csv",
+			user:        TechnicianUser,
+			statusCode:  http.StatusOK,
+			contentType: "text/csv",
+		},
+		"2 - Should return 200 - jsonl": {
+			format:      "jsonl",
+			user:        ManagerUser,
+			statusCode:  http.StatusOK,
+			contentType: "application/x-ndjson",
+		},
+		"3 - Should return 400 - invalid format": {
+			format:     "xlsx",
+			user:       ManagerUser,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	keys := make([]string, 0, len(cases))
+	for v := range cases {
+		keys = append(keys, v)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		suite.Run(key, func() {
+			c, rr := createContextAuth(http.MethodGet, "/tasks/export?format="+cases[key].format, nil, cases[key].user)
+
+			handler := ExportTasks(TasksService)
+
+			err := serve(handler, c)
+
+			suite.NoError(err)
+
+			suite.Equal(cases[key].statusCode, rr.Code, rr.Body)
+
+			if rr.Code != http.StatusOK {
+				return
+			}
+
+			suite.True(strings.HasPrefix(rr.Header().Get("Content-Type"), cases[key].contentType))
+
+			switch cases[key].format {
+			case "csv":
+				records, err := csv.NewReader(rr.Body).ReadAll()
+				suite.NoError(err)
+				suite.Len(records, 3)
+				suite.Equal(entity.TaskExportColumns, records[0])
+			case "jsonl":
+				lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+				suite.Len(lines, 2)
+
+				task := entity.TaskExport{}
+				suite.NoError(json.Unmarshal([]byte(lines[0]), &task))
+				suite.Equal(TechnicianUser.Id, task.CreatedById)
+			}
+		})
+	}
+}
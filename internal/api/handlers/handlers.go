@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"strconv"
+
 	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/apperror"
 	"github.com/lucas-simao/api-tasks/internal/entity"
 )
 
@@ -10,16 +14,59 @@ type ResultMessage struct {
 	Message string `json:"message"`
 }
 
-var result ResultMessage
+var (
+	ErrUnauthorized = apperror.Unauthorized("unauthorized", "user unauthorized")
+	ErrForbidden    = apperror.Forbidden("forbidden", "user don't have permission")
+	ErrInvalidBody  = apperror.Invalid("invalid_body", "error to bind body")
+	ErrInvalidParam = apperror.Invalid("invalid_parameter", "invalid parameter")
+)
+
+type request interface {
+	Validate() error
+}
+
+// bindRequest binds the body into p and validates it.
+func bindRequest(c echo.Context, p request) error {
+	err := c.Bind(p)
+	if err != nil {
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			return ErrInvalidBody.Withf("error to bind body: %v", he.Message).Wrap(err)
+		}
+		return ErrInvalidBody.Wrap(err)
+	}
+
+	return apperror.Validation(p.Validate())
+}
+
+func forbidden(format string, args ...interface{}) error {
+	return ErrForbidden.Withf(format, args...)
+}
+
+func invalidParam(name, message string) error {
+	err := ErrInvalidParam.Withf("%s %s", name, message)
+	err.Fields = []apperror.FieldError{{Field: name, Message: message}}
+	return err
+}
+
+func parseIdParam(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, invalidParam("id", "should be a number")
+	}
+
+	return id, nil
+}
 
 func GetAuthSession(c echo.Context) entity.User {
 	user := c.Get("user").(*jwt.Token)
 	claims := user.Claims.(*entity.JwtCustomClaims)
 
 	return entity.User{
-		Id:       claims.Id,
-		Name:     claims.Name,
-		Username: claims.Username,
-		CodeRole: claims.CodeRole,
+		Id:             claims.Id,
+		Name:           claims.Name,
+		Username:       claims.Username,
+		CodeRole:       claims.CodeRole,
+		OrganizationId: claims.OrganizationId,
 	}
 }
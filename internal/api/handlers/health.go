@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/domain/health"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+// Healthz answers while the process is able to serve requests, it doesn't
+// check dependencies so a database outage doesn't restart every replica.
+func Healthz() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, entity.HealthResponse{Status: entity.HealthStatusOk})
+	}
+}
+
+// Readyz answers 503 while the api shouldn't receive traffic.
+func Readyz(s health.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		err := s.Ready(c.Request().Context())
+		if err != nil {
+			return fmt.Errorf("error to check readiness: %w", err)
+		}
+
+		return c.JSON(http.StatusOK, entity.HealthResponse{Status: entity.HealthStatusReady})
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/domain/health"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+	"github.com/lucas-simao/api-tasks/scripts/migrations"
+	"github.com/stretchr/testify/suite"
+)
+
+// downRepository is a database that can't be reached.
+type downRepository struct{ repository.Repository }
+
+func (downRepository) Ping(context.Context) error { return errInternal }
+
+type HealthTestSuite struct {
+	suite.Suite
+	migrations []string
+}
+
+func TestHealthTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthTestSuite))
+}
+
+func (suite *HealthTestSuite) SetupSuite() {
+	names, err := migrations.Up(repository.DriverMySQL)
+	suite.Require().NoError(err)
+	suite.migrations = names
+}
+
+func (suite *HealthTestSuite) TestReadyz() {
+	shuttingDown := health.New(repo, suite.migrations)
+	shuttingDown.ShutDown()
+
+	// a migration the database doesn't have yet
+	pending := append([]string{"9999.up.sql"}, suite.migrations...)
+
+	cases := map[string]struct {
+		health     health.Service
+		statusCode int
+		code       string
+	}{
+		"1 - Should return 200 - ready": {
+			health:     health.New(repo, suite.migrations),
+			statusCode: http.StatusOK,
+		},
+		"2 - Should return 503 - shutting down": {
+			health:     shuttingDown,
+			statusCode: http.StatusServiceUnavailable,
+			code:       "shutting_down",
+		},
+		"3 - Should return 503 - database down": {
+			health:     health.New(downRepository{}, suite.migrations),
+			statusCode: http.StatusServiceUnavailable,
+			code:       "database_unavailable",
+		},
+		"4 - Should return 503 - migrations pending": {
+			health:     health.New(repo, pending),
+			statusCode: http.StatusServiceUnavailable,
+			code:       "migrations_pending",
+		},
+	}
+
+	keys := make([]string, 0, len(cases))
+	for v := range cases {
+		keys = append(keys, v)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		suite.Run(key, func() {
+			c, rr := createContext(http.MethodGet, "/readyz", nil)
+
+			err := serve(Readyz(cases[key].health), c)
+
+			suite.NoError(err)
+
+			suite.Equal(cases[key].statusCode, rr.Code, rr.Body)
+
+			if cases[key].code == "" {
+				return
+			}
+
+			var p Problem
+			suite.NoError(json.Unmarshal(rr.Body.Bytes(), &p))
+			suite.Equal(cases[key].code, p.Code)
+			suite.NotContains(rr.Body.String(), errInternal.Error())
+		})
+	}
+}
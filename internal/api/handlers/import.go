@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/domain/tasks"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+func ImportTasks(s tasks.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		if err := require(c, entity.PermissionTaskImport); err != nil {
+			return err
+		}
+
+		var dryRun bool
+
+		if value := c.QueryParam("dryRun"); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return invalidParam("dryRun", "should be a boolean")
+			}
+			dryRun = parsed
+		}
+
+		var body io.Reader = c.Request().Body
+
+		if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+			file, err := c.FormFile("file")
+			if err != nil {
+				return ErrInvalidBody.Withf("error to read file: %v", err).Wrap(err)
+			}
+
+			src, err := file.Open()
+			if err != nil {
+				return fmt.Errorf("error to open file: %w", err)
+			}
+
+			defer src.Close()
+
+			body = src
+		}
+
+		report, err := s.ImportTasks(ctx, GetAuthSession(c).Id, body, dryRun)
+		if errors.Is(err, tasks.ErrImportInterrupted) {
+			// the client resumes from the report, the error is still logged
+			if writeErr := c.JSON(http.StatusServiceUnavailable, report); writeErr != nil {
+				return writeErr
+			}
+			return fmt.Errorf("error to import tasks: %w", err)
+		}
+		if err != nil {
+			return fmt.Errorf("error to import tasks: %w", err)
+		}
+
+		if dryRun {
+			return c.JSON(http.StatusOK, report)
+		}
+
+		if report.Imported == 0 {
+			return c.JSON(http.StatusBadRequest, report)
+		}
+
+		return c.JSON(http.StatusCreated, report)
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type ImportTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestImportTestSuite(t *testing.T) {
+	suite.Run(t, new(ImportTestSuite))
+}
+
+func (suite *ImportTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+}
+
+func (suite *ImportTestSuite) TearDownTest() {
+	err := deleteTasks()
+	suite.NoError(err)
+}
+
+func (suite *ImportTestSuite) TestImportTasks() {
+	file := fmt.Sprintf(`title,description,username,created_at,performed_at
+Fix pump,Replaced the seal,%[1]s,2019-03-01 08:00:00,2019-03-01 10:30:00
+,Missing title,%[1]s,2019-03-02,
+Check boiler,Yearly check,unknownUser,2019-03-03,
+Paint wall,Wall of the depot,%[1]s,2019-03-04,2019-03-03
+`, TechnicianUser.Username)
+
+	cases := map[string]struct {
+		query      string
+		body       string
+		user       entity.User
+		statusCode int
+		imported   int
+	}{
+		"1 - Should return 200 - dry run report": {
+			query:      "?dryRun=true",
+			body:       file,
+			user:       ManagerUser,
+			statusCode: http.StatusOK,
+			imported:   0,
+		},
+		"2 - Should return 201 - valid rows imported": {
+			body:       file,
+			user:       ManagerUser,
+			statusCode: http.StatusCreated,
+			imported:   1,
+		},
+		"3 - Should return 403 - Technician can't import": {
+			body:       file,
+			user:       TechnicianUser,
+			statusCode: http.StatusForbidden,
+		},
+		"4 - Should return 400 - missing column": {
+			body:       "title,description\nFix pump,Replaced the seal\n",
+			user:       ManagerUser,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	keys := make([]string, 0, len(cases))
+	for v := range cases {
+		keys = append(keys, v)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		suite.Run(key, func() {
+			c, rr := createContextAuth(http.MethodPost, "/tasks/import"+cases[key].query, strings.NewReader(cases[key].body), cases[key].user)
+			c.Request().Header.Set("Content-Type", "text/csv")
+
+			handler := ImportTasks(TasksService)
+
+			err := serve(handler, c)
+
+			suite.NoError(err)
+
+			suite.Equal(cases[key].statusCode, rr.Code, rr.Body)
+
+			if rr.Code == http.StatusOK || rr.Code == http.StatusCreated {
+				report := entity.TaskImportReport{}
+				suite.NoError(json.Unmarshal(rr.Body.Bytes(), &report))
+				suite.Equal(4, report.Total)
+				suite.Equal(1, report.Valid)
+				suite.Equal(3, report.Invalid)
+				suite.Equal(cases[key].imported, report.Imported)
+				suite.Len(report.Errors, 3)
+			}
+		})
+	}
+
+	var finishedAt string
+	err := DB.Get(&finishedAt, `SELECT finished_at FROM tasks WHERE title = 'Fix pump'`)
+	suite.NoError(err)
+	suite.Contains(finishedAt, "2019-03-01")
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"io"
 	"log"
 	"net/http/httptest"
@@ -11,32 +12,50 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
 	"github.com/lucas-simao/api-tasks/configs"
+	"github.com/lucas-simao/api-tasks/internal/domain/authz"
+	"github.com/lucas-simao/api-tasks/internal/domain/mfa"
+	"github.com/lucas-simao/api-tasks/internal/domain/reports"
+	"github.com/lucas-simao/api-tasks/internal/domain/stats"
 	"github.com/lucas-simao/api-tasks/internal/domain/tasks"
 	"github.com/lucas-simao/api-tasks/internal/domain/users"
 	"github.com/lucas-simao/api-tasks/internal/entity"
 	"github.com/lucas-simao/api-tasks/internal/gateway/notifications"
+	"github.com/lucas-simao/api-tasks/internal/logging"
+	"github.com/lucas-simao/api-tasks/internal/metrics"
 	"github.com/lucas-simao/api-tasks/internal/repository"
+	"github.com/lucas-simao/api-tasks/internal/tenant"
 	"github.com/lucas-simao/api-tasks/internal/utils"
 )
 
+const testSecret = "handlers-test-secret-of-32-characters"
+
+// defaultOrganization is created by the migrations with the users existing
+// before organizations.
+const defaultOrganization = 1
+
 var (
 	repo           repository.Repository
 	DB             *sqlx.DB
 	UsersService   users.Service
 	TasksService   tasks.Service
+	ReportsService reports.Service
+	StatsService   stats.Service
+	AuthzService   authz.Service
 	TechnicianUser = entity.User{
-		Id:       1,
-		Name:     "lucas",
-		Username: "lsimaoTasksHandlers",
-		Password: "123456",
-		CodeRole: entity.TechnicianRole,
+		Id:             1,
+		Name:           "lucas",
+		Username:       "lsimaoTasksHandlers",
+		Password:       "123456",
+		CodeRole:       entity.TechnicianRole,
+		OrganizationId: defaultOrganization,
 	}
 	ManagerUser = entity.User{
-		Id:       2,
-		Name:     "joão",
-		Username: "joaoTasksHandlers",
-		Password: "123456789",
-		CodeRole: entity.ManagerRole,
+		Id:             2,
+		Name:           "joão",
+		Username:       "joaoTasksHandlers",
+		Password:       "123456789",
+		CodeRole:       entity.ManagerRole,
+		OrganizationId: defaultOrganization,
 	}
 )
 
@@ -44,18 +63,27 @@ func TestMain(m *testing.M) {
 	port := "3321"
 	newContainer := configs.ContainerRun(port)
 	newContainer.RunMigrations("../../../scripts/migrations")
-	repo = repository.New()
+	var err error
+	repo, err = repository.New(context.Background(), repository.Config{DataSource: newContainer.URL})
+	if err != nil {
+		log.Fatal(err)
+	}
 	DB = newContainer.DB
 
 	notificationsMock := notifications.MockNotifications{}
 
-	UsersService = users.New(repo)
-	TasksService = tasks.New(repo, &notificationsMock)
+	UsersService = users.New(repo, mfa.New(repo, mfa.Config{}, testSecret), testSecret)
+	StatsService = stats.New(repo)
+	TasksService = tasks.New(repo, &notificationsMock, metrics.New(), logging.Discard(), StatsService)
+	ReportsService = reports.New(repo)
+	AuthzService = authz.New(repo)
 
 	// Register Technician
 	signUpTechnician(TechnicianUser)
 	// Register Manager
 	signUpTManager(ManagerUser)
+	// managers only see the tasks of their teams
+	addToTeam(ManagerUser, TechnicianUser)
 
 	code := m.Run()
 	newContainer.ContainerDown()
@@ -64,6 +92,7 @@ func TestMain(m *testing.M) {
 
 func createContext(method, url string, body io.Reader) (c echo.Context, responseRecorder *httptest.ResponseRecorder) {
 	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(logging.Discard())
 	req := httptest.NewRequest(method, url, body)
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
@@ -74,40 +103,59 @@ func createContext(method, url string, body io.Reader) (c echo.Context, response
 
 func createContextAuth(method, url string, body io.Reader, user entity.User) (c echo.Context, responseRecorder *httptest.ResponseRecorder) {
 	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(logging.Discard())
 	req := httptest.NewRequest(method, url, body)
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c = e.NewContext(req, rec)
 
-	secret := os.Getenv("JWT_SECRET")
-
-	tokenSigned, err := utils.GenerateToken(secret, user)
+	tokenSigned, err := utils.GenerateToken(testSecret, user)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	claims := entity.JwtCustomClaims{
-		Id:       user.Id,
-		Name:     user.Name,
-		Username: user.Username,
-		CodeRole: user.CodeRole,
+		Id:             user.Id,
+		Name:           user.Name,
+		Username:       user.Username,
+		CodeRole:       user.CodeRole,
+		OrganizationId: user.OrganizationId,
 	}
 
 	token, err := jwt.ParseWithClaims(tokenSigned, &claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
+		return []byte(testSecret), nil
 	})
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	c.Set("user", token)
+	// set by the api from the token
+	c.SetRequest(req.WithContext(tenant.WithOrganization(req.Context(), user.OrganizationId)))
+
+	permissions, err := AuthzService.Permissions(c.Request().Context(), user.CodeRole)
+	if err != nil {
+		log.Fatal(err)
+	}
+	c.Set(permissionsKey, permissions)
 
 	return c, rec
 }
 
+// serve runs the handler like echo does, returned errors are written by the
+// error handler.
+func serve(handler echo.HandlerFunc, c echo.Context) error {
+	err := handler(c)
+	if err != nil {
+		c.Error(err)
+	}
+
+	return nil
+}
+
 func signUpTechnician(t entity.User) {
 	var technicianRoleId = 3
-	_, err := DB.Exec(`INSERT INTO users (name, username, password, user_role_id) VALUES(?, ?, ?, ?)`, t.Name, t.Username, t.Password, technicianRoleId)
+	_, err := DB.Exec(`INSERT INTO users (name, username, password, user_role_id, organization_id) VALUES(?, ?, ?, ?, ?)`, t.Name, t.Username, t.Password, technicianRoleId, t.OrganizationId)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -115,7 +163,21 @@ func signUpTechnician(t entity.User) {
 
 func signUpTManager(t entity.User) {
 	var managerRoleId = 2
-	_, err := DB.Exec(`INSERT INTO users (name, username, password, user_role_id) VALUES(?, ?, ?, ?)`, t.Name, t.Username, t.Password, managerRoleId)
+	_, err := DB.Exec(`INSERT INTO users (name, username, password, user_role_id, organization_id) VALUES(?, ?, ?, ?, ?)`, t.Name, t.Username, t.Password, managerRoleId, t.OrganizationId)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func addToTeam(manager, technician entity.User) {
+	ctx := tenant.WithOrganization(context.Background(), manager.OrganizationId)
+
+	id, err := repo.CreateTeam(ctx, entity.TeamRequest{Name: "handlers", UserId: manager.Id})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = repo.AddTeamMember(ctx, entity.TeamMemberRequest{TeamId: int(id), UserId: technician.Id, ManagerId: manager.Id})
 	if err != nil {
 		log.Fatal(err)
 	}
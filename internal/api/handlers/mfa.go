@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/domain/mfa"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+// EnrollMFAChallenge enrolls the users who must enable two-factor
+// authentication to sign in, with the MFA token returned by SignIn.
+func EnrollMFAChallenge(m mfa.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		p := entity.MFAEnrollRequest{}
+
+		err := bindRequest(c, &p)
+		if err != nil {
+			return err
+		}
+
+		enrollment, err := m.EnrollChallenge(ctx, p.MFAToken)
+		if err != nil {
+			return fmt.Errorf("error to enroll authenticator: %w", err)
+		}
+
+		return c.JSON(http.StatusCreated, enrollment)
+	}
+}
+
+func EnrollTOTP(m mfa.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		if HasApiKey(c) {
+			return forbidden("api keys don't sign in with codes")
+		}
+
+		enrollment, err := m.Enroll(ctx, GetAuthSession(c))
+		if err != nil {
+			return fmt.Errorf("error to enroll authenticator: %w", err)
+		}
+
+		return c.JSON(http.StatusCreated, enrollment)
+	}
+}
+
+func ActivateTOTP(m mfa.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		if HasApiKey(c) {
+			return forbidden("api keys don't sign in with codes")
+		}
+
+		p := entity.MFACodeRequest{}
+
+		err := bindRequest(c, &p)
+		if err != nil {
+			return err
+		}
+
+		codes, err := m.Activate(ctx, GetAuthSession(c), p.Code)
+		if err != nil {
+			return fmt.Errorf("error to enable two-factor authentication: %w", err)
+		}
+
+		return c.JSON(http.StatusOK, codes)
+	}
+}
+
+func DisableTOTP(m mfa.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		if HasApiKey(c) {
+			return forbidden("api keys don't sign in with codes")
+		}
+
+		p := entity.MFACodeRequest{}
+
+		err := bindRequest(c, &p)
+		if err != nil {
+			return err
+		}
+
+		err = m.Disable(ctx, GetAuthSession(c), p.Code)
+		if err != nil {
+			return fmt.Errorf("error to disable two-factor authentication: %w", err)
+		}
+
+		return c.JSON(http.StatusOK, ResultMessage{
+			Message: "two-factor authentication disabled",
+		})
+	}
+}
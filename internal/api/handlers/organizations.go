@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/domain/organizations"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+// GetOrganization shows the organization of the manager with its invite code.
+func GetOrganization(s organizations.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		if err := require(c, entity.PermissionUserManage); err != nil {
+			return err
+		}
+
+		o, err := s.GetOrganization(ctx)
+		if err != nil {
+			return fmt.Errorf("error to get organization: %w", err)
+		}
+
+		return c.JSON(http.StatusOK, o)
+	}
+}
+
+func RotateInviteCode(s organizations.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		if err := require(c, entity.PermissionUserManage); err != nil {
+			return err
+		}
+
+		o, err := s.RotateInviteCode(ctx)
+		if err != nil {
+			return fmt.Errorf("error to rotate invite code: %w", err)
+		}
+
+		return c.JSON(http.StatusOK, o)
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/domain/reports"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+var timesheetCSVHeader = []string{
+	"user_id", "user_name", "date", "type", "task_id", "task_title",
+	"started_at", "ended_at", "finished_at", "duration_seconds",
+}
+
+func GetTimesheet(s reports.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		r, err := parseTimesheetParams(c)
+		if err != nil {
+			return err
+		}
+
+		timesheet, err := s.GetTimesheet(ctx, r)
+		if err != nil {
+			return fmt.Errorf("error to get timesheet: %w", err)
+		}
+
+		if c.QueryParam("format") == "csv" {
+			filename := fmt.Sprintf("timesheet-%d-%s.csv", timesheet.UserId, timesheet.Date)
+			return writeTimesheetCSV(c, filename, []entity.TimesheetResponse{timesheet})
+		}
+
+		return c.JSON(http.StatusOK, timesheet)
+	}
+}
+
+func GetWeeklyTimesheet(s reports.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		r, err := parseTimesheetParams(c)
+		if err != nil {
+			return err
+		}
+
+		weekly, err := s.GetWeeklyTimesheet(ctx, r)
+		if err != nil {
+			return fmt.Errorf("error to get weekly timesheet: %w", err)
+		}
+
+		if c.QueryParam("format") == "csv" {
+			filename := fmt.Sprintf("timesheet-%d-%s-%s.csv", weekly.UserId, weekly.WeekStart, weekly.WeekEnd)
+			return writeTimesheetCSV(c, filename, weekly.Days)
+		}
+
+		return c.JSON(http.StatusOK, weekly)
+	}
+}
+
+// parseTimesheetParams reads the userId and date query params. Technicians may
+// only fetch their own timesheet, managers must choose one of their teammates.
+func parseTimesheetParams(c echo.Context) (entity.TimesheetRequest, error) {
+	session := GetAuthSession(c)
+
+	date := time.Now().UTC()
+	if value := c.QueryParam("date"); value != "" {
+		parsed, err := time.Parse(reports.DateLayout, value)
+		if err != nil {
+			return entity.TimesheetRequest{}, invalidParam("date", "should be a date in the format YYYY-MM-DD")
+		}
+		date = parsed
+	}
+
+	if format := c.QueryParam("format"); format != "" && format != "csv" && format != "json" {
+		return entity.TimesheetRequest{}, invalidParam("format", "should be csv or json")
+	}
+
+	userId := session.Id
+	if value := c.QueryParam("userId"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return entity.TimesheetRequest{}, invalidParam("userId", "should be a number")
+		}
+		userId = parsed
+	}
+
+	scope, err := scope(c, entity.PermissionReportRead)
+	if err != nil {
+		return entity.TimesheetRequest{}, err
+	}
+
+	// the service checks the user is a teammate of managers
+	if scope == entity.ScopeOwn {
+		if userId != session.Id {
+			return entity.TimesheetRequest{}, forbidden("user don't have permission to see other timesheets")
+		}
+	} else if c.QueryParam("userId") == "" {
+		return entity.TimesheetRequest{}, invalidParam("userId", "is required")
+	}
+
+	return entity.TimesheetRequest{
+		UserId:   userId,
+		Date:     date,
+		ViewerId: session.Id,
+		Scope:    scope,
+	}, nil
+}
+
+func writeTimesheetCSV(c echo.Context, filename string, timesheets []entity.TimesheetResponse) error {
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Response().WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(c.Response())
+
+	err := w.Write(timesheetCSVHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range timesheets {
+		userId := strconv.Itoa(t.UserId)
+
+		for _, task := range t.Tasks {
+			err = w.Write([]string{
+				userId, t.UserName, t.Date, "task", strconv.Itoa(task.TaskId), task.Title,
+				task.StartedAt, task.EndedAt, task.FinishedAt, strconv.FormatInt(task.DurationSeconds, 10),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, gap := range t.Gaps {
+			err = w.Write([]string{
+				userId, t.UserName, t.Date, "gap", "", "",
+				gap.StartedAt, gap.EndedAt, "", strconv.FormatInt(gap.DurationSeconds, 10),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
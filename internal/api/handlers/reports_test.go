@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReportsTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestReportsTestSuite(t *testing.T) {
+	suite.Run(t, new(ReportsTestSuite))
+}
+
+// otherTechnician isn't in a team of ManagerUser.
+var otherTechnician = entity.User{
+	Name:           "rita",
+	Username:       "ritaReportsHandlers",
+	Password:       "123456",
+	CodeRole:       entity.TechnicianRole,
+	OrganizationId: defaultOrganization,
+}
+
+func (suite *ReportsTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	signUpTechnician(otherTechnician)
+	suite.Require().NoError(DB.Get(&otherTechnician.Id, `SELECT id FROM users WHERE username = ?`, otherTechnician.Username))
+}
+
+func (suite *ReportsTestSuite) TearDownTest() {
+	err := deleteTasks()
+	suite.NoError(err)
+}
+
+func (suite *ReportsTestSuite) TestGetTimesheet() {
+	taskId, err := createTask("test timesheet", "this test should be in timesheet", TechnicianUser.Id)
+	suite.NoError(err)
+
+	err = createWorkLog(taskId, TechnicianUser.Id, "2023-09-18 08:00:00", "2023-09-18 09:30:00")
+	suite.NoError(err)
+
+	err = createWorkLog(taskId, TechnicianUser.Id, "2023-09-18 10:00:00", "2023-09-18 11:00:00")
+	suite.NoError(err)
+
+	cases := map[string]struct {
+		query      string
+		user       entity.User
+		statusCode int
+	}{
+		"1 - Should return 200 - own timesheet": {
+			query:      "date=2023-09-18",
+			user:       TechnicianUser,
+			statusCode: http.StatusOK,
+		},
+		"2 - Should return 403 - other technician timesheet": {
+			query:      fmt.Sprintf("date=2023-09-18&userId=%d", ManagerUser.Id),
+			user:       TechnicianUser,
+			statusCode: http.StatusForbidden,
+		},
+		"3 - Should return 200 - Manager can see technician timesheet": {
+			query:      fmt.Sprintf("date=2023-09-18&userId=%d", TechnicianUser.Id),
+			user:       ManagerUser,
+			statusCode: http.StatusOK,
+		},
+		"4 - Should return 400 - Manager without userId": {
+			query:      "date=2023-09-18",
+			user:       ManagerUser,
+			statusCode: http.StatusBadRequest,
+		},
+		"5 - Should return 400 - invalid date": {
+			query:      "date=18/09/2023",
+			user:       TechnicianUser,
+			statusCode: http.StatusBadRequest,
+		},
+		"6 - Should return 403 - Manager can't see the timesheet of another team": {
+			query:      fmt.Sprintf("date=2023-09-18&userId=%d", otherTechnician.Id),
+			user:       ManagerUser,
+			statusCode: http.StatusForbidden,
+		},
+	}
+
+	keys := make([]string, 0, len(cases))
+	for v := range cases {
+		keys = append(keys, v)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		suite.Run(key, func() {
+			c, rr := createContextAuth(http.MethodGet, "/reports/timesheet?"+cases[key].query, nil, cases[key].user)
+
+			handler := GetTimesheet(ReportsService)
+
+			err := serve(handler, c)
+
+			suite.NoError(err)
+
+			suite.Equal(cases[key].statusCode, rr.Code, rr.Body)
+
+			if rr.Code == http.StatusOK {
+				timesheet := entity.TimesheetResponse{}
+				suite.NoError(json.Unmarshal(rr.Body.Bytes(), &timesheet))
+				suite.Equal(int64(9000), timesheet.TotalSeconds)
+				suite.Equal(int64(1800), timesheet.GapSeconds)
+				suite.Len(timesheet.Tasks, 1)
+				suite.Len(timesheet.Gaps, 1)
+			}
+		})
+	}
+}
+
+func (suite *ReportsTestSuite) TestGetTimesheetCSV() {
+	taskId, err := createTask("test timesheet csv", "this test should be in csv", TechnicianUser.Id)
+	suite.NoError(err)
+
+	err = createWorkLog(taskId, TechnicianUser.Id, "2023-09-19 08:00:00", "2023-09-19 09:00:00")
+	suite.NoError(err)
+
+	c, rr := createContextAuth(http.MethodGet, "/reports/timesheet/weekly?date=2023-09-19&format=csv", nil, TechnicianUser)
+
+	err = serve(GetWeeklyTimesheet(ReportsService), c)
+	suite.NoError(err)
+
+	suite.Equal(http.StatusOK, rr.Code, rr.Body)
+	suite.True(strings.HasPrefix(rr.Header().Get("Content-Type"), "text/csv"))
+
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	suite.Len(lines, 2)
+	suite.Contains(lines[1], "test timesheet csv")
+	suite.Contains(lines[1], "3600")
+}
+
+func createWorkLog(taskId int64, userId int, startedAt, endedAt string) error {
+	_, err := DB.Exec(`INSERT INTO work_logs (task_id, user_id, started_at, ended_at) VALUES(?, ?, ?, ?)`, taskId, userId, startedAt, endedAt)
+
+	return err
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/domain/sso"
+	"github.com/lucas-simao/api-tasks/internal/gateway/oidc"
+)
+
+// oidcFlowCookie keeps the flow of a sign in until the provider redirects the
+// browser to the callback.
+const oidcFlowCookie = "oidc_flow"
+
+var ErrOIDCDisabled = apperror.NotFound("oidc_disabled", "single sign-on isn't configured")
+
+// SignInOIDC redirects the user to the identity provider, the callback
+// returns the token.
+func SignInOIDC(s sso.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if s == nil {
+			return ErrOIDCDisabled
+		}
+
+		url, flow, err := s.Start(c.Request().Context())
+		if err != nil {
+			return fmt.Errorf("error to start sign in: %w", err)
+		}
+
+		// the callback is below the path of this route, whatever the version
+		c.SetCookie(&http.Cookie{
+			Name:     oidcFlowCookie,
+			Value:    flow,
+			Path:     c.Request().URL.Path,
+			MaxAge:   int(sso.FlowTTL.Seconds()),
+			HttpOnly: true,
+			Secure:   c.Scheme() == "https",
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		return c.Redirect(http.StatusFound, url)
+	}
+}
+
+func OIDCCallback(s sso.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if s == nil {
+			return ErrOIDCDisabled
+		}
+
+		// the user cancelled or the provider refused the sign in
+		if e := c.QueryParam("error"); e != "" {
+			return oidc.ErrSignInFailed.Withf("sign in with the identity provider failed: %s", e)
+		}
+
+		code := c.QueryParam("code")
+		if code == "" {
+			return invalidParam("code", "is required")
+		}
+
+		cookie, err := c.Cookie(oidcFlowCookie)
+		if err != nil {
+			return sso.ErrInvalidFlow
+		}
+
+		// a flow is used once
+		c.SetCookie(&http.Cookie{
+			Name:     oidcFlowCookie,
+			Path:     strings.TrimSuffix(c.Request().URL.Path, "/callback"),
+			MaxAge:   -1,
+			HttpOnly: true,
+		})
+
+		response, err := s.SignIn(c.Request().Context(), cookie.Value, code, c.QueryParam("state"))
+		if err != nil {
+			return fmt.Errorf("error to sign in: %w", err)
+		}
+
+		return c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/domain/stats"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+var (
+	statsDefaultPeriodDays = 30
+	statsMaxPeriodDays     = 366
+	statsDefaultTop        = 5
+	statsMaxTop            = 50
+)
+
+func GetStats(s stats.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		if err := require(c, entity.PermissionStatsRead); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+		if value := c.QueryParam("to"); value != "" {
+			parsed, err := time.Parse(stats.DateLayout, value)
+			if err != nil {
+				return invalidParam("to", "should be a date in the format YYYY-MM-DD")
+			}
+			to = parsed
+		}
+
+		from := to.AddDate(0, 0, 1-statsDefaultPeriodDays)
+
+		if value := c.QueryParam("from"); value != "" {
+			parsed, err := time.Parse(stats.DateLayout, value)
+			if err != nil {
+				return invalidParam("from", "should be a date in the format YYYY-MM-DD")
+			}
+			from = parsed
+		}
+
+		// the period is inclusive for clients, but [from, to) for the queries
+		to = to.AddDate(0, 0, 1)
+
+		if !from.Before(to) || to.Sub(from) > time.Duration(statsMaxPeriodDays)*24*time.Hour {
+			return invalidParam("from", fmt.Sprintf("should make a period between 1 and %d days", statsMaxPeriodDays))
+		}
+
+		top := statsDefaultTop
+
+		if value := c.QueryParam("top"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 || parsed > statsMaxTop {
+				return invalidParam("top", fmt.Sprintf("should be a number between 1 and %d", statsMaxTop))
+			}
+			top = parsed
+		}
+
+		response, err := s.GetStats(ctx, GetAuthSession(c).Id, from, to, top)
+		if err != nil {
+			return fmt.Errorf("error to get stats: %w", err)
+		}
+
+		return c.JSON(http.StatusOK, response)
+	}
+}
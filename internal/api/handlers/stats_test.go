@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/tenant"
+	"github.com/stretchr/testify/suite"
+)
+
+type StatsTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestStatsTestSuite(t *testing.T) {
+	suite.Run(t, new(StatsTestSuite))
+}
+
+func (suite *StatsTestSuite) SetupSuite() {
+	suite.ctx = tenant.WithOrganization(context.Background(), defaultOrganization)
+}
+
+func (suite *StatsTestSuite) TearDownTest() {
+	err := deleteTasks()
+	suite.NoError(err)
+	StatsService.Invalidate()
+}
+
+func (suite *StatsTestSuite) TestGetStats() {
+	cases := map[string]struct {
+		query      string
+		user       entity.User
+		statusCode int
+	}{
+		"1 - Should return 200": {
+			user:       ManagerUser,
+			statusCode: http.StatusOK,
+		},
+		"2 - Should return 403 - Technician can't see stats": {
+			user:       TechnicianUser,
+			statusCode: http.StatusForbidden,
+		},
+		"3 - Should return 400 - from after to": {
+			query:      "from=2023-09-20&to=2023-09-10",
+			user:       ManagerUser,
+			statusCode: http.StatusBadRequest,
+		},
+		"4 - Should return 400 - invalid top": {
+			query:      "top=0",
+			user:       ManagerUser,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	keys := make([]string, 0, len(cases))
+	for v := range cases {
+		keys = append(keys, v)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		suite.Run(key, func() {
+			c, rr := createContextAuth(http.MethodGet, "/stats?"+cases[key].query, nil, cases[key].user)
+
+			handler := GetStats(StatsService)
+
+			err := serve(handler, c)
+
+			suite.NoError(err)
+
+			suite.Equal(cases[key].statusCode, rr.Code, rr.Body)
+		})
+	}
+}
+
+func (suite *StatsTestSuite) TestGetStatsInvalidatedOnChange() {
+	before := suite.getStats()
+
+	_, err := TasksService.CreateTask(suite.ctx, entity.TaskRequest{
+		Title:       "test stats",
+		Description: "this test should change stats",
+		UserId:      TechnicianUser.Id,
+	})
+	suite.NoError(err)
+
+	after := suite.getStats()
+
+	suite.Equal(countCreated(before)+1, countCreated(after))
+}
+
+func (suite *StatsTestSuite) getStats() entity.StatsResponse {
+	c, rr := createContextAuth(http.MethodGet, "/stats", strings.NewReader(""), ManagerUser)
+
+	err := serve(GetStats(StatsService), c)
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, rr.Code, rr.Body)
+
+	stats := entity.StatsResponse{}
+	suite.NoError(json.Unmarshal(rr.Body.Bytes(), &stats))
+
+	return stats
+}
+
+func countCreated(stats entity.StatsResponse) int {
+	var total int
+	for _, d := range stats.TasksPerDay {
+		total += d.Created
+	}
+
+	return total
+}
@@ -1,15 +1,12 @@
 package handlers
 
 import (
-	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/labstack/echo/v4"
 	"github.com/lucas-simao/api-tasks/internal/domain/tasks"
 	"github.com/lucas-simao/api-tasks/internal/entity"
-	"github.com/lucas-simao/api-tasks/internal/repository"
 )
 
 func CreateTask(s tasks.Service) echo.HandlerFunc {
@@ -18,31 +15,23 @@ func CreateTask(s tasks.Service) echo.HandlerFunc {
 
 		p := entity.TaskRequest{}
 
-		err := c.Bind(&p)
+		err := bindRequest(c, &p)
 		if err != nil {
-			result.Message = fmt.Sprintf("error to bind body: %v", err)
-			return c.JSON(http.StatusBadRequest, result)
-		}
-
-		err = p.Validate()
-		if err != nil {
-			result.Message = fmt.Sprintf("error to validate: %v", err)
-			return c.JSON(http.StatusBadRequest, result)
+			return err
 		}
 
 		session := GetAuthSession(c)
 
 		p.UserId = session.Id
+		p.CodeRole = session.CodeRole
 
-		if session.CodeRole != entity.TechnicianRole {
-			result.Message = "user unauthorized to create tasks"
-			return c.JSON(http.StatusForbidden, result)
+		if err := require(c, entity.PermissionTaskCreate); err != nil {
+			return err
 		}
 
 		id, err := s.CreateTask(ctx, p)
 		if err != nil {
-			result.Message = fmt.Sprintf("error to create task: %v", err)
-			return c.JSON(http.StatusInternalServerError, result)
+			return fmt.Errorf("error to create task: %w", err)
 		}
 
 		return c.JSON(http.StatusCreated, map[string]int64{
@@ -58,14 +47,12 @@ func GetTasks(s tasks.Service) echo.HandlerFunc {
 		session := GetAuthSession(c)
 
 		if session.Id == 0 {
-			result.Message = "user unauthorized"
-			return c.JSON(http.StatusBadRequest, result)
+			return ErrUnauthorized
 		}
 
-		tasks, err := s.GetTasks(ctx, session.Id, session.CodeRole)
+		tasks, err := s.GetTasks(ctx, session.Id, GetPermissions(c).Scope(entity.PermissionTaskRead))
 		if err != nil {
-			result.Message = fmt.Sprintf("error to get tasks: %v", err)
-			return c.JSON(http.StatusInternalServerError, result)
+			return fmt.Errorf("error to get tasks: %w", err)
 		}
 
 		var httpStatus int = http.StatusNoContent
@@ -74,7 +61,7 @@ func GetTasks(s tasks.Service) echo.HandlerFunc {
 			httpStatus = http.StatusOK
 		}
 
-		return c.JSON(httpStatus, tasks)
+		return c.JSON(httpStatus, presenter(c).Tasks(tasks))
 	}
 }
 
@@ -82,30 +69,23 @@ func GetTaskById(s tasks.Service) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 
-		taskId, err := strconv.Atoi(c.Param("id"))
+		taskId, err := parseIdParam(c)
 		if err != nil {
-			result.Message = "error to parse id"
-			return c.JSON(http.StatusBadRequest, result)
+			return err
 		}
 
 		session := GetAuthSession(c)
 
 		if session.Id == 0 {
-			result.Message = "user unauthorized"
-			return c.JSON(http.StatusBadRequest, result)
+			return ErrUnauthorized
 		}
 
-		task, err := s.GetTaskById(ctx, taskId, session.Id, session.CodeRole)
+		task, err := s.GetTaskById(ctx, taskId, session.Id, GetPermissions(c).Scope(entity.PermissionTaskRead))
 		if err != nil {
-			if errors.Is(err, repository.ErrNoTaskInResult) {
-				return c.JSON(http.StatusNoContent, nil)
-			}
-
-			result.Message = fmt.Sprintf("error to get task by id: %v", err)
-			return c.JSON(http.StatusInternalServerError, result)
+			return fmt.Errorf("error to get task by id: %w", err)
 		}
 
-		return c.JSON(http.StatusOK, task)
+		return c.JSON(http.StatusOK, presenter(c).Task(task))
 	}
 }
 
@@ -113,30 +93,24 @@ func DeleteTaskById(s tasks.Service) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 
-		taskId, err := strconv.Atoi(c.Param("id"))
+		taskId, err := parseIdParam(c)
 		if err != nil {
-			result.Message = "error to parse id"
-			return c.JSON(http.StatusBadRequest, result)
+			return err
 		}
 
-		session := GetAuthSession(c)
-
-		if session.CodeRole != entity.ManagerRole {
-			result.Message = "user don't have permission to delete tasks"
-			return c.JSON(http.StatusBadRequest, result)
+		scope, err := scope(c, entity.PermissionTaskDelete)
+		if err != nil {
+			return err
 		}
 
-		err = s.DeleteTaskById(ctx, taskId, session.Id)
+		err = s.DeleteTaskById(ctx, taskId, GetAuthSession(c).Id, scope)
 		if err != nil {
-			if errors.Is(err, repository.ErrNoTaskInResult) {
-				return c.JSON(http.StatusNoContent, nil)
-			}
-			result.Message = fmt.Sprintf("error to delete task: %v", err)
-			return c.JSON(http.StatusInternalServerError, result)
+			return fmt.Errorf("error to delete task: %w", err)
 		}
 
-		result.Message = fmt.Sprintf("task %d deleted", taskId)
-		return c.JSON(http.StatusOK, result)
+		return c.JSON(http.StatusOK, ResultMessage{
+			Message: fmt.Sprintf("task %d deleted", taskId),
+		})
 	}
 }
 
@@ -146,22 +120,14 @@ func UpdateTaskById(s tasks.Service) echo.HandlerFunc {
 
 		p := entity.TaskUpdateRequest{}
 
-		err := c.Bind(&p)
+		err := bindRequest(c, &p)
 		if err != nil {
-			result.Message = fmt.Sprintf("error to bind body: %v", err)
-			return c.JSON(http.StatusBadRequest, result)
+			return err
 		}
 
-		err = p.Validate()
+		taskId, err := parseIdParam(c)
 		if err != nil {
-			result.Message = fmt.Sprintf("error to validate: %v", err)
-			return c.JSON(http.StatusBadRequest, result)
-		}
-
-		taskId, err := strconv.Atoi(c.Param("id"))
-		if err != nil {
-			result.Message = "error to parse id"
-			return c.JSON(http.StatusBadRequest, result)
+			return err
 		}
 
 		session := GetAuthSession(c)
@@ -169,21 +135,16 @@ func UpdateTaskById(s tasks.Service) echo.HandlerFunc {
 		p.Id = taskId
 		p.UserId = session.Id
 
-		if session.CodeRole != entity.TechnicianRole {
-			result.Message = "user don't have permission to update tasks"
-			return c.JSON(http.StatusBadRequest, result)
+		if err := require(c, entity.PermissionTaskUpdateOwn); err != nil {
+			return err
 		}
 
 		task, err := s.UpdateTaskById(ctx, p)
 		if err != nil {
-			if errors.Is(err, repository.ErrNoTaskInResult) {
-				return c.JSON(http.StatusNoContent, nil)
-			}
-			result.Message = fmt.Sprintf("error to update task: %v", err)
-			return c.JSON(http.StatusInternalServerError, result)
+			return fmt.Errorf("error to update task: %w", err)
 		}
 
-		return c.JSON(http.StatusOK, task)
+		return c.JSON(http.StatusOK, presenter(c).Task(task))
 	}
 }
 
@@ -191,28 +152,22 @@ func FinishTaskById(s tasks.Service) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 
-		taskId, err := strconv.Atoi(c.Param("id"))
+		taskId, err := parseIdParam(c)
 		if err != nil {
-			result.Message = "error to parse id"
-			return c.JSON(http.StatusBadRequest, result)
+			return err
 		}
 
-		session := GetAuthSession(c)
-
-		if session.CodeRole != entity.TechnicianRole {
-			result.Message = "user don't have permission to finish tasks"
-			return c.JSON(http.StatusBadRequest, result)
+		if err := require(c, entity.PermissionTaskUpdateOwn); err != nil {
+			return err
 		}
 
-		task, err := s.FinishTaskById(ctx, taskId, session.Id)
+		session := GetAuthSession(c)
+
+		task, err := s.FinishTaskById(ctx, taskId, session.Id, session.CodeRole)
 		if err != nil {
-			if errors.Is(err, repository.ErrNoTaskInResult) {
-				return c.JSON(http.StatusNoContent, nil)
-			}
-			result.Message = fmt.Sprintf("error to finish task: %v", err)
-			return c.JSON(http.StatusInternalServerError, result)
+			return fmt.Errorf("error to finish task: %w", err)
 		}
 
-		return c.JSON(http.StatusOK, task)
+		return c.JSON(http.StatusOK, presenter(c).Task(task))
 	}
 }
@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"sort"
 	"strconv"
 	"strings"
 	"testing"
 
+	"github.com/lucas-simao/api-tasks/internal/api/dto"
+	v2 "github.com/lucas-simao/api-tasks/internal/api/dto/v2"
 	"github.com/lucas-simao/api-tasks/internal/entity"
 	"github.com/stretchr/testify/suite"
 )
@@ -77,7 +80,7 @@ func (suite *TasksTestSuite) TestCreateTask() {
 
 			handler := CreateTask(TasksService)
 
-			err := handler(c)
+			err := serve(handler, c)
 
 			suite.NoError(err)
 
@@ -118,7 +121,7 @@ func (suite *TasksTestSuite) TestGetTasks() {
 
 			handler := GetTasks(TasksService)
 
-			err := handler(c)
+			err := serve(handler, c)
 
 			suite.NoError(err)
 
@@ -146,10 +149,10 @@ func (suite *TasksTestSuite) TestGetTaskById() {
 			taskId:     int(taskId),
 			statusCode: http.StatusOK,
 		},
-		"3 - Should return 204 - task not exist": {
+		"3 - Should return 404 - task not exist": {
 			user:       TechnicianUser,
 			taskId:     0,
-			statusCode: http.StatusNoContent,
+			statusCode: http.StatusNotFound,
 		},
 	}
 
@@ -170,7 +173,7 @@ func (suite *TasksTestSuite) TestGetTaskById() {
 
 			handler := GetTaskById(TasksService)
 
-			err := handler(c)
+			err := serve(handler, c)
 
 			suite.NoError(err)
 
@@ -179,6 +182,55 @@ func (suite *TasksTestSuite) TestGetTaskById() {
 	}
 }
 
+func (suite *TasksTestSuite) TestGetTaskByIdWithPresenter() {
+	taskId, err := createTask("test presenter", "this test should return the body of the version", TechnicianUser.Id)
+	suite.NoError(err)
+
+	cases := map[string]struct {
+		presenter dto.Presenter
+		deletedBy string
+	}{
+		"1 - Should return v1 body without presenter": {
+			deletedBy: `{"id":0,"name":"","date":""}`,
+		},
+		"2 - Should return v2 body with null": {
+			presenter: v2.Presenter{},
+			deletedBy: `null`,
+		},
+	}
+
+	keys := make([]string, 0, len(cases))
+	for v := range cases {
+		keys = append(keys, v)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		suite.Run(key, func() {
+			c, rr := createContextAuth(http.MethodGet, "/tasks/:id", nil, TechnicianUser)
+
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(int(taskId)))
+
+			handler := GetTaskById(TasksService)
+			if cases[key].presenter != nil {
+				handler = UsePresenter(cases[key].presenter)(handler)
+			}
+
+			err := serve(handler, c)
+
+			suite.NoError(err)
+
+			suite.Equal(http.StatusOK, rr.Code, rr.Body)
+
+			var body map[string]json.RawMessage
+			suite.NoError(json.Unmarshal(rr.Body.Bytes(), &body))
+			suite.JSONEq(cases[key].deletedBy, string(body["deletedBy"]))
+		})
+	}
+}
+
 func (suite *TasksTestSuite) TestDeleteTaskById() {
 	taskId, err := createTask("test delete task", "this test should delete it", TechnicianUser.Id)
 	suite.NoError(err)
@@ -193,20 +245,20 @@ func (suite *TasksTestSuite) TestDeleteTaskById() {
 			taskId:     int(taskId),
 			statusCode: http.StatusOK,
 		},
-		"2 - Should return 400 - Technician can't delete tasks": {
+		"2 - Should return 403 - Technician can't delete tasks": {
 			user:       TechnicianUser,
 			taskId:     int(taskId),
-			statusCode: http.StatusBadRequest,
+			statusCode: http.StatusForbidden,
 		},
-		"3 - Should return 204 - task has already been deleted": {
+		"3 - Should return 404 - task has already been deleted": {
 			user:       ManagerUser,
 			taskId:     int(taskId),
-			statusCode: http.StatusNoContent,
+			statusCode: http.StatusNotFound,
 		},
-		"4 - Should return 204 - task not exist": {
+		"4 - Should return 404 - task not exist": {
 			user:       ManagerUser,
 			taskId:     0,
-			statusCode: http.StatusNoContent,
+			statusCode: http.StatusNotFound,
 		},
 	}
 
@@ -227,7 +279,7 @@ func (suite *TasksTestSuite) TestDeleteTaskById() {
 
 			handler := DeleteTaskById(TasksService)
 
-			err := handler(c)
+			err := serve(handler, c)
 
 			suite.NoError(err)
 
@@ -264,11 +316,11 @@ func (suite *TasksTestSuite) TestUpdateTaskById() {
 			body:       `{ "title": "test", "description": ""}`,
 			statusCode: http.StatusBadRequest,
 		},
-		"4 - Should return 204 - task not exist": {
+		"4 - Should return 404 - task not exist": {
 			user:       TechnicianUser,
 			taskId:     0,
 			body:       `{ "title": "test", "description": "test test"}`,
-			statusCode: http.StatusNoContent,
+			statusCode: http.StatusNotFound,
 		},
 	}
 
@@ -289,7 +341,7 @@ func (suite *TasksTestSuite) TestUpdateTaskById() {
 
 			handler := UpdateTaskById(TasksService)
 
-			err := handler(c)
+			err := serve(handler, c)
 
 			suite.NoError(err)
 
@@ -312,15 +364,15 @@ func (suite *TasksTestSuite) TestFinishTaskById() {
 			taskId:     int(taskId),
 			statusCode: http.StatusOK,
 		},
-		"2 - Should return 204 - task not exist": {
+		"2 - Should return 404 - task not exist": {
 			user:       TechnicianUser,
 			taskId:     0,
-			statusCode: http.StatusNoContent,
+			statusCode: http.StatusNotFound,
 		},
-		"3 - Should return 400 - without permission": {
+		"3 - Should return 403 - without permission": {
 			user:       ManagerUser,
 			taskId:     int(taskId),
-			statusCode: http.StatusBadRequest,
+			statusCode: http.StatusForbidden,
 		},
 	}
 
@@ -341,7 +393,7 @@ func (suite *TasksTestSuite) TestFinishTaskById() {
 
 			handler := FinishTaskById(TasksService)
 
-			err := handler(c)
+			err := serve(handler, c)
 
 			suite.NoError(err)
 
@@ -351,7 +403,7 @@ func (suite *TasksTestSuite) TestFinishTaskById() {
 }
 
 func createTask(title, description string, userId int) (int64, error) {
-	result, err := DB.Exec(`INSERT INTO tasks (title, description, created_by_user_id) VALUES(?, ?, ?)`, title, description, userId)
+	result, err := DB.Exec(`INSERT INTO tasks (title, description, created_by_user_id, organization_id) VALUES(?, ?, ?, ?)`, title, description, userId, defaultOrganization)
 	if err != nil {
 		return 0, err
 	}
@@ -360,7 +412,12 @@ func createTask(title, description string, userId int) (int64, error) {
 }
 
 func deleteTasks() error {
-	_, err := DB.Exec(`DELETE FROM tasks`)
+	_, err := DB.Exec(`DELETE FROM work_logs`)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.Exec(`DELETE FROM tasks`)
 	if err != nil {
 		return err
 	}
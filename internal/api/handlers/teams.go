@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/domain/teams"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+func CreateTeam(s teams.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		p := entity.TeamRequest{}
+
+		err := bindRequest(c, &p)
+		if err != nil {
+			return err
+		}
+
+		session := GetAuthSession(c)
+
+		p.UserId = session.Id
+
+		if err := require(c, entity.PermissionTeamManage); err != nil {
+			return err
+		}
+
+		id, err := s.CreateTeam(ctx, p)
+		if err != nil {
+			return fmt.Errorf("error to create team: %w", err)
+		}
+
+		return c.JSON(http.StatusCreated, map[string]int64{
+			"id": id,
+		})
+	}
+}
+
+func GetTeams(s teams.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		session := GetAuthSession(c)
+
+		if session.Id == 0 {
+			return ErrUnauthorized
+		}
+
+		teams, err := s.GetTeams(ctx, session.Id)
+		if err != nil {
+			return fmt.Errorf("error to get teams: %w", err)
+		}
+
+		return c.JSON(http.StatusOK, teams)
+	}
+}
+
+func AddTeamMember(s teams.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		p, err := parseTeamMemberParams(c)
+		if err != nil {
+			return err
+		}
+
+		err = s.AddTeamMember(ctx, p)
+		if err != nil {
+			return fmt.Errorf("error to add team member: %w", err)
+		}
+
+		return c.JSON(http.StatusOK, ResultMessage{
+			Message: fmt.Sprintf("user %d added to team %d", p.UserId, p.TeamId),
+		})
+	}
+}
+
+func RemoveTeamMember(s teams.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		p, err := parseTeamMemberParams(c)
+		if err != nil {
+			return err
+		}
+
+		err = s.RemoveTeamMember(ctx, p)
+		if err != nil {
+			return fmt.Errorf("error to remove team member: %w", err)
+		}
+
+		return c.JSON(http.StatusOK, ResultMessage{
+			Message: fmt.Sprintf("user %d removed from team %d", p.UserId, p.TeamId),
+		})
+	}
+}
+
+// parseTeamMemberParams reads the team and the user of the path, only members
+// of the team with the permission to manage teams change its members.
+func parseTeamMemberParams(c echo.Context) (entity.TeamMemberRequest, error) {
+	teamId, err := parseIdParam(c)
+	if err != nil {
+		return entity.TeamMemberRequest{}, err
+	}
+
+	userId, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		return entity.TeamMemberRequest{}, invalidParam("userId", "should be a number")
+	}
+
+	if err := require(c, entity.PermissionTeamManage); err != nil {
+		return entity.TeamMemberRequest{}, err
+	}
+
+	session := GetAuthSession(c)
+
+	return entity.TeamMemberRequest{TeamId: teamId, UserId: userId, ManagerId: session.Id}, nil
+}
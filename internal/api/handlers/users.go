@@ -1,14 +1,12 @@
 package handlers
 
 import (
-	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"github.com/lucas-simao/api-tasks/internal/domain/users"
 	"github.com/lucas-simao/api-tasks/internal/entity"
-	"github.com/lucas-simao/api-tasks/internal/repository"
 )
 
 func SignUp(u users.Service) echo.HandlerFunc {
@@ -17,31 +15,19 @@ func SignUp(u users.Service) echo.HandlerFunc {
 
 		p := entity.SignUpRequest{}
 
-		err := c.Bind(&p)
+		err := bindRequest(c, &p)
 		if err != nil {
-			result.Message = fmt.Sprintf("error to bind body: %v", err)
-			return c.JSON(http.StatusBadRequest, result)
-		}
-
-		err = p.Validate()
-		if err != nil {
-			result.Message = fmt.Sprintf("error to validate: %v", err)
-			return c.JSON(http.StatusBadRequest, result)
+			return err
 		}
 
 		err = u.SignUp(ctx, p)
 		if err != nil {
-			if errors.Is(err, repository.ErrUsernameUnavailable) {
-				result.Message = fmt.Sprintf("%v", err)
-				return c.JSON(http.StatusBadRequest, result)
-			}
-
-			result.Message = fmt.Sprintf("error to sign up: %v", err)
-			return c.JSON(http.StatusInternalServerError, result)
+			return fmt.Errorf("error to sign up: %w", err)
 		}
 
-		result.Message = "success to sign up"
-		return c.JSON(http.StatusCreated, result)
+		return c.JSON(http.StatusCreated, ResultMessage{
+			Message: "success to sign up",
+		})
 	}
 }
 
@@ -51,36 +37,38 @@ func SignIn(u users.Service) echo.HandlerFunc {
 
 		p := entity.SignInRequest{}
 
-		err := c.Bind(&p)
+		err := bindRequest(c, &p)
+		if err != nil {
+			return err
+		}
+
+		response, err := u.SignIn(ctx, p)
 		if err != nil {
-			result.Message = fmt.Sprintf("error to bind body: %v", err)
-			return c.JSON(http.StatusBadRequest, result)
+			return fmt.Errorf("error to sign in: %w", err)
 		}
 
-		err = p.Validate()
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// SignInMFA finishes a sign in with the MFA token returned by SignIn and a
+// code.
+func SignInMFA(u users.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		p := entity.MFASignInRequest{}
+
+		err := bindRequest(c, &p)
 		if err != nil {
-			result.Message = fmt.Sprintf("error to validate: %v", err)
-			return c.JSON(http.StatusBadRequest, result)
+			return err
 		}
 
-		token, err := u.SignIn(ctx, p)
+		response, err := u.SignInMFA(ctx, p)
 		if err != nil {
-			if errors.Is(err, users.ErrWrongPassword) {
-				result.Message = fmt.Sprintf("%v", err)
-				return c.JSON(http.StatusBadRequest, result)
-			}
-
-			if errors.Is(err, users.ErrUserWithoutValidRole) {
-				result.Message = fmt.Sprintf("%v", err)
-				return c.JSON(http.StatusForbidden, result)
-			}
-
-			result.Message = fmt.Sprintf("error to sign in: %v", err)
-			return c.JSON(http.StatusInternalServerError, result)
+			return fmt.Errorf("error to sign in: %w", err)
 		}
 
-		return c.JSON(http.StatusOK, map[string]string{
-			"token": token,
-		})
+		return c.JSON(http.StatusOK, response)
 	}
 }
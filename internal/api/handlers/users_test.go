@@ -47,7 +47,7 @@ func (suite *UsersTestSuite) TestSignUp() {
 		statusCode int
 	}{
 		"1 - Should return 201": {
-			body:       `{ "name": "lucas", "username": "lsimao", "password": "123456"}`,
+			body:       `{ "name": "lucas", "username": "lsimao", "password": "123456", "organization": "acme"}`,
 			statusCode: http.StatusCreated,
 		},
 		"2 - Should return 400 - empty name": {
@@ -66,6 +66,18 @@ func (suite *UsersTestSuite) TestSignUp() {
 			body:       `{ "name": "lucas", "username": "lsimao", "password": ""}`,
 			statusCode: http.StatusBadRequest,
 		},
+		"6 - Should return 400 - without organization nor invite code": {
+			body:       `{ "name": "maria", "username": "maria", "password": "123456"}`,
+			statusCode: http.StatusBadRequest,
+		},
+		"7 - Should return 400 - organization and invite code": {
+			body:       `{ "name": "maria", "username": "maria", "password": "123456", "organization": "acme", "inviteCode": "acme"}`,
+			statusCode: http.StatusBadRequest,
+		},
+		"8 - Should return 400 - invalid invite code": {
+			body:       `{ "name": "maria", "username": "maria", "password": "123456", "inviteCode": "invalid"}`,
+			statusCode: http.StatusBadRequest,
+		},
 	}
 
 	keys := make([]string, 0, len(cases))
@@ -90,7 +102,7 @@ func (suite *UsersTestSuite) TestSignUp() {
 func (suite *UsersTestSuite) TestSignIn() {
 	var username = "lucasSimao"
 
-	rr, err := singUp(fmt.Sprintf(`{ "name": "Lucas S Simao", "username": "%s", "password": "123456"}`, username))
+	rr, err := singUp(fmt.Sprintf(`{ "name": "Lucas S Simao", "username": "%s", "password": "123456", "organization": "Simao"}`, username))
 
 	suite.NoError(err)
 
@@ -101,6 +113,10 @@ func (suite *UsersTestSuite) TestSignIn() {
 	err = DB.Get(&userId, `SELECT id FROM users WHERE username = ?`, username)
 	suite.NoError(err)
 
+	// users creating an organization are its managers, visitors can't sign in
+	_, err = DB.Exec(`UPDATE users SET user_role_id = 1 WHERE id = ?`, userId)
+	suite.NoError(err)
+
 	cases := map[string]struct {
 		body       string
 		changeRole bool
@@ -118,9 +134,9 @@ func (suite *UsersTestSuite) TestSignIn() {
 			body:       `{ "username": "lucasSimao", "password": ""}`,
 			statusCode: http.StatusBadRequest,
 		},
-		"4 - Should return 400 - wrong password": {
+		"4 - Should return 401 - wrong password": {
 			body:       `{ "username": "lucasSimao", "password": "12345"}`,
-			statusCode: http.StatusBadRequest,
+			statusCode: http.StatusUnauthorized,
 		},
 		"5 - Should return 200": {
 			body:       `{ "username": "lucasSimao", "password": "123456"} `,
@@ -147,7 +163,7 @@ func (suite *UsersTestSuite) TestSignIn() {
 				suite.NoError(err)
 			}
 
-			err := handler(c)
+			err := serve(handler, c)
 
 			suite.NoError(err)
 
@@ -156,12 +172,47 @@ func (suite *UsersTestSuite) TestSignIn() {
 	}
 }
 
+func (suite *UsersTestSuite) TestSignUpWithInviteCode() {
+	rr, err := singUp(`{ "name": "Ana", "username": "anaInvite", "password": "123456", "organization": "Invite"}`)
+	suite.NoError(err)
+	suite.Equal(http.StatusCreated, rr.Code, rr.Body)
+
+	var manager struct {
+		OrganizationId int    `db:"organization_id"`
+		InviteCode     string `db:"invite_code"`
+		RoleId         int    `db:"user_role_id"`
+	}
+
+	err = DB.Get(&manager, `
+		SELECT u.organization_id, o.invite_code, u.user_role_id
+		FROM users u
+		INNER JOIN organizations o ON o.id = u.organization_id
+		WHERE u.username = 'anaInvite'`)
+	suite.NoError(err)
+	suite.NotEqual(defaultOrganization, manager.OrganizationId)
+	suite.Equal(2, manager.RoleId, "should be manager of the organization created")
+
+	rr, err = singUp(fmt.Sprintf(`{ "name": "Bia", "username": "biaInvite", "password": "123456", "inviteCode": "%s"}`, manager.InviteCode))
+	suite.NoError(err)
+	suite.Equal(http.StatusCreated, rr.Code, rr.Body)
+
+	var technician struct {
+		OrganizationId int `db:"organization_id"`
+		RoleId         int `db:"user_role_id"`
+	}
+
+	err = DB.Get(&technician, `SELECT organization_id, user_role_id FROM users WHERE username = 'biaInvite'`)
+	suite.NoError(err)
+	suite.Equal(manager.OrganizationId, technician.OrganizationId)
+	suite.Equal(3, technician.RoleId, "should join as technician")
+}
+
 func singUp(payload string) (*httptest.ResponseRecorder, error) {
 	c, rr := createContext(http.MethodPost, "/sign-up", strings.NewReader(payload))
 
 	handler := SignUp(UsersService)
 
-	err := handler(c)
+	err := serve(handler, c)
 
 	return rr, err
 }
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/api/dto"
+	v1 "github.com/lucas-simao/api-tasks/internal/api/dto/v1"
+)
+
+const presenterKey = "presenter"
+
+// UsePresenter makes the handlers of a group answer with the bodies of one api
+// version, handlers without it answer like v1.
+func UsePresenter(p dto.Presenter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(presenterKey, p)
+			return next(c)
+		}
+	}
+}
+
+func presenter(c echo.Context) dto.Presenter {
+	if p, ok := c.Get(presenterKey).(dto.Presenter); ok {
+		return p
+	}
+	return v1.Presenter{}
+}
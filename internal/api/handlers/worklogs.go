@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/domain/tasks"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+func StartTimer(s tasks.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		taskId, err := parseIdParam(c)
+		if err != nil {
+			return err
+		}
+
+		if err := require(c, entity.PermissionWorkLogCreateOwn); err != nil {
+			return err
+		}
+
+		workLog, err := s.StartTimer(ctx, taskId, GetAuthSession(c).Id)
+		if err != nil {
+			return fmt.Errorf("error to start timer: %w", err)
+		}
+
+		return c.JSON(http.StatusCreated, presenter(c).WorkLog(workLog))
+	}
+}
+
+func StopTimer(s tasks.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		p := entity.TimerStopRequest{}
+
+		err := bindRequest(c, &p)
+		if err != nil {
+			return err
+		}
+
+		taskId, err := parseIdParam(c)
+		if err != nil {
+			return err
+		}
+
+		session := GetAuthSession(c)
+
+		p.TaskId = taskId
+		p.UserId = session.Id
+
+		if err := require(c, entity.PermissionWorkLogCreateOwn); err != nil {
+			return err
+		}
+
+		workLog, err := s.StopTimer(ctx, p)
+		if err != nil {
+			return fmt.Errorf("error to stop timer: %w", err)
+		}
+
+		return c.JSON(http.StatusOK, presenter(c).WorkLog(workLog))
+	}
+}
+
+func CreateWorkLog(s tasks.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		p := entity.WorkLogRequest{}
+
+		err := bindRequest(c, &p)
+		if err != nil {
+			return err
+		}
+
+		taskId, err := parseIdParam(c)
+		if err != nil {
+			return err
+		}
+
+		session := GetAuthSession(c)
+
+		p.TaskId = taskId
+		p.UserId = session.Id
+
+		if err := require(c, entity.PermissionWorkLogCreateOwn); err != nil {
+			return err
+		}
+
+		workLog, err := s.CreateWorkLog(ctx, p)
+		if err != nil {
+			return fmt.Errorf("error to create work log: %w", err)
+		}
+
+		return c.JSON(http.StatusCreated, presenter(c).WorkLog(workLog))
+	}
+}
+
+func GetWorkLogs(s tasks.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		taskId, err := parseIdParam(c)
+		if err != nil {
+			return err
+		}
+
+		session := GetAuthSession(c)
+
+		if session.Id == 0 {
+			return ErrUnauthorized
+		}
+
+		workLogs, err := s.GetWorkLogs(ctx, taskId, session.Id, GetPermissions(c).Scope(entity.PermissionTaskRead))
+		if err != nil {
+			return fmt.Errorf("error to get work logs: %w", err)
+		}
+
+		var httpStatus int = http.StatusNoContent
+
+		if len(workLogs) > 0 {
+			httpStatus = http.StatusOK
+		}
+
+		return c.JSON(httpStatus, presenter(c).WorkLogs(workLogs))
+	}
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type WorkLogsTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestWorkLogsTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkLogsTestSuite))
+}
+
+func (suite *WorkLogsTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+}
+
+func (suite *WorkLogsTestSuite) TearDownTest() {
+	err := deleteTasks()
+	suite.NoError(err)
+}
+
+func (suite *WorkLogsTestSuite) TestTimer() {
+	taskId, err := createTask("test timer", "this test should track time", TechnicianUser.Id)
+	suite.NoError(err)
+
+	cases := map[string]struct {
+		action     string
+		taskId     int
+		user       entity.User
+		statusCode int
+	}{
+		"1 - Should return 201 - timer started": {
+			action:     "start",
+			taskId:     int(taskId),
+			user:       TechnicianUser,
+			statusCode: http.StatusCreated,
+		},
+		"2 - Should return 409 - timer already running": {
+			action:     "start",
+			taskId:     int(taskId),
+			user:       TechnicianUser,
+			statusCode: http.StatusConflict,
+		},
+		"3 - Should return 403 - without permission": {
+			action:     "start",
+			taskId:     int(taskId),
+			user:       ManagerUser,
+			statusCode: http.StatusForbidden,
+		},
+		"4 - Should return 200 - timer stopped": {
+			action:     "stop",
+			taskId:     int(taskId),
+			user:       TechnicianUser,
+			statusCode: http.StatusOK,
+		},
+		"5 - Should return 409 - no running timer": {
+			action:     "stop",
+			taskId:     int(taskId),
+			user:       TechnicianUser,
+			statusCode: http.StatusConflict,
+		},
+		"6 - Should return 404 - task not exist": {
+			action:     "start",
+			taskId:     0,
+			user:       TechnicianUser,
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	keys := make([]string, 0, len(cases))
+	for v := range cases {
+		keys = append(keys, v)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		suite.Run(key, func() {
+			url := fmt.Sprintf("/tasks/:id/timer/%s", cases[key].action)
+
+			c, rr := createContextAuth(http.MethodPost, url, strings.NewReader(`{"note": "test"}`), cases[key].user)
+
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(cases[key].taskId))
+
+			handler := StartTimer(TasksService)
+			if cases[key].action == "stop" {
+				handler = StopTimer(TasksService)
+			}
+
+			err := serve(handler, c)
+
+			suite.NoError(err)
+
+			suite.Equal(cases[key].statusCode, rr.Code, rr.Body)
+		})
+	}
+}
+
+func (suite *WorkLogsTestSuite) TestCreateWorkLog() {
+	taskId, err := createTask("test work log", "this test should log work", TechnicianUser.Id)
+	suite.NoError(err)
+
+	startedAt := time.Now().UTC().Add(-3 * time.Hour).Truncate(time.Second)
+	body := func(start, end time.Time) string {
+		return fmt.Sprintf(`{ "startedAt": "%s", "endedAt": "%s", "note": "test"}`, start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	cases := map[string]struct {
+		body       string
+		user       entity.User
+		statusCode int
+	}{
+		"1 - Should return 201": {
+			body:       body(startedAt, startedAt.Add(time.Hour)),
+			user:       TechnicianUser,
+			statusCode: http.StatusCreated,
+		},
+		"2 - Should return 409 - overlapping entry": {
+			body:       body(startedAt.Add(time.Minute), startedAt.Add(2*time.Hour)),
+			user:       TechnicianUser,
+			statusCode: http.StatusConflict,
+		},
+		"3 - Should return 400 - end before start": {
+			body:       body(startedAt, startedAt.Add(-time.Hour)),
+			user:       TechnicianUser,
+			statusCode: http.StatusBadRequest,
+		},
+		"4 - Should return 403 - without permission": {
+			body:       body(startedAt.Add(2*time.Hour), startedAt.Add(150*time.Minute)),
+			user:       ManagerUser,
+			statusCode: http.StatusForbidden,
+		},
+	}
+
+	keys := make([]string, 0, len(cases))
+	for v := range cases {
+		keys = append(keys, v)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		suite.Run(key, func() {
+			c, rr := createContextAuth(http.MethodPost, "/tasks/:id/work-logs", strings.NewReader(cases[key].body), cases[key].user)
+
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(int(taskId)))
+
+			handler := CreateWorkLog(TasksService)
+
+			err := serve(handler, c)
+
+			suite.NoError(err)
+
+			suite.Equal(cases[key].statusCode, rr.Code, rr.Body)
+		})
+	}
+
+	c, rr := createContextAuth(http.MethodGet, "/tasks/:id/work-logs", nil, ManagerUser)
+
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.Itoa(int(taskId)))
+
+	err = serve(GetWorkLogs(TasksService), c)
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, rr.Code, rr.Body)
+}
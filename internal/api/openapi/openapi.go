@@ -0,0 +1,201 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+)
+
+//go:embed openapi.yaml docs.html
+var files embed.FS
+
+var ErrRequestInvalid = apperror.Invalid("request_invalid", "request doesn't match the api spec")
+
+func init() {
+	openapi3filter.RegisterBodyDecoder("text/csv", openapi3filter.FileBodyDecoder)
+}
+
+// Load parses and validates the embedded OpenAPI document.
+func Load() (*openapi3.T, error) {
+	data, err := files.ReadFile("openapi.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = doc.Validate(loader.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// SpecHandler serves the document as JSON.
+func SpecHandler(doc *openapi3.T) (echo.HandlerFunc, error) {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c echo.Context) error {
+		return c.JSONBlob(http.StatusOK, spec)
+	}, nil
+}
+
+// DocsHandler serves a page rendering /openapi.json, it has no external assets.
+func DocsHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		page, err := files.ReadFile("docs.html")
+		if err != nil {
+			return err
+		}
+
+		return c.HTMLBlob(http.StatusOK, page)
+	}
+}
+
+type ValidatorConfig struct {
+	// ValidateResponses checks JSON responses too, it buffers every response so
+	// it's meant for tests.
+	ValidateResponses bool
+	// OnResponseError is called when a response doesn't match the document,
+	// the response was already sent at this point. Defaults to logging.
+	OnResponseError func(echo.Context, error)
+	// Logger receives the response errors by default. Defaults to
+	// slog.Default().
+	Logger *slog.Logger
+}
+
+// Validator returns a middleware checking requests, and optionally responses,
+// against the document. Requests to routes the document doesn't describe are
+// passed through untouched, authentication is left to the JWT middleware.
+func Validator(doc *openapi3.T, config ValidatorConfig) (echo.MiddlewareFunc, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	if config.OnResponseError == nil {
+		config.OnResponseError = func(c echo.Context, err error) {
+			config.Logger.ErrorContext(c.Request().Context(), "response doesn't match the api spec", slog.Any("error", err))
+		}
+	}
+
+	options := &openapi3filter.Options{
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		IncludeResponseStatus: true,
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			route, pathParams, err := router.FindRoute(req)
+			if err != nil {
+				return next(c)
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+
+			err = openapi3filter.ValidateRequest(req.Context(), input)
+			if err != nil {
+				return ErrRequestInvalid.Withf("request doesn't match the api spec: %s", firstLine(err)).Wrap(err)
+			}
+
+			if !config.ValidateResponses {
+				return next(c)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			// errors are written here so problem responses are validated too
+			err = next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			err = validateResponse(req.Context(), input, route, c.Response(), recorder)
+			if err != nil {
+				config.OnResponseError(c, err)
+			}
+
+			return nil
+		}
+	}, nil
+}
+
+func validateResponse(ctx context.Context, input *openapi3filter.RequestValidationInput, route *routers.Route, res *echo.Response, recorder *responseRecorder) error {
+	if recorder.checked && !recorder.json {
+		return nil
+	}
+
+	return openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 res.Status,
+		Header:                 res.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+		Options:                input.Options,
+	})
+}
+
+// responseRecorder keeps a copy of JSON responses, other content types such as
+// CSV exports are streamed and not validated.
+type responseRecorder struct {
+	http.ResponseWriter
+	body    bytes.Buffer
+	checked bool
+	json    bool
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if !w.checked {
+		w.checked = true
+		mediaType := strings.TrimSpace(strings.SplitN(w.Header().Get(echo.HeaderContentType), ";", 2)[0])
+		w.json = mediaType == echo.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json")
+	}
+
+	if w.json {
+		w.body.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func firstLine(err error) string {
+	return strings.SplitN(err.Error(), "\n", 2)[0]
+}
@@ -0,0 +1,454 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/api/openapi"
+	"github.com/lucas-simao/api-tasks/internal/domain/apikeys"
+	"github.com/lucas-simao/api-tasks/internal/domain/authz"
+	"github.com/lucas-simao/api-tasks/internal/domain/health"
+	"github.com/lucas-simao/api-tasks/internal/domain/mfa"
+	"github.com/lucas-simao/api-tasks/internal/domain/organizations"
+	"github.com/lucas-simao/api-tasks/internal/domain/reports"
+	"github.com/lucas-simao/api-tasks/internal/domain/stats"
+	"github.com/lucas-simao/api-tasks/internal/domain/tasks"
+	"github.com/lucas-simao/api-tasks/internal/domain/teams"
+	"github.com/lucas-simao/api-tasks/internal/domain/users"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/logging"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+	"github.com/lucas-simao/api-tasks/internal/utils"
+	"github.com/lucas-simao/api-tasks/scripts/migrations"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errInternal = errors.New("dial tcp 10.0.0.1:3306: connect: connection refused")
+
+	fakeTask = entity.TaskResponse{
+		Id:          1,
+		Title:       "test",
+		Description: "test for test",
+		UpdatedAt:   "2023-09-18 08:00:00",
+		CreatedBy:   entity.TaskUserOperationResponse{Id: 1, Name: "lucas", Date: "2023-09-18 08:00:00"},
+	}
+	fakeWorkLog = entity.WorkLogResponse{
+		Id:              1,
+		TaskId:          1,
+		StartedAt:       "2023-09-18T08:00:00Z",
+		EndedAt:         "2023-09-18T09:00:00Z",
+		DurationSeconds: 3600,
+		LoggedBy:        entity.TaskUserOperationResponse{Id: 1, Name: "lucas", Date: "2023-09-18 08:00:00"},
+	}
+	fakeTimesheet = entity.TimesheetResponse{
+		UserId:   1,
+		UserName: "lucas",
+		Date:     "2023-09-18",
+		Tasks:    []entity.TimesheetTask{{TaskId: 1, Title: "test", DurationSeconds: 3600, Entries: 1}},
+		Gaps:     []entity.TimesheetGap{},
+	}
+)
+
+type fakeTasks struct{ tasks.Service }
+
+func (fakeTasks) CreateTask(context.Context, entity.TaskRequest) (int64, error) { return 1, nil }
+func (fakeTasks) GetTasks(context.Context, int, entity.Scope) ([]entity.TaskResponse, error) {
+	return []entity.TaskResponse{fakeTask}, nil
+}
+func (fakeTasks) GetTaskById(_ context.Context, taskId, _ int, _ entity.Scope) (entity.TaskResponse, error) {
+	switch taskId {
+	case http.StatusNotFound:
+		return entity.TaskResponse{}, repository.ErrNoTaskInResult
+	case http.StatusInternalServerError:
+		return entity.TaskResponse{}, errInternal
+	}
+	return fakeTask, nil
+}
+func (fakeTasks) DeleteTaskById(context.Context, int, int, entity.Scope) error { return nil }
+func (fakeTasks) UpdateTaskById(context.Context, entity.TaskUpdateRequest) (entity.TaskResponse, error) {
+	return fakeTask, nil
+}
+func (fakeTasks) FinishTaskById(context.Context, int, int, int) (entity.TaskResponse, error) {
+	return fakeTask, nil
+}
+func (fakeTasks) StartTimer(context.Context, int, int) (entity.WorkLogResponse, error) {
+	return fakeWorkLog, nil
+}
+func (fakeTasks) StopTimer(context.Context, entity.TimerStopRequest) (entity.WorkLogResponse, error) {
+	return fakeWorkLog, nil
+}
+func (fakeTasks) CreateWorkLog(context.Context, entity.WorkLogRequest) (entity.WorkLogResponse, error) {
+	return fakeWorkLog, nil
+}
+func (fakeTasks) GetWorkLogs(context.Context, int, int, entity.Scope) ([]entity.WorkLogResponse, error) {
+	return []entity.WorkLogResponse{fakeWorkLog}, nil
+}
+func (fakeTasks) ExportTasks(_ context.Context, _ int, _ entity.Scope, fn func(entity.TaskExport) error) error {
+	return fn(entity.TaskExport{Id: 1, Title: "test"})
+}
+func (fakeTasks) ImportTasks(context.Context, int, io.Reader, bool) (entity.TaskImportReport, error) {
+	return entity.TaskImportReport{DryRun: true, Total: 1, Valid: 1, Errors: []entity.TaskImportError{}}, nil
+}
+func (fakeTasks) BulkTasks(_ context.Context, b entity.BulkTaskRequest) (entity.BulkTaskResponse, error) {
+	return entity.BulkTaskResponse{
+		Action:    b.Action,
+		Mode:      entity.BulkModeTransaction,
+		Succeeded: 1,
+		Results:   []entity.BulkTaskResult{{Id: b.Ids[0], Status: entity.BulkStatusOk}},
+	}, nil
+}
+
+type fakeUsers struct{ users.Service }
+
+func (fakeUsers) SignUp(context.Context, entity.SignUpRequest) error { return nil }
+func (fakeUsers) SignIn(_ context.Context, r entity.SignInRequest) (entity.SignInResponse, error) {
+	if r.Username == "mfa" {
+		return entity.SignInResponse{MFAToken: "mfa-token", MFAEnrollmentRequired: true}, nil
+	}
+	return entity.SignInResponse{Token: "token"}, nil
+}
+func (fakeUsers) SignInMFA(context.Context, entity.MFASignInRequest) (entity.SignInResponse, error) {
+	return entity.SignInResponse{Token: "token", RecoveryCodes: []string{"3f9a1-c07b2"}}, nil
+}
+
+type fakeMFA struct{ mfa.Service }
+
+var fakeEnrollment = entity.TOTPEnrollment{
+	Secret: "JBSWY3DPEHPK3PXP",
+	URI:    "otpauth://totp/api-tasks:lsimao?secret=JBSWY3DPEHPK3PXP&issuer=api-tasks",
+}
+
+func (fakeMFA) Enroll(context.Context, entity.User) (entity.TOTPEnrollment, error) {
+	return fakeEnrollment, nil
+}
+func (fakeMFA) Activate(context.Context, entity.User, string) (entity.RecoveryCodes, error) {
+	return entity.RecoveryCodes{RecoveryCodes: []string{"3f9a1-c07b2"}}, nil
+}
+func (fakeMFA) Disable(_ context.Context, u entity.User, _ string) error {
+	if u.CodeRole == entity.ManagerRole {
+		return mfa.ErrRequired
+	}
+	return nil
+}
+func (fakeMFA) EnrollChallenge(context.Context, string) (entity.TOTPEnrollment, error) {
+	return fakeEnrollment, nil
+}
+
+type fakeReports struct{ reports.Service }
+
+func (fakeReports) GetTimesheet(context.Context, entity.TimesheetRequest) (entity.TimesheetResponse, error) {
+	return fakeTimesheet, nil
+}
+func (fakeReports) GetWeeklyTimesheet(context.Context, entity.TimesheetRequest) (entity.WeeklyTimesheetResponse, error) {
+	return entity.WeeklyTimesheetResponse{
+		UserId:    1,
+		UserName:  "lucas",
+		WeekStart: "2023-09-18",
+		WeekEnd:   "2023-09-24",
+		Days:      []entity.TimesheetResponse{fakeTimesheet},
+	}, nil
+}
+
+type fakeStats struct{ stats.Service }
+
+func (fakeStats) GetStats(context.Context, int, time.Time, time.Time, int) (entity.StatsResponse, error) {
+	return entity.StatsResponse{
+		From:           "2023-09-01",
+		To:             "2023-09-30",
+		TasksPerDay:    []entity.TasksPerDay{{Date: "2023-09-18", Created: 1}},
+		OpenBacklog:    []entity.TechnicianTaskCount{},
+		TopTechnicians: []entity.TechnicianTaskCount{},
+	}, nil
+}
+
+type fakeTeams struct{ teams.Service }
+
+func (fakeTeams) CreateTeam(context.Context, entity.TeamRequest) (int64, error) { return 1, nil }
+func (fakeTeams) GetTeams(context.Context, int) ([]entity.TeamResponse, error) {
+	return []entity.TeamResponse{{
+		Id:      1,
+		Name:    "depot",
+		Members: []entity.TeamMember{{Id: 1, Name: "lucas", Username: "lsimao", Role: "technician"}},
+	}}, nil
+}
+func (fakeTeams) AddTeamMember(context.Context, entity.TeamMemberRequest) error    { return nil }
+func (fakeTeams) RemoveTeamMember(context.Context, entity.TeamMemberRequest) error { return nil }
+
+type fakeOrganizations struct{ organizations.Service }
+
+func (fakeOrganizations) GetOrganization(context.Context) (entity.Organization, error) {
+	return entity.Organization{Id: 1, Name: "acme", InviteCode: "0123456789abcdef"}, nil
+}
+func (fakeOrganizations) RotateInviteCode(context.Context) (entity.Organization, error) {
+	return entity.Organization{Id: 1, Name: "acme", InviteCode: "fedcba9876543210"}, nil
+}
+
+type fakeApiKeys struct{ apikeys.Service }
+
+var fakeApiKey = entity.ApiKeyResponse{
+	Id:          1,
+	Name:        "bms",
+	Permissions: []string{entity.PermissionTaskCreate},
+	UserId:      4,
+	ExpiresAt:   "2030-01-01 00:00:00",
+	CreatedBy:   entity.TaskUserOperationResponse{Id: 2, Name: "joão", Date: "2023-09-18 08:00:00"},
+}
+
+func (fakeApiKeys) CreateApiKey(context.Context, entity.ApiKeyRequest) (entity.ApiKeyCreated, error) {
+	return entity.ApiKeyCreated{ApiKeyResponse: fakeApiKey, Key: "tsk_0123456789abcdef"}, nil
+}
+func (fakeApiKeys) GetApiKeys(context.Context) ([]entity.ApiKeyResponse, error) {
+	return []entity.ApiKeyResponse{fakeApiKey}, nil
+}
+func (fakeApiKeys) RevokeApiKey(context.Context, int) error { return nil }
+
+type fakeRepository struct {
+	repository.Repository
+	applied []string
+}
+
+func (fakeRepository) Ping(context.Context) error { return nil }
+
+func (r fakeRepository) GetAppliedMigrations(context.Context) ([]string, error) {
+	return r.applied, nil
+}
+
+// GetPermissionsByRole returns the permissions the migrations give the roles.
+func (fakeRepository) GetPermissionsByRole(_ context.Context, code int) ([]string, error) {
+	switch code {
+	case entity.ManagerRole:
+		return []string{"report:read:team", "stats:read", "task:delete:team", "task:import", "task:read:team", "task:reassign:team", "team:manage", "user:manage"}, nil
+	case entity.TechnicianRole:
+		return []string{"report:read:own", "task:create", "task:read:own", "task:update:own", "worklog:create:own"}, nil
+	}
+	return nil, nil
+}
+
+func upMigrations(t *testing.T) []string {
+	names, err := migrations.Up(repository.DriverMySQL)
+	require.NoError(t, err)
+
+	return names
+}
+
+const testSecret = "openapi-test-secret-of-32-characters"
+
+func newTestApi(t *testing.T) *echo.Echo {
+	e, err := New(Services{
+		Tasks:         fakeTasks{},
+		Users:         fakeUsers{},
+		MFA:           fakeMFA{},
+		Reports:       fakeReports{},
+		Stats:         fakeStats{},
+		Teams:         fakeTeams{},
+		Organizations: fakeOrganizations{},
+		Authz:         authz.New(fakeRepository{}),
+		ApiKeys:       fakeApiKeys{},
+		Health:        health.New(fakeRepository{applied: upMigrations(t)}, upMigrations(t)),
+		Logger:        logging.Discard(),
+	}, Config{JWTSecret: testSecret})
+	require.NoError(t, err)
+
+	spec, err := openapi.Load()
+	require.NoError(t, err)
+
+	validator, err := openapi.Validator(spec, openapi.ValidatorConfig{
+		ValidateResponses: true,
+		OnResponseError: func(c echo.Context, err error) {
+			t.Errorf("%s %s: %v", c.Request().Method, c.Request().URL, err)
+		},
+	})
+	require.NoError(t, err)
+
+	e.Use(validator)
+
+	return e
+}
+
+func TestRoutesMatchSpec(t *testing.T) {
+	e := newTestApi(t)
+
+	spec, err := openapi.Load()
+	require.NoError(t, err)
+
+	versions := Versions(time.Time{})
+
+	routes := map[string]bool{}
+
+	for _, r := range e.Routes() {
+		path := r.Path
+		for _, segment := range strings.Split(r.Path, "/") {
+			if strings.HasPrefix(segment, ":") {
+				path = strings.Replace(path, segment, "{"+segment[1:]+"}", 1)
+			}
+		}
+
+		key := r.Method + " " + path
+		routes[key] = true
+
+		// routes of the docs are only served without prefix
+		if item := spec.Paths.Find(path); item != nil && len(item.Servers) > 0 {
+			continue
+		}
+
+		for _, v := range versions {
+			if v.Prefix != "" && !strings.HasPrefix(path, v.Prefix+"/") && path != v.Prefix {
+				continue
+			}
+
+			path = strings.TrimPrefix(path, v.Prefix)
+			break
+		}
+
+		// not found handlers echo adds for groups with middlewares
+		if path == "" || path == "/*" {
+			continue
+		}
+
+		item := spec.Paths.Find(path)
+		if item == nil || item.GetOperation(r.Method) == nil {
+			t.Errorf("route %s is not described in openapi.yaml", key)
+		}
+	}
+
+	for path, item := range spec.Paths {
+		prefixes := []string{""}
+		if len(item.Servers) == 0 {
+			prefixes = nil
+			for _, v := range versions {
+				prefixes = append(prefixes, v.Prefix)
+			}
+		}
+
+		for method := range item.Operations() {
+			for _, prefix := range prefixes {
+				if !routes[method+" "+prefix+path] {
+					t.Errorf("operation %s %s%s of openapi.yaml has no route", method, prefix, path)
+				}
+			}
+		}
+	}
+}
+
+func TestResponsesMatchSpec(t *testing.T) {
+	e := newTestApi(t)
+
+	technician := entity.User{Id: 1, Name: "lucas", Username: "lsimao", CodeRole: entity.TechnicianRole, OrganizationId: 1}
+	manager := entity.User{Id: 2, Name: "joão", Username: "joao", CodeRole: entity.ManagerRole, OrganizationId: 1}
+	withoutOrganization := entity.User{Id: 3, Name: "maria", Username: "maria", CodeRole: entity.ManagerRole}
+
+	cases := []struct {
+		method, path, body string
+		user               *entity.User
+		statusCode         int
+	}{
+		{http.MethodPost, "/sign-up", `{"name": "lucas", "username": "lsimao", "password": "123456", "organization": "acme"}`, nil, http.StatusCreated},
+		{http.MethodPost, "/sign-up", `{"name": "lucas", "username": "lsimao", "password": "123456", "inviteCode": "0123456789abcdef"}`, nil, http.StatusCreated},
+		{http.MethodPost, "/sign-in", `{"username": "lsimao", "password": "123456"}`, nil, http.StatusOK},
+		{http.MethodPost, "/sign-in", `{"username": "mfa", "password": "123456"}`, nil, http.StatusOK},
+		{http.MethodPost, "/sign-in/mfa", `{"mfaToken": "mfa-token", "code": "287082"}`, nil, http.StatusOK},
+		{http.MethodPost, "/sign-in/mfa/enroll", `{"mfaToken": "mfa-token"}`, nil, http.StatusCreated},
+		{http.MethodPost, "/mfa/totp", "", &technician, http.StatusCreated},
+		{http.MethodPost, "/mfa/totp/verify", `{"code": "287082"}`, &technician, http.StatusOK},
+		{http.MethodPost, "/mfa/totp/disable", `{"code": "287082"}`, &technician, http.StatusOK},
+		{http.MethodPost, "/mfa/totp/disable", `{"code": "287082"}`, &manager, http.StatusForbidden},
+		{http.MethodPost, "/tasks", `{"title": "test", "description": "test"}`, &technician, http.StatusCreated},
+		{http.MethodPost, "/tasks", `{"title": "test", "description": "test"}`, &manager, http.StatusForbidden},
+		{http.MethodGet, "/tasks", "", &technician, http.StatusOK},
+		{http.MethodGet, "/tasks/export?format=jsonl", "", &technician, http.StatusOK},
+		{http.MethodPost, "/tasks/import?dryRun=true", "title,description,username\n", &manager, http.StatusOK},
+		{http.MethodPost, "/tasks/bulk", `{"action": "finish", "ids": [1]}`, &technician, http.StatusOK},
+		{http.MethodGet, "/tasks/1", "", &technician, http.StatusOK},
+		{http.MethodGet, "/tasks/404", "", &technician, http.StatusNotFound},
+		{http.MethodGet, "/tasks/500", "", &technician, http.StatusInternalServerError},
+		{http.MethodDelete, "/tasks/1", "", &technician, http.StatusForbidden},
+		{http.MethodPut, "/tasks/1", `{"title": "test", "description": "test"}`, &technician, http.StatusOK},
+		{http.MethodDelete, "/tasks/1", "", &manager, http.StatusOK},
+		{http.MethodPatch, "/tasks/1", "", &technician, http.StatusOK},
+		{http.MethodPost, "/tasks/1/timer/start", "", &technician, http.StatusCreated},
+		{http.MethodPost, "/tasks/1/timer/stop", `{"note": "done"}`, &technician, http.StatusOK},
+		{http.MethodPost, "/tasks/1/work-logs", `{"startedAt": "2023-09-18T08:00:00Z", "endedAt": "2023-09-18T09:00:00Z"}`, &technician, http.StatusCreated},
+		{http.MethodGet, "/tasks/1/work-logs", "", &manager, http.StatusOK},
+		{http.MethodGet, "/reports/timesheet?date=2023-09-18", "", &technician, http.StatusOK},
+		{http.MethodGet, "/reports/timesheet/weekly?date=2023-09-18&userId=1", "", &manager, http.StatusOK},
+		{http.MethodGet, "/stats?from=2023-09-01&to=2023-09-30", "", &manager, http.StatusOK},
+		{http.MethodGet, "/teams", "", &technician, http.StatusOK},
+		{http.MethodPost, "/teams", `{"name": "depot"}`, &manager, http.StatusCreated},
+		{http.MethodPost, "/teams", `{"name": "depot"}`, &technician, http.StatusForbidden},
+		{http.MethodPut, "/teams/1/members/1", "", &manager, http.StatusOK},
+		{http.MethodDelete, "/teams/1/members/1", "", &manager, http.StatusOK},
+		{http.MethodGet, "/organization", "", &manager, http.StatusOK},
+		{http.MethodGet, "/organization", "", &technician, http.StatusForbidden},
+		{http.MethodGet, "/organization", "", &withoutOrganization, http.StatusUnauthorized},
+		{http.MethodPost, "/organization/invite-code", "", &manager, http.StatusOK},
+		{http.MethodGet, "/api-keys", "", &manager, http.StatusOK},
+		{http.MethodGet, "/api-keys", "", &technician, http.StatusForbidden},
+		{http.MethodPost, "/api-keys", `{"name": "bms", "permissions": ["task:create"], "expiresAt": "2030-01-01T00:00:00Z"}`, &manager, http.StatusCreated},
+		{http.MethodDelete, "/api-keys/1", "", &manager, http.StatusOK},
+		{http.MethodGet, "/openapi.json", "", nil, http.StatusOK},
+		{http.MethodGet, "/docs", "", nil, http.StatusOK},
+		{http.MethodGet, "/metrics", "", nil, http.StatusOK},
+		{http.MethodGet, "/healthz", "", nil, http.StatusOK},
+		{http.MethodGet, "/readyz", "", nil, http.StatusOK},
+		// rejected by the spec before reaching the handlers
+		{http.MethodPost, "/tasks", `{"description": "test"}`, &technician, http.StatusBadRequest},
+		{http.MethodPost, "/tasks/bulk", `{"action": "archive", "ids": [1]}`, &manager, http.StatusBadRequest},
+		{http.MethodGet, "/tasks/export?format=xlsx", "", &technician, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%s %s %d", tc.method, tc.path, tc.statusCode), func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+
+			if tc.body != "" {
+				contentType := echo.MIMEApplicationJSON
+				if strings.HasPrefix(tc.path, "/tasks/import") {
+					contentType = "text/csv"
+				}
+				req.Header.Set(echo.HeaderContentType, contentType)
+			}
+
+			if tc.user != nil {
+				token, err := utils.GenerateToken(testSecret, *tc.user)
+				require.NoError(t, err)
+				req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+			}
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.statusCode, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+// TestVersionsMatchSpec checks the requests are matched with the spec under
+// the prefix of every version.
+func TestVersionsMatchSpec(t *testing.T) {
+	e := newTestApi(t)
+
+	technician := entity.User{Id: 1, Name: "lucas", Username: "lsimao", CodeRole: entity.TechnicianRole, OrganizationId: 1}
+
+	token, err := utils.GenerateToken(testSecret, technician)
+	require.NoError(t, err)
+
+	for _, prefix := range []string{"", "/v1", "/v2"} {
+		req := httptest.NewRequest(http.MethodGet, prefix+"/tasks/export?format=xlsx", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Contains(t, rec.Body.String(), `"code":"request_invalid"`, prefix)
+	}
+}
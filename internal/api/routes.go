@@ -1,36 +1,145 @@
 package api
 
 import (
-	"os"
+	"fmt"
+	"log/slog"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/lucas-simao/api-tasks/internal/api/handlers"
+	"github.com/lucas-simao/api-tasks/internal/api/openapi"
 	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/logging"
+	"github.com/lucas-simao/api-tasks/internal/tenant"
 )
 
-func addRoutes(e *echo.Echo, s Services) {
+func addRoutes(e *echo.Echo, s Services, c Config, spec *openapi3.T, versions []Version) error {
+	specHandler, err := openapi.SpecHandler(spec)
+	if err != nil {
+		return fmt.Errorf("error to serve openapi spec: %w", err)
+	}
+
+	// docs
+	e.GET("/openapi.json", specHandler)
+	e.GET("/docs", openapi.DocsHandler())
+
+	// monitoring
+	e.GET("/metrics", s.Metrics.Handler())
+	e.GET("/healthz", handlers.Healthz())
+	e.GET("/readyz", handlers.Readyz(s.Health))
+
+	for _, v := range versions {
+		g := e.Group(v.Prefix, handlers.UsePresenter(v.Presenter))
+
+		if v.Deprecation != nil {
+			g.Use(deprecated(v))
+		}
+
+		addVersionRoutes(g, s, c)
+	}
+
+	return nil
+}
+
+// addVersionRoutes registers the routes shared by every version, versions only
+// change the response bodies through their presenter.
+func addVersionRoutes(g *echo.Group, s Services, c Config) {
 	// public
-	public := e.Group("")
-	public.POST("/sign-up", handlers.SignUp(s.Users))
-	public.POST("/sign-in", handlers.SignIn(s.Users))
+	g.POST("/sign-up", handlers.SignUp(s.Users))
+	g.POST("/sign-in", handlers.SignIn(s.Users))
+	g.POST("/sign-in/mfa", handlers.SignInMFA(s.Users))
+	g.POST("/sign-in/mfa/enroll", handlers.EnrollMFAChallenge(s.MFA))
+	g.GET("/sign-in/oidc", handlers.SignInOIDC(s.SSO))
+	g.GET("/sign-in/oidc/callback", handlers.OIDCCallback(s.SSO))
 
 	// authenticated
-	auth := e.Group("")
-	auth.Use(middleware.JWTWithConfig(JwtConfig()))
+	auth := g.Group("")
+	auth.Use(handlers.AuthenticateApiKey(s.ApiKeys), middleware.JWTWithConfig(JwtConfig(c.JWTSecret)), logSession(), scopeOrganization(), handlers.Authorize(s.Authz))
+
+	auth.POST("/mfa/totp", handlers.EnrollTOTP(s.MFA))
+	auth.POST("/mfa/totp/verify", handlers.ActivateTOTP(s.MFA))
+	auth.POST("/mfa/totp/disable", handlers.DisableTOTP(s.MFA))
 
 	auth.POST("/tasks", handlers.CreateTask(s.Tasks))
 	auth.GET("/tasks", handlers.GetTasks(s.Tasks))
+	auth.GET("/tasks/export", handlers.ExportTasks(s.Tasks))
+	auth.POST("/tasks/import", handlers.ImportTasks(s.Tasks), middleware.BodyLimit("20M"))
+	auth.POST("/tasks/bulk", handlers.BulkTasks(s.Tasks))
 	auth.GET("/tasks/:id", handlers.GetTaskById(s.Tasks))
 	auth.DELETE("/tasks/:id", handlers.DeleteTaskById(s.Tasks))
 	auth.PUT("/tasks/:id", handlers.UpdateTaskById(s.Tasks))
 	auth.PATCH("/tasks/:id", handlers.FinishTaskById(s.Tasks))
+
+	auth.POST("/tasks/:id/timer/start", handlers.StartTimer(s.Tasks))
+	auth.POST("/tasks/:id/timer/stop", handlers.StopTimer(s.Tasks))
+	auth.POST("/tasks/:id/work-logs", handlers.CreateWorkLog(s.Tasks))
+	auth.GET("/tasks/:id/work-logs", handlers.GetWorkLogs(s.Tasks))
+
+	auth.GET("/reports/timesheet", handlers.GetTimesheet(s.Reports))
+	auth.GET("/reports/timesheet/weekly", handlers.GetWeeklyTimesheet(s.Reports))
+
+	auth.GET("/stats", handlers.GetStats(s.Stats))
+
+	auth.GET("/teams", handlers.GetTeams(s.Teams))
+	auth.POST("/teams", handlers.CreateTeam(s.Teams))
+	auth.PUT("/teams/:id/members/:userId", handlers.AddTeamMember(s.Teams))
+	auth.DELETE("/teams/:id/members/:userId", handlers.RemoveTeamMember(s.Teams))
+
+	auth.GET("/organization", handlers.GetOrganization(s.Organizations))
+	auth.POST("/organization/invite-code", handlers.RotateInviteCode(s.Organizations))
+
+	auth.GET("/api-keys", handlers.GetApiKeys(s.ApiKeys))
+	auth.POST("/api-keys", handlers.CreateApiKey(s.ApiKeys))
+	auth.DELETE("/api-keys/:id", handlers.RevokeApiKey(s.ApiKeys))
+}
+
+// logSession adds the authenticated user to the log lines of the request.
+func logSession() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			session := handlers.GetAuthSession(c)
+
+			logging.AddFields(c.Request().Context(),
+				slog.Int("user_id", session.Id),
+				slog.String("role", entity.RoleName(session.CodeRole)),
+				slog.Int("organization_id", session.OrganizationId),
+			)
+
+			if id, ok := handlers.GetApiKeyId(c); ok {
+				logging.AddFields(c.Request().Context(), slog.Int("api_key_id", id))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// scopeOrganization scopes the queries of the request to the organization of
+// the user, tokens without an organization are rejected.
+func scopeOrganization() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			session := handlers.GetAuthSession(c)
+
+			if session.OrganizationId == 0 {
+				return handlers.ErrUnauthorized
+			}
+
+			r := c.Request()
+			c.SetRequest(r.WithContext(tenant.WithOrganization(r.Context(), session.OrganizationId)))
+
+			return next(c)
+		}
+	}
 }
 
-func JwtConfig() middleware.JWTConfig {
+func JwtConfig(secret string) middleware.JWTConfig {
 	config := middleware.JWTConfig{
+		// requests with an api key are already authenticated
+		Skipper:    handlers.HasApiKey,
 		Claims:     &entity.JwtCustomClaims{},
-		SigningKey: []byte(os.Getenv("JWT_SECRET")),
+		SigningKey: []byte(secret),
 	}
 
 	return config
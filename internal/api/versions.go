@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/api/dto"
+	v1 "github.com/lucas-simao/api-tasks/internal/api/dto/v1"
+	v2 "github.com/lucas-simao/api-tasks/internal/api/dto/v2"
+)
+
+// Version is a prefix mounting every route with the response bodies of one
+// presenter. To change a response shape add a presenter in dto and a version
+// using it, older versions keep answering like before.
+type Version struct {
+	Prefix      string
+	Presenter   dto.Presenter
+	Deprecation *Deprecation
+}
+
+// Deprecation is sent to clients in the Deprecation (RFC 9745), Sunset
+// (RFC 8594) and Link headers.
+type Deprecation struct {
+	Since     time.Time
+	Sunset    time.Time
+	Successor string
+}
+
+// unversionedDeprecatedSince is when the routes without prefix became aliases
+// of /v1.
+var unversionedDeprecatedSince = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// Versions returns the versions served by the api, sunset is when the routes
+// without prefix stop working, zero while it isn't decided.
+func Versions(sunset time.Time) []Version {
+	return []Version{
+		{Prefix: "/v1", Presenter: v1.Presenter{}},
+		{Prefix: "/v2", Presenter: v2.Presenter{}},
+		{
+			Prefix:    "",
+			Presenter: v1.Presenter{},
+			Deprecation: &Deprecation{
+				Since:     unversionedDeprecatedSince,
+				Sunset:    sunset,
+				Successor: "/v1",
+			},
+		},
+	}
+}
+
+func deprecated(v Version) echo.MiddlewareFunc {
+	d := *v.Deprecation
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+
+			header.Set("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+
+			if !d.Sunset.IsZero() {
+				header.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+			}
+
+			if d.Successor != "" {
+				path := d.Successor + strings.TrimPrefix(c.Request().URL.Path, v.Prefix)
+				header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, path))
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecated(t *testing.T) {
+	sunset := time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	e := echo.New()
+
+	for _, v := range Versions(sunset) {
+		g := e.Group(v.Prefix)
+		if v.Deprecation != nil {
+			g.Use(deprecated(v))
+		}
+		g.GET("/tasks/:id", func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+	}
+
+	cases := map[string]struct {
+		path        string
+		deprecation bool
+	}{
+		"1 - Shouldn't deprecate v1":                 {path: "/v1/tasks/1"},
+		"2 - Shouldn't deprecate v2":                 {path: "/v2/tasks/1"},
+		"3 - Should deprecate routes without prefix": {path: "/tasks/1", deprecation: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			if !tc.deprecation {
+				require.Empty(t, rec.Header().Get("Deprecation"))
+				require.Empty(t, rec.Header().Get("Sunset"))
+				return
+			}
+
+			require.Equal(t, fmt.Sprintf("@%d", unversionedDeprecatedSince.Unix()), rec.Header().Get("Deprecation"))
+			require.Equal(t, sunset.Format(http.TimeFormat), rec.Header().Get("Sunset"))
+			require.Equal(t, `</v1/tasks/1>; rel="successor-version"`, rec.Header().Get("Link"))
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// Kind groups errors by how the client should react to them, the api maps
+// every kind to one http status.
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindInvalid
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindUnavailable
+)
+
+const CodeValidation = "validation_failed"
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is an error safe to show to clients. Code is stable and documented,
+// Message can change and the wrapped cause is never exposed.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	cause   error
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func Invalid(code, message string) *Error {
+	return New(KindInvalid, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+func Unavailable(code, message string) *Error {
+	return New(KindUnavailable, code, message)
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.cause)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is matches errors by code, so copies made by Wrap and Withf still match the
+// original variable.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap returns a copy of e caused by err.
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.cause = err
+	return &c
+}
+
+// Withf returns a copy of e with a more specific message.
+func (e *Error) Withf(format string, args ...interface{}) *Error {
+	c := *e
+	c.Message = fmt.Sprintf(format, args...)
+	return &c
+}
+
+// Validation converts the errors of ozzo-validation to an invalid error with
+// one entry per field, other errors are returned as they are.
+func Validation(err error) error {
+	var fieldErrors validation.Errors
+	if !errors.As(err, &fieldErrors) {
+		return err
+	}
+
+	e := Invalid(CodeValidation, "request has invalid fields").Wrap(err)
+	e.Fields = flatten("", fieldErrors)
+
+	return e
+}
+
+func flatten(prefix string, fieldErrors validation.Errors) []FieldError {
+	fields := []FieldError{}
+
+	for name, err := range fieldErrors {
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		var nested validation.Errors
+		if errors.As(err, &nested) {
+			fields = append(fields, flatten(name, nested)...)
+			continue
+		}
+
+		fields = append(fields, FieldError{Field: name, Message: err.Error()})
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Field < fields[j].Field
+	})
+
+	return fields
+}
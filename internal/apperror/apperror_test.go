@@ -0,0 +1,54 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIs(t *testing.T) {
+	errNotFound := NotFound("task_not_found", "task not found")
+
+	cases := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"1 - Should match the same error":       {err: errNotFound, expected: true},
+		"2 - Should match a copy with message":  {err: errNotFound.Withf("task %d not found", 1), expected: true},
+		"3 - Should match a wrapped error":      {err: fmt.Errorf("error to get task: %w", errNotFound.Wrap(errors.New("db"))), expected: true},
+		"4 - Should not match another code":     {err: NotFound("user_not_found", "user not found"), expected: false},
+		"5 - Should not match an untyped error": {err: errors.New("task not found"), expected: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, errors.Is(tc.err, errNotFound))
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	err := Validation(validation.Errors{
+		"title":       errors.New("cannot be blank"),
+		"description": errors.New("the length must be no more than 2500"),
+		"period":      validation.Errors{"from": errors.New("cannot be blank")},
+	})
+
+	var appErr *Error
+	require.True(t, errors.As(err, &appErr))
+	require.Equal(t, KindInvalid, appErr.Kind)
+	require.Equal(t, CodeValidation, appErr.Code)
+	require.Equal(t, []FieldError{
+		{Field: "description", Message: "the length must be no more than 2500"},
+		{Field: "period.from", Message: "cannot be blank"},
+		{Field: "title", Message: "cannot be blank"},
+	}, appErr.Fields)
+
+	require.NoError(t, Validation(nil))
+
+	internal := errors.New("internal")
+	require.Equal(t, internal, Validation(internal))
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const masked = "****"
+
+// Print writes every value with the source it came from, secrets are masked.
+// The password is the only part of DATABASE_URL masked, the host and the
+// database are useful to debug a deploy.
+func Print(w io.Writer, c Config, sources map[string]Source) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVALUE\tSOURCE")
+
+	v := reflect.ValueOf(c)
+	for _, f := range fields() {
+		value := format(v.Field(f.index).Interface())
+
+		if f.secret && value != "" {
+			value = c.mask(f.env, value)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", f.env, value, sources[f.env])
+	}
+
+	return tw.Flush()
+}
+
+func format(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(DateLayout)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (c Config) mask(env, value string) string {
+	if env != "DATABASE_URL" {
+		return masked
+	}
+
+	switch c.DatabaseDriver {
+	case "postgres":
+		return maskPostgresURL(value)
+	case "sqlite":
+		// a path, SQLite has no password
+		return value
+	}
+
+	dsn, err := mysql.ParseDSN(value)
+	if err != nil {
+		return masked
+	}
+	if dsn.Passwd != "" {
+		dsn.Passwd = masked
+	}
+
+	return dsn.FormatDSN()
+}
+
+// maskPostgresURL masks the password of a url, the keyword/value form
+// (host=... password=...) is masked whole.
+func maskPostgresURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		return masked
+	}
+
+	// set by hand, url escapes the *
+	var user string
+	if u.User != nil {
+		user = u.User.String()
+		if _, ok := u.User.Password(); ok {
+			user = url.User(u.User.Username()).String() + ":" + masked
+		}
+		user += "@"
+		u.User = nil
+	}
+
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		if strings.HasPrefix(param, "password=") {
+			params[i] = "password=" + masked
+		}
+	}
+	u.RawQuery = strings.Join(params, "&")
+
+	return strings.Replace(u.String(), "://", "://"+user, 1)
+}
@@ -0,0 +1,118 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+)
+
+// keyPrefix tells api keys apart from tokens, and from other secrets when one
+// leaks in a log or a repository.
+const keyPrefix = "tsk_"
+
+var ErrInvalidApiKey = apperror.Unauthorized("invalid_api_key", "api key is invalid, expired or revoked")
+
+type service struct {
+	repository repository.Repository
+}
+
+func New(r repository.Repository) Service {
+	return service{
+		repository: r,
+	}
+}
+
+// CreateApiKey creates a key acting as a new service user, the key is only
+// returned now, the database keeps its hash.
+func (s service) CreateApiKey(ctx context.Context, k entity.ApiKeyRequest) (entity.ApiKeyCreated, error) {
+	secret, err := random(32)
+	if err != nil {
+		return entity.ApiKeyCreated{}, err
+	}
+
+	suffix, err := random(8)
+	if err != nil {
+		return entity.ApiKeyCreated{}, err
+	}
+
+	key := keyPrefix + secret
+	k.KeyHash = Hash(key)
+	k.Username = "key-" + suffix
+
+	var created entity.ApiKeyCreated
+
+	err = s.repository.WithTx(ctx, func(r repository.Repository) error {
+		id, err := r.CreateApiKey(ctx, k)
+		if err != nil {
+			return err
+		}
+
+		created.ApiKeyResponse, err = r.GetApiKeyById(ctx, int(id))
+		return err
+	})
+	if err != nil {
+		return entity.ApiKeyCreated{}, err
+	}
+
+	created.Key = key
+
+	return created, nil
+}
+
+// GetApiKeys returns the keys of the organization of the user calling.
+func (s service) GetApiKeys(ctx context.Context) ([]entity.ApiKeyResponse, error) {
+	return s.repository.GetApiKeys(ctx)
+}
+
+func (s service) RevokeApiKey(ctx context.Context, id int) error {
+	return s.repository.RevokeApiKey(ctx, id)
+}
+
+// Authenticate returns the service user and the permissions of the key, and
+// records its use.
+func (s service) Authenticate(ctx context.Context, key string) (entity.ApiKeyPrincipal, error) {
+	if !strings.HasPrefix(key, keyPrefix) {
+		return entity.ApiKeyPrincipal{}, ErrInvalidApiKey
+	}
+
+	p, err := s.repository.GetApiKeyPrincipal(ctx, Hash(key))
+	if err != nil {
+		if errors.Is(err, repository.ErrApiKeyNotFound) {
+			return entity.ApiKeyPrincipal{}, ErrInvalidApiKey
+		}
+		return entity.ApiKeyPrincipal{}, err
+	}
+
+	err = s.repository.TouchApiKey(ctx, p.ApiKeyId, time.Now().UTC())
+	if err != nil {
+		return entity.ApiKeyPrincipal{}, err
+	}
+
+	return p, nil
+}
+
+// Hash is the hash of the key stored in the database. Keys are random, unlike
+// passwords, so a fast hash is enough and lets keys be looked up by hash.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func random(size int) (string, error) {
+	b := make([]byte, size)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,14 @@
+package apikeys
+
+import (
+	"context"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+type Service interface {
+	CreateApiKey(context.Context, entity.ApiKeyRequest) (entity.ApiKeyCreated, error)
+	GetApiKeys(context.Context) ([]entity.ApiKeyResponse, error)
+	RevokeApiKey(context.Context, int) error
+	Authenticate(context.Context, string) (entity.ApiKeyPrincipal, error)
+}
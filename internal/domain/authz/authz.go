@@ -0,0 +1,62 @@
+package authz
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+)
+
+const cacheTTL = 30 * time.Second
+
+type cached struct {
+	permissions entity.Permissions
+	expiresAt   time.Time
+}
+
+type service struct {
+	repository repository.Repository
+
+	mu    sync.Mutex
+	cache map[int]cached
+}
+
+func New(r repository.Repository) Service {
+	return &service{
+		repository: r,
+		cache:      map[int]cached{},
+	}
+}
+
+// Permissions returns the permissions of the role, kept for a short time as
+// every authenticated request reads them.
+func (s *service) Permissions(ctx context.Context, codeRole int) (entity.Permissions, error) {
+	s.mu.Lock()
+	entry, ok := s.cache[codeRole]
+	s.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.permissions, nil
+	}
+
+	names, err := s.repository.GetPermissionsByRole(ctx, codeRole)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := entity.NewPermissions(names...)
+
+	s.mu.Lock()
+	s.cache[codeRole] = cached{permissions: permissions, expiresAt: time.Now().Add(cacheTTL)}
+	s.mu.Unlock()
+
+	return permissions, nil
+}
+
+func (s *service) Invalidate() {
+	s.mu.Lock()
+	s.cache = map[int]cached{}
+	s.mu.Unlock()
+}
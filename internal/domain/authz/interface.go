@@ -0,0 +1,14 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+type Service interface {
+	Permissions(context.Context, int) (entity.Permissions, error)
+	// Invalidate drops the cached permissions, changes to the permissions of
+	// the roles apply right away instead of after the cache expires.
+	Invalidate()
+}
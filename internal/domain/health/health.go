@@ -0,0 +1,79 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+)
+
+var (
+	ErrShuttingDown        = apperror.Unavailable("shutting_down", "api is shutting down")
+	ErrDatabaseUnavailable = apperror.Unavailable("database_unavailable", "database is unavailable")
+	ErrMigrationsPending   = apperror.Unavailable("migrations_pending", "database migrations are pending")
+)
+
+// checkTimeout keeps probes fast when the database hangs.
+const checkTimeout = 2 * time.Second
+
+type service struct {
+	repository   repository.Repository
+	migrations   []string
+	shuttingDown atomic.Bool
+}
+
+// New returns the health of the api, migrations are the names every database
+// should have in its migrations table.
+func New(r repository.Repository, migrations []string) Service {
+	return &service{
+		repository: r,
+		migrations: migrations,
+	}
+}
+
+func (s *service) Ready(ctx context.Context) error {
+	if s.shuttingDown.Load() {
+		return ErrShuttingDown
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	if err := s.repository.Ping(ctx); err != nil {
+		return ErrDatabaseUnavailable.Wrap(err)
+	}
+
+	applied, err := s.repository.GetAppliedMigrations(ctx)
+	if err != nil {
+		return ErrDatabaseUnavailable.Wrap(err)
+	}
+
+	if pending := pendingMigrations(s.migrations, applied); len(pending) > 0 {
+		return ErrMigrationsPending.Withf("database migrations are pending: %s", strings.Join(pending, ", "))
+	}
+
+	return nil
+}
+
+func (s *service) ShutDown() {
+	s.shuttingDown.Store(true)
+}
+
+func pendingMigrations(expected, applied []string) []string {
+	done := make(map[string]bool, len(applied))
+	for _, name := range applied {
+		done[name] = true
+	}
+
+	var pending []string
+	for _, name := range expected {
+		if !done[name] {
+			pending = append(pending, name)
+		}
+	}
+
+	return pending
+}
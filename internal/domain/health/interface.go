@@ -0,0 +1,14 @@
+package health
+
+import (
+	"context"
+)
+
+type Service interface {
+	// Ready returns nil when the api can receive traffic: it isn't shutting
+	// down, the database answers and every migration was applied.
+	Ready(context.Context) error
+	// ShutDown makes Ready fail, so the load balancer stops sending requests
+	// before the server closes.
+	ShutDown()
+}
@@ -0,0 +1,18 @@
+package mfa
+
+import (
+	"context"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+type Service interface {
+	Enroll(context.Context, entity.User) (entity.TOTPEnrollment, error)
+	Activate(ctx context.Context, u entity.User, code string) (entity.RecoveryCodes, error)
+	Disable(ctx context.Context, u entity.User, code string) error
+
+	// sign in
+	Challenge(context.Context, entity.User) (entity.MFAChallenge, error)
+	EnrollChallenge(ctx context.Context, mfaToken string) (entity.TOTPEnrollment, error)
+	VerifyChallenge(context.Context, entity.MFASignInRequest) (entity.User, []string, error)
+}
@@ -0,0 +1,345 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+	"github.com/lucas-simao/api-tasks/internal/tenant"
+	"github.com/lucas-simao/api-tasks/internal/totp"
+)
+
+const (
+	// Issuer names the api in authenticator apps.
+	Issuer = "api-tasks"
+
+	// ChallengeTTL is the time users have to send a code after their password.
+	ChallengeTTL = 5 * time.Minute
+
+	// MaxFailedAttempts wrong codes within LockDuration lock the codes of the
+	// user, 6 digits are otherwise guessed in a few days.
+	MaxFailedAttempts = 5
+	LockDuration      = 15 * time.Minute
+
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrInvalidCode      = apperror.Unauthorized("invalid_mfa_code", "code is invalid or was already used")
+	ErrLocked           = apperror.Unauthorized("mfa_locked", "too many wrong codes, try again later")
+	ErrInvalidChallenge = apperror.Unauthorized("invalid_mfa_token", "mfa token is invalid or expired")
+	ErrRequired         = apperror.Forbidden("mfa_required", "two-factor authentication is required for managers")
+	ErrNotEnrolled      = apperror.Invalid("mfa_not_enrolled", "enroll an authenticator first")
+)
+
+type Config struct {
+	// RequiredForManagers makes managers enable two-factor authentication on
+	// their next sign in, and keeps them from disabling it.
+	RequiredForManagers bool
+}
+
+type service struct {
+	repository repository.Repository
+	config     Config
+	jwtSecret  string
+}
+
+func New(r repository.Repository, c Config, jwtSecret string) Service {
+	return service{
+		repository: r,
+		config:     c,
+		jwtSecret:  jwtSecret,
+	}
+}
+
+// challengeClaims are the claims of the MFA tokens, the user passed its
+// password and signs in once it sends a code.
+type challengeClaims struct {
+	UserId         int  `json:"userId"`
+	OrganizationId int  `json:"organizationId"`
+	Enroll         bool `json:"enroll,omitempty"`
+	jwt.StandardClaims
+}
+
+// Enroll creates the secret of the user, enabled by Activate with a code of
+// it. Enrolling again before that replaces the secret.
+func (s service) Enroll(ctx context.Context, u entity.User) (entity.TOTPEnrollment, error) {
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return entity.TOTPEnrollment{}, err
+	}
+
+	err = s.repository.CreateMFA(tenant.WithOrganization(ctx, u.OrganizationId), u.Id, secret)
+	if err != nil {
+		return entity.TOTPEnrollment{}, err
+	}
+
+	return entity.TOTPEnrollment{
+		Secret: secret,
+		URI:    totp.URI(Issuer, u.Username, secret),
+	}, nil
+}
+
+// Activate enables the secret of the user with a code of it and returns new
+// recovery codes, only shown now.
+func (s service) Activate(ctx context.Context, u entity.User, code string) (entity.RecoveryCodes, error) {
+	ctx = tenant.WithOrganization(ctx, u.OrganizationId)
+
+	m, err := s.get(ctx, u.Id)
+	if err != nil {
+		if errors.Is(err, repository.ErrMFANotFound) {
+			return entity.RecoveryCodes{}, ErrNotEnrolled
+		}
+		return entity.RecoveryCodes{}, err
+	}
+
+	if m.Enabled {
+		return entity.RecoveryCodes{}, repository.ErrMFAEnabled
+	}
+
+	step, ok := totp.Validate(m.Secret, normalize(code), time.Now())
+	if !ok {
+		return entity.RecoveryCodes{}, s.fail(ctx, u.Id)
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return entity.RecoveryCodes{}, err
+	}
+
+	err = s.repository.EnableMFA(ctx, u.Id, step, hashes)
+	if err != nil {
+		return entity.RecoveryCodes{}, err
+	}
+
+	return entity.RecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// Disable disables the two-factor authentication of the user with a code,
+// unless it's required.
+func (s service) Disable(ctx context.Context, u entity.User, code string) error {
+	if s.required(u) {
+		return ErrRequired
+	}
+
+	ctx = tenant.WithOrganization(ctx, u.OrganizationId)
+
+	m, err := s.get(ctx, u.Id)
+	if err != nil {
+		return err
+	}
+
+	if !m.Enabled {
+		return repository.ErrMFANotFound
+	}
+
+	if err := s.verify(ctx, m, code); err != nil {
+		return err
+	}
+
+	return s.repository.DeleteMFA(ctx, u.Id)
+}
+
+// Challenge returns the MFA token of users who passed their password and need
+// a code, because they enabled two-factor authentication or it's required.
+func (s service) Challenge(ctx context.Context, u entity.User) (entity.MFAChallenge, error) {
+	m, err := s.repository.GetUserMFA(tenant.WithOrganization(ctx, u.OrganizationId), u.Id, time.Now().Add(-LockDuration))
+	if err != nil && !errors.Is(err, repository.ErrMFANotFound) {
+		return entity.MFAChallenge{}, err
+	}
+
+	enabled := err == nil && m.Enabled
+
+	if !enabled && !s.required(u) {
+		return entity.MFAChallenge{}, nil
+	}
+
+	claims := challengeClaims{
+		UserId:         u.Id,
+		OrganizationId: u.OrganizationId,
+		Enroll:         !enabled,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(ChallengeTTL).Unix(),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.challengeKey())
+	if err != nil {
+		return entity.MFAChallenge{}, err
+	}
+
+	return entity.MFAChallenge{
+		Required:           true,
+		Token:              token,
+		EnrollmentRequired: !enabled,
+	}, nil
+}
+
+// EnrollChallenge enrolls the user of an MFA token who must enable two-factor
+// authentication to sign in.
+func (s service) EnrollChallenge(ctx context.Context, mfaToken string) (entity.TOTPEnrollment, error) {
+	claims, err := s.parseChallenge(mfaToken)
+	if err != nil {
+		return entity.TOTPEnrollment{}, err
+	}
+
+	if !claims.Enroll {
+		return entity.TOTPEnrollment{}, repository.ErrMFAEnabled
+	}
+
+	u, err := s.repository.GetUserById(tenant.WithOrganization(ctx, claims.OrganizationId), claims.UserId)
+	if err != nil {
+		return entity.TOTPEnrollment{}, err
+	}
+
+	return s.Enroll(ctx, u)
+}
+
+// VerifyChallenge checks the code of the user of an MFA token and returns the
+// user. Users enrolling get their recovery codes too.
+func (s service) VerifyChallenge(ctx context.Context, r entity.MFASignInRequest) (entity.User, []string, error) {
+	claims, err := s.parseChallenge(r.MFAToken)
+	if err != nil {
+		return entity.User{}, nil, err
+	}
+
+	ctx = tenant.WithOrganization(ctx, claims.OrganizationId)
+
+	u, err := s.repository.GetUserById(ctx, claims.UserId)
+	if err != nil {
+		return entity.User{}, nil, err
+	}
+
+	if claims.Enroll {
+		codes, err := s.Activate(ctx, u, r.Code)
+		if err != nil {
+			return entity.User{}, nil, err
+		}
+
+		return u, codes.RecoveryCodes, nil
+	}
+
+	m, err := s.get(ctx, u.Id)
+	if err != nil {
+		if errors.Is(err, repository.ErrMFANotFound) {
+			return entity.User{}, nil, ErrNotEnrolled
+		}
+		return entity.User{}, nil, err
+	}
+
+	if err := s.verify(ctx, m, r.Code); err != nil {
+		return entity.User{}, nil, err
+	}
+
+	return u, nil, nil
+}
+
+// get returns the two-factor authentication of the user, ErrLocked after too
+// many wrong codes.
+func (s service) get(ctx context.Context, userId int) (entity.UserMFA, error) {
+	m, err := s.repository.GetUserMFA(ctx, userId, time.Now().Add(-LockDuration))
+	if err != nil {
+		return entity.UserMFA{}, err
+	}
+
+	if m.FailedAttempts >= MaxFailedAttempts {
+		return entity.UserMFA{}, ErrLocked
+	}
+
+	return m, nil
+}
+
+// verify checks a code of the authenticator or a recovery code, each is
+// accepted once.
+func (s service) verify(ctx context.Context, m entity.UserMFA, code string) error {
+	code = normalize(code)
+
+	var err error
+
+	if step, ok := totp.Validate(m.Secret, code, time.Now()); ok {
+		err = s.repository.UseMFAStep(ctx, m.UserId, step)
+	} else {
+		err = s.repository.UseRecoveryCode(ctx, m.UserId, hash(code))
+	}
+
+	if errors.Is(err, repository.ErrMFACodeUsed) {
+		return s.fail(ctx, m.UserId)
+	}
+
+	return err
+}
+
+func (s service) fail(ctx context.Context, userId int) error {
+	now := time.Now().UTC()
+
+	if err := s.repository.FailMFA(ctx, userId, now, now.Add(-LockDuration)); err != nil {
+		return err
+	}
+	return ErrInvalidCode
+}
+
+func (s service) required(u entity.User) bool {
+	return s.config.RequiredForManagers && u.CodeRole == entity.ManagerRole
+}
+
+func (s service) parseChallenge(mfaToken string) (challengeClaims, error) {
+	claims := challengeClaims{}
+
+	_, err := jwt.ParseWithClaims(mfaToken, &claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %s", t.Header["alg"])
+		}
+		return s.challengeKey(), nil
+	})
+	if err != nil {
+		return challengeClaims{}, ErrInvalidChallenge
+	}
+
+	return claims, nil
+}
+
+// challengeKey signs the MFA tokens. It isn't the secret of the tokens of the
+// users, which would accept an MFA token in place of one.
+func (s service) challengeKey() []byte {
+	sum := sha256.Sum256([]byte("mfa-challenge " + s.jwtSecret))
+	return sum[:]
+}
+
+// newRecoveryCodes returns the codes shown to the user, like 3f9a1-c07b2, and
+// the hashes stored.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		code := hex.EncodeToString(b)
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = hash(code)
+	}
+
+	return codes, hashes, nil
+}
+
+// normalize accepts codes typed with spaces, dashes or in upper case.
+func normalize(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
+
+func hash(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
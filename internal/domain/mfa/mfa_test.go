@@ -0,0 +1,181 @@
+package mfa
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+	"github.com/lucas-simao/api-tasks/internal/totp"
+	"github.com/stretchr/testify/require"
+)
+
+const jwtSecret = "mfa-test-secret-of-32-characters"
+
+func newService(t *testing.T, c Config) (Service, repository.Repository, int) {
+	ctx := context.Background()
+
+	repo, err := repository.New(ctx, repository.Config{
+		Driver:     repository.DriverSQLite,
+		DataSource: filepath.Join(t.TempDir(), "api.db"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+
+	organizationId, err := repo.CreateOrganization(ctx, entity.Organization{Name: "acme", InviteCode: "acme-invite-code"})
+	require.NoError(t, err)
+
+	return New(repo, c, jwtSecret), repo, int(organizationId)
+}
+
+func signUp(t *testing.T, repo repository.Repository, organizationId int, username string, codeRole int) entity.User {
+	ctx := context.Background()
+
+	err := repo.SignUp(ctx, entity.SignUpRequest{
+		Name:           username,
+		Username:       username,
+		Password:       "123456",
+		CodeRole:       codeRole,
+		OrganizationId: organizationId,
+	})
+	require.NoError(t, err)
+
+	u, err := repo.SignIn(ctx, username)
+	require.NoError(t, err)
+
+	return u
+}
+
+// code returns the code of the secret skip steps from now, the next ones are
+// accepted once the current one was used.
+func code(t *testing.T, secret string, skip int64) string {
+	c, err := totp.Code(secret, totp.Step(time.Now())+skip)
+	require.NoError(t, err)
+	return c
+}
+
+func TestSignIn(t *testing.T) {
+	s, repo, organizationId := newService(t, Config{})
+	u := signUp(t, repo, organizationId, "ana", entity.TechnicianRole)
+	ctx := context.Background()
+
+	// without two-factor authentication the password is enough
+	challenge, err := s.Challenge(ctx, u)
+	require.NoError(t, err)
+	require.False(t, challenge.Required)
+
+	enrollment, err := s.Enroll(ctx, u)
+	require.NoError(t, err)
+	require.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+
+	// until a code is verified either
+	challenge, err = s.Challenge(ctx, u)
+	require.NoError(t, err)
+	require.False(t, challenge.Required)
+
+	// codes of steps far from now aren't accepted
+	_, err = s.Activate(ctx, u, code(t, enrollment.Secret, 10))
+	require.ErrorIs(t, err, ErrInvalidCode)
+
+	codes, err := s.Activate(ctx, u, code(t, enrollment.Secret, 0))
+	require.NoError(t, err)
+	require.Len(t, codes.RecoveryCodes, recoveryCodeCount)
+
+	challenge, err = s.Challenge(ctx, u)
+	require.NoError(t, err)
+	require.True(t, challenge.Required)
+	require.False(t, challenge.EnrollmentRequired)
+
+	signedIn, recoveryCodes, err := s.VerifyChallenge(ctx, entity.MFASignInRequest{MFAToken: challenge.Token, Code: code(t, enrollment.Secret, 1)})
+	require.NoError(t, err)
+	require.Equal(t, u.Id, signedIn.Id)
+	require.Empty(t, recoveryCodes)
+
+	// a code is accepted once
+	_, _, err = s.VerifyChallenge(ctx, entity.MFASignInRequest{MFAToken: challenge.Token, Code: code(t, enrollment.Secret, 1)})
+	require.ErrorIs(t, err, ErrInvalidCode)
+
+	// recovery codes too, typed in any case
+	recoveryCode := strings.ToUpper(codes.RecoveryCodes[0])
+
+	_, _, err = s.VerifyChallenge(ctx, entity.MFASignInRequest{MFAToken: challenge.Token, Code: recoveryCode})
+	require.NoError(t, err)
+
+	_, _, err = s.VerifyChallenge(ctx, entity.MFASignInRequest{MFAToken: challenge.Token, Code: recoveryCode})
+	require.ErrorIs(t, err, ErrInvalidCode)
+
+	// the tokens of the users aren't mfa tokens
+	_, _, err = s.VerifyChallenge(ctx, entity.MFASignInRequest{MFAToken: "not-a-token", Code: code(t, enrollment.Secret, 1)})
+	require.ErrorIs(t, err, ErrInvalidChallenge)
+
+	err = s.Disable(ctx, u, codes.RecoveryCodes[1])
+	require.NoError(t, err)
+
+	challenge, err = s.Challenge(ctx, u)
+	require.NoError(t, err)
+	require.False(t, challenge.Required)
+}
+
+func TestLock(t *testing.T) {
+	s, repo, organizationId := newService(t, Config{})
+	u := signUp(t, repo, organizationId, "ana", entity.TechnicianRole)
+	ctx := context.Background()
+
+	enrollment, err := s.Enroll(ctx, u)
+	require.NoError(t, err)
+
+	_, err = s.Activate(ctx, u, code(t, enrollment.Secret, 0))
+	require.NoError(t, err)
+
+	challenge, err := s.Challenge(ctx, u)
+	require.NoError(t, err)
+
+	for i := 0; i < MaxFailedAttempts; i++ {
+		_, _, err = s.VerifyChallenge(ctx, entity.MFASignInRequest{MFAToken: challenge.Token, Code: "wrong-code"})
+		require.ErrorIs(t, err, ErrInvalidCode)
+	}
+
+	// valid codes aren't accepted either once locked
+	_, _, err = s.VerifyChallenge(ctx, entity.MFASignInRequest{MFAToken: challenge.Token, Code: code(t, enrollment.Secret, 1)})
+	require.ErrorIs(t, err, ErrLocked)
+}
+
+func TestRequiredForManagers(t *testing.T) {
+	s, repo, organizationId := newService(t, Config{RequiredForManagers: true})
+	manager := signUp(t, repo, organizationId, "maria", entity.ManagerRole)
+	technician := signUp(t, repo, organizationId, "ana", entity.TechnicianRole)
+	ctx := context.Background()
+
+	challenge, err := s.Challenge(ctx, technician)
+	require.NoError(t, err)
+	require.False(t, challenge.Required)
+
+	// managers enroll on their next sign in
+	challenge, err = s.Challenge(ctx, manager)
+	require.NoError(t, err)
+	require.True(t, challenge.Required)
+	require.True(t, challenge.EnrollmentRequired)
+
+	enrollment, err := s.EnrollChallenge(ctx, challenge.Token)
+	require.NoError(t, err)
+
+	signedIn, recoveryCodes, err := s.VerifyChallenge(ctx, entity.MFASignInRequest{MFAToken: challenge.Token, Code: code(t, enrollment.Secret, 0)})
+	require.NoError(t, err)
+	require.Equal(t, manager.Id, signedIn.Id)
+	require.Len(t, recoveryCodes, recoveryCodeCount)
+
+	// the token of the enrolment can't replace the secret now
+	_, err = s.EnrollChallenge(ctx, challenge.Token)
+	require.ErrorIs(t, err, repository.ErrMFAEnabled)
+
+	challenge, err = s.Challenge(ctx, manager)
+	require.NoError(t, err)
+	require.True(t, challenge.Required)
+	require.False(t, challenge.EnrollmentRequired)
+
+	err = s.Disable(ctx, manager, code(t, enrollment.Secret, 1))
+	require.ErrorIs(t, err, ErrRequired)
+}
@@ -0,0 +1,12 @@
+package organizations
+
+import (
+	"context"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+type Service interface {
+	GetOrganization(context.Context) (entity.Organization, error)
+	RotateInviteCode(context.Context) (entity.Organization, error)
+}
@@ -0,0 +1,64 @@
+package organizations
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+)
+
+type service struct {
+	repository repository.Repository
+}
+
+func New(r repository.Repository) Service {
+	return service{
+		repository: r,
+	}
+}
+
+// GetOrganization returns the organization of the user calling.
+func (s service) GetOrganization(ctx context.Context) (entity.Organization, error) {
+	return s.repository.GetOrganization(ctx)
+}
+
+// RotateInviteCode gives the organization of the user calling a new invite
+// code, users can't sign up with the previous one anymore.
+func (s service) RotateInviteCode(ctx context.Context) (entity.Organization, error) {
+	inviteCode, err := NewInviteCode()
+	if err != nil {
+		return entity.Organization{}, err
+	}
+
+	var o entity.Organization
+
+	err = s.repository.WithTx(ctx, func(r repository.Repository) error {
+		err := r.UpdateInviteCode(ctx, inviteCode)
+		if err != nil {
+			return err
+		}
+
+		o, err = r.GetOrganization(ctx)
+		return err
+	})
+	if err != nil {
+		return entity.Organization{}, err
+	}
+
+	return o, nil
+}
+
+// NewInviteCode returns a random invite code, hard to guess since it's all a
+// user needs to join the organization.
+func NewInviteCode() (string, error) {
+	b := make([]byte, 16)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,12 @@
+package reports
+
+import (
+	"context"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+type Service interface {
+	GetTimesheet(context.Context, entity.TimesheetRequest) (entity.TimesheetResponse, error)
+	GetWeeklyTimesheet(context.Context, entity.TimesheetRequest) (entity.WeeklyTimesheetResponse, error)
+}
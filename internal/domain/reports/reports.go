@@ -0,0 +1,191 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+)
+
+const DateLayout = "2006-01-02"
+
+var ErrUserOutOfScope = apperror.Forbidden("user_out_of_scope", "user don't have permission to see the timesheet of this user")
+
+type service struct {
+	repository repository.Repository
+}
+
+func New(r repository.Repository) Service {
+	return service{
+		repository: r,
+	}
+}
+
+// GetTimesheet builds the timesheet of a user for the UTC day containing date.
+func (s service) GetTimesheet(ctx context.Context, r entity.TimesheetRequest) (entity.TimesheetResponse, error) {
+	user, err := s.user(ctx, r)
+	if err != nil {
+		return entity.TimesheetResponse{}, err
+	}
+
+	day := truncateDay(r.Date)
+
+	entries, finished, err := s.getPeriod(ctx, user.Id, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return entity.TimesheetResponse{}, err
+	}
+
+	return buildTimesheet(user, day, entries, finished), nil
+}
+
+// GetWeeklyTimesheet builds one timesheet per day for the week (Monday to
+// Sunday, UTC) containing date.
+func (s service) GetWeeklyTimesheet(ctx context.Context, r entity.TimesheetRequest) (entity.WeeklyTimesheetResponse, error) {
+	user, err := s.user(ctx, r)
+	if err != nil {
+		return entity.WeeklyTimesheetResponse{}, err
+	}
+
+	day := truncateDay(r.Date)
+	weekStart := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	weekEnd := weekStart.AddDate(0, 0, 7)
+
+	entries, finished, err := s.getPeriod(ctx, user.Id, weekStart, weekEnd)
+	if err != nil {
+		return entity.WeeklyTimesheetResponse{}, err
+	}
+
+	weekly := entity.WeeklyTimesheetResponse{
+		UserId:    user.Id,
+		UserName:  user.Name,
+		WeekStart: weekStart.Format(DateLayout),
+		WeekEnd:   weekEnd.AddDate(0, 0, -1).Format(DateLayout),
+		Days:      make([]entity.TimesheetResponse, 0, 7),
+	}
+
+	for d := weekStart; d.Before(weekEnd); d = d.AddDate(0, 0, 1) {
+		timesheet := buildTimesheet(user, d, entries, finished)
+		weekly.TotalSeconds += timesheet.TotalSeconds
+		weekly.Days = append(weekly.Days, timesheet)
+	}
+
+	return weekly, nil
+}
+
+// user returns the user of the timesheet, ErrUserOutOfScope when the viewer
+// can't see it, managers only see the timesheets of their teammates.
+func (s service) user(ctx context.Context, r entity.TimesheetRequest) (entity.User, error) {
+	user, err := s.repository.GetUserInScope(ctx, r.UserId, r.ViewerId, r.Scope)
+	if errors.Is(err, repository.ErrUserNotExist) && r.Scope != entity.ScopeAny {
+		return entity.User{}, ErrUserOutOfScope
+	}
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return user, nil
+}
+
+func (s service) getPeriod(ctx context.Context, userId int, from, to time.Time) ([]entity.WorkLogEntry, []entity.FinishedTask, error) {
+	entries, err := s.repository.GetWorkLogEntriesByUser(ctx, userId, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	finished, err := s.repository.GetFinishedTasksByUser(ctx, userId, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return entries, finished, nil
+}
+
+// buildTimesheet clips the entries to the day, aggregates them per task and
+// reports the idle time between consecutive entries. Entries must be sorted by
+// start time and, as work logs of one user never overlap, are laid out linearly.
+func buildTimesheet(user entity.User, day time.Time, entries []entity.WorkLogEntry, finished []entity.FinishedTask) entity.TimesheetResponse {
+	dayEnd := day.AddDate(0, 0, 1)
+
+	timesheet := entity.TimesheetResponse{
+		UserId:   user.Id,
+		UserName: user.Name,
+		Date:     day.Format(DateLayout),
+		Tasks:    []entity.TimesheetTask{},
+		Gaps:     []entity.TimesheetGap{},
+	}
+
+	positions := map[int]int{}
+	var lastEnd time.Time
+
+	for _, e := range entries {
+		start, end := e.StartedAt.UTC(), e.EndedAt.UTC()
+		if start.Before(day) {
+			start = day
+		}
+		if end.After(dayEnd) {
+			end = dayEnd
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		if !lastEnd.IsZero() && start.After(lastEnd) {
+			gap := int64(start.Sub(lastEnd).Seconds())
+			timesheet.GapSeconds += gap
+			timesheet.Gaps = append(timesheet.Gaps, entity.TimesheetGap{
+				StartedAt:       lastEnd.Format(time.RFC3339),
+				EndedAt:         start.Format(time.RFC3339),
+				DurationSeconds: gap,
+			})
+		}
+		if end.After(lastEnd) {
+			lastEnd = end
+		}
+
+		i, ok := positions[e.TaskId]
+		if !ok {
+			i = len(timesheet.Tasks)
+			positions[e.TaskId] = i
+			timesheet.Tasks = append(timesheet.Tasks, entity.TimesheetTask{
+				TaskId:    e.TaskId,
+				Title:     e.TaskTitle,
+				StartedAt: start.Format(time.RFC3339),
+			})
+		}
+
+		duration := int64(end.Sub(start).Seconds())
+		timesheet.Tasks[i].EndedAt = end.Format(time.RFC3339)
+		timesheet.Tasks[i].DurationSeconds += duration
+		timesheet.Tasks[i].Entries++
+		timesheet.TotalSeconds += duration
+	}
+
+	for _, f := range finished {
+		finishedAt := f.FinishedAt.UTC()
+		if finishedAt.Before(day) || !finishedAt.Before(dayEnd) {
+			continue
+		}
+
+		i, ok := positions[f.Id]
+		if !ok {
+			i = len(timesheet.Tasks)
+			positions[f.Id] = i
+			timesheet.Tasks = append(timesheet.Tasks, entity.TimesheetTask{
+				TaskId: f.Id,
+				Title:  f.Title,
+			})
+		}
+
+		timesheet.Tasks[i].FinishedAt = finishedAt.Format(time.RFC3339)
+	}
+
+	return timesheet
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,19 @@
+package sso
+
+import (
+	"context"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/gateway/oidc"
+)
+
+type Service interface {
+	Start(context.Context) (string, string, error)
+	SignIn(ctx context.Context, flow, code, state string) (entity.SignInResponse, error)
+}
+
+// Provider is the identity provider users sign in at, see oidc.Provider.
+type Provider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, verifier, nonce string) (oidc.Claims, error)
+}
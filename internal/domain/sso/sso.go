@@ -0,0 +1,282 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/domain/mfa"
+	"github.com/lucas-simao/api-tasks/internal/domain/users"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/gateway/oidc"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+	"github.com/lucas-simao/api-tasks/internal/tenant"
+	"github.com/lucas-simao/api-tasks/internal/utils"
+)
+
+// FlowTTL is the time users have to sign in at the provider.
+const FlowTTL = 10 * time.Minute
+
+var ErrInvalidFlow = apperror.Unauthorized("invalid_oidc_flow", "sign in expired or was started in another browser")
+
+type Config struct {
+	// OrganizationId is the organization of the users signing in for the
+	// first time.
+	OrganizationId int
+	// ManagerGroup and TechnicianGroup give their members the role at every
+	// sign in, the role of users of no group isn't changed.
+	ManagerGroup    string
+	TechnicianGroup string
+}
+
+type service struct {
+	repository repository.Repository
+	provider   Provider
+	mfa        mfa.Service
+	config     Config
+	jwtSecret  string
+}
+
+func New(r repository.Repository, p Provider, m mfa.Service, c Config, jwtSecret string) Service {
+	return service{
+		repository: r,
+		provider:   p,
+		mfa:        m,
+		config:     c,
+		jwtSecret:  jwtSecret,
+	}
+}
+
+// flowClaims are the secrets of a sign in, kept by the browser in a cookie
+// until the provider redirects it back.
+type flowClaims struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	jwt.StandardClaims
+}
+
+// Start returns the url of the provider to redirect the user to, and the flow
+// the callback is checked with.
+func (s service) Start(ctx context.Context) (string, string, error) {
+	var values [3]string
+
+	for i := range values {
+		v, err := random(32)
+		if err != nil {
+			return "", "", err
+		}
+		values[i] = v
+	}
+
+	f := flowClaims{
+		State:    values[0],
+		Nonce:    values[1],
+		Verifier: values[2],
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(FlowTTL).Unix(),
+		},
+	}
+
+	url, err := s.provider.AuthCodeURL(ctx, f.State, f.Nonce, f.Verifier)
+	if err != nil {
+		return "", "", err
+	}
+
+	flow, err := jwt.NewWithClaims(jwt.SigningMethodHS256, f).SignedString(s.flowKey())
+	if err != nil {
+		return "", "", err
+	}
+
+	return url, flow, nil
+}
+
+// SignIn exchanges the code of the callback and returns the token of the user
+// of the provider, provisioned as a visitor when signing in for the first time.
+// Visitors get ErrUserWithoutValidRole, as with passwords, until a manager or
+// a group of the provider gives them a role. Users with two-factor
+// authentication get the MFA token instead, as with passwords.
+func (s service) SignIn(ctx context.Context, flow, code, state string) (entity.SignInResponse, error) {
+	f := flowClaims{}
+
+	_, err := jwt.ParseWithClaims(flow, &f, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %s", t.Header["alg"])
+		}
+		return s.flowKey(), nil
+	})
+	if err != nil || state == "" || f.State != state {
+		return entity.SignInResponse{}, ErrInvalidFlow
+	}
+
+	claims, err := s.provider.Exchange(ctx, code, f.Verifier, f.Nonce)
+	if err != nil {
+		return entity.SignInResponse{}, err
+	}
+
+	user, err := s.user(ctx, claims)
+	if err != nil {
+		return entity.SignInResponse{}, err
+	}
+
+	if user.CodeRole == entity.VisitorRole {
+		return entity.SignInResponse{}, users.ErrUserWithoutValidRole
+	}
+
+	// the provider signing in managers of ManagerGroup doesn't skip the codes
+	challenge, err := s.mfa.Challenge(ctx, user)
+	if err != nil {
+		return entity.SignInResponse{}, err
+	}
+
+	if challenge.Required {
+		return entity.SignInResponse{
+			MFAToken:              challenge.Token,
+			MFAEnrollmentRequired: challenge.EnrollmentRequired,
+		}, nil
+	}
+
+	token, err := utils.GenerateToken(s.jwtSecret, user)
+	if err != nil {
+		return entity.SignInResponse{}, err
+	}
+
+	return entity.SignInResponse{Token: token}, nil
+}
+
+// user returns the user linked to the subject of the claims, provisioning it
+// when there's none, with the role of its groups.
+func (s service) user(ctx context.Context, claims oidc.Claims) (entity.User, error) {
+	var u entity.User
+
+	err := s.repository.WithTx(ctx, func(r repository.Repository) error {
+		var err error
+
+		u, err = r.GetUserByIdentity(ctx, claims.Issuer, claims.Subject)
+		if errors.Is(err, repository.ErrUserNotExist) {
+			u, err = s.provision(ctx, r, claims)
+		}
+		if err != nil {
+			return err
+		}
+
+		role, ok := s.role(claims.Groups)
+		if !ok || role == u.CodeRole || u.CodeRole == entity.ServiceRole {
+			return nil
+		}
+
+		err = r.UpdateUserRole(tenant.WithOrganization(ctx, u.OrganizationId), u.Id, role)
+		if err != nil {
+			return err
+		}
+
+		u.CodeRole = role
+
+		return nil
+	})
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return u, nil
+}
+
+// provision creates a visitor without a password for the subject of the
+// claims. Users aren't linked to existing accounts by username or email, the
+// provider could sign in anyone as them, so a taken username gets one made
+// of the subject.
+func (s service) provision(ctx context.Context, r repository.Repository, claims oidc.Claims) (entity.User, error) {
+	sum := sha256.Sum256([]byte(claims.Issuer + " " + claims.Subject))
+	fallback := "sso-" + hex.EncodeToString(sum[:])[:26]
+
+	username := truncate(first(claims.PreferredUsername, claims.Email, fallback), 30)
+
+	u := entity.SignUpRequest{
+		Name:           truncate(first(claims.Name, claims.PreferredUsername, claims.Email, claims.Subject), 50),
+		Username:       username,
+		OrganizationId: s.config.OrganizationId,
+		CodeRole:       entity.VisitorRole,
+	}
+
+	ctx = tenant.WithOrganization(ctx, s.config.OrganizationId)
+
+	err := r.SignUp(ctx, u)
+	if errors.Is(err, repository.ErrUsernameUnavailable) && u.Username != fallback {
+		u.Username = fallback
+		err = r.SignUp(ctx, u)
+	}
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	user, err := r.SignIn(ctx, u.Username)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	err = r.AddUserIdentity(ctx, entity.UserIdentity{
+		Issuer:  claims.Issuer,
+		Subject: claims.Subject,
+		UserId:  user.Id,
+	})
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return user, nil
+}
+
+// role returns the role of the groups, manager wins when the user is in both.
+func (s service) role(groups []string) (int, bool) {
+	technician := false
+
+	for _, g := range groups {
+		switch {
+		case s.config.ManagerGroup != "" && g == s.config.ManagerGroup:
+			return entity.ManagerRole, true
+		case s.config.TechnicianGroup != "" && g == s.config.TechnicianGroup:
+			technician = true
+		}
+	}
+
+	return entity.TechnicianRole, technician
+}
+
+// flowKey signs the flows, derived from the secret of the tokens so a flow is
+// never accepted as a token.
+func (s service) flowKey() []byte {
+	sum := sha256.Sum256([]byte("oidc-flow " + s.jwtSecret))
+	return sum[:]
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
+
+func random(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,181 @@
+package sso
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/lucas-simao/api-tasks/internal/domain/mfa"
+	"github.com/lucas-simao/api-tasks/internal/domain/users"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/gateway/oidc"
+	"github.com/lucas-simao/api-tasks/internal/gateway/oidc/oidctest"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+const jwtSecret = "sso-test-secret-of-32-characters"
+
+func newService(t *testing.T, idp *oidctest.Provider, c mfa.Config) (Service, repository.Repository) {
+	ctx := context.Background()
+
+	repo, err := repository.New(ctx, repository.Config{
+		Driver:     repository.DriverSQLite,
+		DataSource: filepath.Join(t.TempDir(), "api.db"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+
+	organizationId, err := repo.CreateOrganization(ctx, entity.Organization{Name: "acme", InviteCode: "acme-invite-code"})
+	require.NoError(t, err)
+
+	provider := oidc.New(idp.Config("http://localhost:9000/sign-in/oidc/callback"), nil)
+
+	return New(repo, provider, mfa.New(repo, c, jwtSecret), Config{
+		OrganizationId:  int(organizationId),
+		ManagerGroup:    "maintenance-managers",
+		TechnicianGroup: "maintenance-technicians",
+	}, jwtSecret), repo
+}
+
+// signIn signs in the user of the provider as a browser would.
+func signIn(t *testing.T, s Service, idp *oidctest.Provider) (entity.SignInResponse, error) {
+	ctx := context.Background()
+
+	url, flow, err := s.Start(ctx)
+	require.NoError(t, err)
+
+	code, state, err := idp.Authorize(url)
+	require.NoError(t, err)
+
+	return s.SignIn(ctx, flow, code, state)
+}
+
+func claims(t *testing.T, token string) entity.JwtCustomClaims {
+	c := entity.JwtCustomClaims{}
+
+	_, err := jwt.ParseWithClaims(token, &c, func(*jwt.Token) (interface{}, error) {
+		return []byte(jwtSecret), nil
+	})
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestSignIn(t *testing.T) {
+	idp := oidctest.New(oidctest.User{Subject: "248289761001", Name: "Jane Doe", PreferredUsername: "jane"})
+	defer idp.Close()
+
+	s, repo := newService(t, idp, mfa.Config{})
+
+	// the first sign in provisions a visitor
+	_, err := signIn(t, s, idp)
+	require.ErrorIs(t, err, users.ErrUserWithoutValidRole)
+
+	user, err := repo.GetUserByIdentity(context.Background(), idp.Issuer(), "248289761001")
+	require.NoError(t, err)
+	require.Equal(t, "jane", user.Username)
+	require.Equal(t, "Jane Doe", user.Name)
+	require.Equal(t, entity.VisitorRole, user.CodeRole)
+
+	// the groups of the provider give the role
+	idp.SetUser(oidctest.User{Subject: "248289761001", Name: "Jane Doe", PreferredUsername: "jane", Groups: []string{"maintenance-technicians"}})
+
+	response, err := signIn(t, s, idp)
+	require.NoError(t, err)
+
+	c := claims(t, response.Token)
+	require.Equal(t, user.Id, c.Id)
+	require.Equal(t, entity.TechnicianRole, c.CodeRole)
+	require.Equal(t, user.OrganizationId, c.OrganizationId)
+
+	// manager wins, and users of no group keep their role
+	idp.SetUser(oidctest.User{Subject: "248289761001", Groups: []string{"maintenance-technicians", "maintenance-managers"}})
+
+	response, err = signIn(t, s, idp)
+	require.NoError(t, err)
+	require.Equal(t, entity.ManagerRole, claims(t, response.Token).CodeRole)
+
+	idp.SetUser(oidctest.User{Subject: "248289761001"})
+
+	response, err = signIn(t, s, idp)
+	require.NoError(t, err)
+	require.Equal(t, entity.ManagerRole, claims(t, response.Token).CodeRole)
+}
+
+func TestSignInDoesNotLinkExistingUsers(t *testing.T) {
+	idp := oidctest.New(oidctest.User{Subject: "248289761001", PreferredUsername: "jane", Groups: []string{"maintenance-technicians"}})
+	defer idp.Close()
+
+	s, repo := newService(t, idp, mfa.Config{})
+	ctx := context.Background()
+
+	err := repo.SignUp(ctx, entity.SignUpRequest{Name: "Jane", Username: "jane", Password: "hash", OrganizationId: 1, CodeRole: entity.ManagerRole})
+	require.NoError(t, err)
+
+	existing, err := repo.SignIn(ctx, "jane")
+	require.NoError(t, err)
+
+	response, err := signIn(t, s, idp)
+	require.NoError(t, err)
+
+	c := claims(t, response.Token)
+	require.NotEqual(t, existing.Id, c.Id)
+	require.Regexp(t, `^sso-[0-9a-f]{26}$`, c.Username)
+	require.Equal(t, entity.TechnicianRole, c.CodeRole)
+}
+
+func TestSignInChecksTheFlow(t *testing.T) {
+	idp := oidctest.New(oidctest.User{Subject: "248289761001", Groups: []string{"maintenance-technicians"}})
+	defer idp.Close()
+
+	s, _ := newService(t, idp, mfa.Config{})
+	ctx := context.Background()
+
+	url, flow, err := s.Start(ctx)
+	require.NoError(t, err)
+
+	code, state, err := idp.Authorize(url)
+	require.NoError(t, err)
+
+	_, err = s.SignIn(ctx, flow, code, "another state")
+	require.ErrorIs(t, err, ErrInvalidFlow)
+
+	_, err = s.SignIn(ctx, flow+"x", code, state)
+	require.ErrorIs(t, err, ErrInvalidFlow)
+
+	// flows aren't accepted as tokens
+	_, err = jwt.ParseWithClaims(flow, &entity.JwtCustomClaims{}, func(*jwt.Token) (interface{}, error) {
+		return []byte(jwtSecret), nil
+	})
+	require.Error(t, err)
+}
+
+func TestSignInWithTwoFactorAuthentication(t *testing.T) {
+	idp := oidctest.New(oidctest.User{Subject: "248289761001", PreferredUsername: "jane", Groups: []string{"maintenance-managers"}})
+	defer idp.Close()
+
+	s, _ := newService(t, idp, mfa.Config{RequiredForManagers: true})
+
+	// managers of the group of the provider enroll as with passwords
+	response, err := signIn(t, s, idp)
+	require.NoError(t, err)
+	require.Empty(t, response.Token)
+	require.NotEmpty(t, response.MFAToken)
+	require.True(t, response.MFAEnrollmentRequired)
+
+	// mfa tokens aren't accepted as tokens
+	_, err = jwt.ParseWithClaims(response.MFAToken, &entity.JwtCustomClaims{}, func(*jwt.Token) (interface{}, error) {
+		return []byte(jwtSecret), nil
+	})
+	require.Error(t, err)
+
+	// technicians aren't required to
+	idp.SetUser(oidctest.User{Subject: "248289761002", PreferredUsername: "joe", Groups: []string{"maintenance-technicians"}})
+
+	response, err = signIn(t, s, idp)
+	require.NoError(t, err)
+	require.NotEmpty(t, response.Token)
+	require.Empty(t, response.MFAToken)
+}
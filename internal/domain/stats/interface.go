@@ -0,0 +1,14 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+type Service interface {
+	GetStats(ctx context.Context, userId int, from, to time.Time, top int) (entity.StatsResponse, error)
+	// Invalidate drops the cached statistics, it is called whenever tasks change.
+	Invalidate()
+}
@@ -0,0 +1,133 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+	"github.com/lucas-simao/api-tasks/internal/tenant"
+)
+
+const (
+	DateLayout = "2006-01-02"
+	cacheTTL   = 30 * time.Second
+	// cacheSize bounds the cached results, the periods are chosen by the
+	// clients
+	cacheSize = 1000
+)
+
+type cached struct {
+	stats     entity.StatsResponse
+	expiresAt time.Time
+}
+
+type service struct {
+	repository repository.Repository
+
+	mu    sync.Mutex
+	cache map[string]cached
+	// generation changes on every Invalidate, results loaded before aren't
+	// cached
+	generation uint64
+}
+
+func New(r repository.Repository) Service {
+	return &service{
+		repository: r,
+		cache:      map[string]cached{},
+	}
+}
+
+// GetStats aggregates the tasks of the teammates of the user in the period
+// [from, to) keeping the result for a short time, as dashboards usually poll
+// the same period. Results are cached per user, managers only see their teams.
+func (s *service) GetStats(ctx context.Context, userId int, from, to time.Time, top int) (entity.StatsResponse, error) {
+	organizationId, _ := tenant.Organization(ctx)
+
+	key := fmt.Sprintf("%d:%d:%d:%d:%d", organizationId, userId, from.Unix(), to.Unix(), top)
+
+	s.mu.Lock()
+	entry, ok := s.cache[key]
+	generation := s.generation
+	s.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.stats, nil
+	}
+
+	stats, err := s.load(ctx, userId, from, to, top)
+	if err != nil {
+		return entity.StatsResponse{}, err
+	}
+
+	s.mu.Lock()
+	if s.generation == generation {
+		s.store(key, stats)
+	}
+	s.mu.Unlock()
+
+	return stats, nil
+}
+
+func (s *service) Invalidate() {
+	s.mu.Lock()
+	s.generation++
+	s.cache = map[string]cached{}
+	s.mu.Unlock()
+}
+
+// store caches the stats of the key, evicting the expired results first and
+// any result when the cache is still full. s.mu must be held.
+func (s *service) store(key string, stats entity.StatsResponse) {
+	now := time.Now()
+
+	for k, entry := range s.cache {
+		if !now.Before(entry.expiresAt) {
+			delete(s.cache, k)
+		}
+	}
+
+	for k := range s.cache {
+		if len(s.cache) < cacheSize {
+			break
+		}
+		delete(s.cache, k)
+	}
+
+	s.cache[key] = cached{stats: stats, expiresAt: now.Add(cacheTTL)}
+}
+
+func (s *service) load(ctx context.Context, userId int, from, to time.Time, top int) (entity.StatsResponse, error) {
+	perDay, err := s.repository.GetTasksPerDay(ctx, userId, from, to)
+	if err != nil {
+		return entity.StatsResponse{}, err
+	}
+
+	mean, err := s.repository.GetMeanTimeToFinish(ctx, userId, from, to)
+	if err != nil {
+		return entity.StatsResponse{}, err
+	}
+
+	backlog, err := s.repository.GetOpenBacklogByTechnician(ctx, userId)
+	if err != nil {
+		return entity.StatsResponse{}, err
+	}
+
+	topTechnicians, err := s.repository.GetTopTechniciansByFinished(ctx, userId, from, to, top)
+	if err != nil {
+		return entity.StatsResponse{}, err
+	}
+
+	return entity.StatsResponse{
+		From:                    from.Format(DateLayout),
+		To:                      to.AddDate(0, 0, -1).Format(DateLayout),
+		TasksPerDay:             perDay,
+		MeanTimeToFinishSeconds: int64(math.Round(mean)),
+		OpenBacklog:             backlog,
+		TopTechnicians:          topTechnicians,
+	}, nil
+}
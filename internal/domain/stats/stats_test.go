@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// countedRepository counts the loads, onLoad runs during each one.
+type countedRepository struct {
+	repository.Repository
+	loads  *int
+	onLoad func()
+}
+
+func (r countedRepository) GetTasksPerDay(context.Context, int, time.Time, time.Time) ([]entity.TasksPerDay, error) {
+	*r.loads++
+	if r.onLoad != nil {
+		r.onLoad()
+	}
+	return []entity.TasksPerDay{}, nil
+}
+
+func (countedRepository) GetMeanTimeToFinish(context.Context, int, time.Time, time.Time) (float64, error) {
+	return 0, nil
+}
+
+func (countedRepository) GetOpenBacklogByTechnician(context.Context, int) ([]entity.TechnicianTaskCount, error) {
+	return []entity.TechnicianTaskCount{}, nil
+}
+
+func (countedRepository) GetTopTechniciansByFinished(context.Context, int, time.Time, time.Time, int) ([]entity.TechnicianTaskCount, error) {
+	return []entity.TechnicianTaskCount{}, nil
+}
+
+func TestInvalidateDuringLoad(t *testing.T) {
+	var loads int
+	s := &service{cache: map[string]cached{}}
+	s.repository = countedRepository{loads: &loads, onLoad: func() {
+		// a task changed while the stats were loaded
+		if loads == 1 {
+			s.Invalidate()
+		}
+	}}
+
+	ctx := context.Background()
+	from := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	_, err := s.GetStats(ctx, 1, from, to, 5)
+	require.NoError(t, err)
+	require.Empty(t, s.cache, "shouldn't cache a result loaded before the invalidation")
+
+	_, err = s.GetStats(ctx, 1, from, to, 5)
+	require.NoError(t, err)
+	_, err = s.GetStats(ctx, 1, from, to, 5)
+	require.NoError(t, err)
+	require.Equal(t, 2, loads)
+}
+
+func TestCacheSize(t *testing.T) {
+	var loads int
+	s := New(countedRepository{loads: &loads}).(*service)
+
+	ctx := context.Background()
+	from := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < cacheSize+10; i++ {
+		_, err := s.GetStats(ctx, 1, from, from.AddDate(0, 0, i+1), 5)
+		require.NoError(t, err)
+	}
+	require.Len(t, s.cache, cacheSize)
+
+	// expired results are evicted on the next write
+	for key, entry := range s.cache {
+		entry.expiresAt = time.Now()
+		s.cache[key] = entry
+	}
+
+	_, err := s.GetStats(ctx, 1, from, from.AddDate(0, 0, 1), 5)
+	require.NoError(t, err)
+	require.Len(t, s.cache, 1)
+}
@@ -0,0 +1,135 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+)
+
+var ErrInvalidAssignee = apperror.Invalid("invalid_assignee", "tasks can only be reassigned to users of your teams working on tasks")
+
+// BulkTasks applies one action to several tasks, see repository.BulkUpdateTasks.
+func (s service) BulkTasks(ctx context.Context, b entity.BulkTaskRequest) (entity.BulkTaskResponse, error) {
+	if b.Mode == "" {
+		b.Mode = entity.BulkModeTransaction
+	}
+
+	seen := make(map[int]bool, len(b.Ids))
+	ids := make([]int, 0, len(b.Ids))
+
+	for _, id := range b.Ids {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	b.Ids = ids
+
+	var response entity.BulkTaskResponse
+	var err error
+
+	if b.Mode == entity.BulkModeTransaction {
+		// the assignee is checked in the transaction of the update
+		err = s.repository.WithTx(ctx, func(r repository.Repository) error {
+			err := checkAssignee(ctx, r, b)
+			if err != nil {
+				return err
+			}
+
+			response, err = r.BulkUpdateTasks(ctx, b)
+			return err
+		})
+	} else {
+		// each task is updated in its own transaction by the repository
+		err = checkAssignee(ctx, s.repository, b)
+		if err == nil {
+			response, err = s.repository.BulkUpdateTasks(ctx, b)
+		}
+	}
+	if err != nil {
+		return entity.BulkTaskResponse{}, err
+	}
+
+	for _, r := range response.Results {
+		if r.Err != nil {
+			s.logger.ErrorContext(ctx, "error to apply bulk action", slog.Int("task_id", r.Id), slog.Any("error", r.Err))
+		}
+	}
+
+	if response.Succeeded == 0 {
+		return response, nil
+	}
+
+	s.changed()
+
+	if b.Action == entity.BulkActionFinish {
+		s.recorder.TasksFinished(b.CodeRole, response.Succeeded)
+
+		for _, r := range response.Results {
+			if r.Status != entity.BulkStatusOk {
+				continue
+			}
+
+			task, err := s.repository.GetTaskById(ctx, r.Id, b.UserId, entity.ScopeOwn)
+			if err != nil {
+				continue
+			}
+
+			s.notify(ctx, task)
+		}
+	}
+
+	return response, nil
+}
+
+// checkAssignee checks the tasks are reassigned to a user of the teams of the
+// manager able to work on them.
+func checkAssignee(ctx context.Context, r repository.Repository, b entity.BulkTaskRequest) error {
+	if b.Action != entity.BulkActionReassign {
+		return nil
+	}
+
+	assignee, err := r.GetUserById(ctx, b.AssigneeId)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotExist) {
+			return ErrInvalidAssignee.Withf("user %d don't exist", b.AssigneeId)
+		}
+		return err
+	}
+
+	// the assignee must be able to work on the tasks
+	permissions, err := r.GetPermissionsByRole(ctx, assignee.CodeRole)
+	if err != nil {
+		return err
+	}
+
+	if !entity.NewPermissions(permissions...).Allows(entity.PermissionTaskUpdateOwn) {
+		return ErrInvalidAssignee
+	}
+
+	managers, err := r.GetTeamManagersByUser(ctx, b.AssigneeId)
+	if err != nil {
+		return err
+	}
+
+	if !containsUser(managers, b.UserId) {
+		return ErrInvalidAssignee.Withf("user %d isn't in your teams", b.AssigneeId)
+	}
+
+	return nil
+}
+
+func containsUser(users []entity.User, id int) bool {
+	for _, u := range users {
+		if u.Id == id {
+			return true
+		}
+	}
+
+	return false
+}
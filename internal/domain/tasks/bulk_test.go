@@ -0,0 +1,66 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/logging"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// bulkRepository counts the transactions and the checks of the assignee.
+type bulkRepository struct {
+	fakeRepository
+	transactions, assignees *int
+}
+
+func (r bulkRepository) WithTx(ctx context.Context, fn func(repository.Repository) error) error {
+	*r.transactions++
+	return fn(r)
+}
+
+func (r bulkRepository) GetUserById(context.Context, int) (entity.User, error) {
+	*r.assignees++
+	return entity.User{Id: 1, CodeRole: entity.TechnicianRole}, nil
+}
+
+func (bulkRepository) GetPermissionsByRole(context.Context, int) ([]string, error) {
+	return []string{entity.PermissionTaskUpdateOwn}, nil
+}
+
+func (bulkRepository) GetTeamManagersByUser(context.Context, int) ([]entity.User, error) {
+	return []entity.User{{Id: 2, CodeRole: entity.ManagerRole}}, nil
+}
+
+func (bulkRepository) BulkUpdateTasks(_ context.Context, b entity.BulkTaskRequest) (entity.BulkTaskResponse, error) {
+	return entity.BulkTaskResponse{Action: b.Action, Mode: b.Mode, Failed: len(b.Ids)}, nil
+}
+
+func TestBulkTasksModes(t *testing.T) {
+	cases := map[string]int{
+		entity.BulkModeTransaction: 1,
+		// each task has its own transaction in the repository
+		entity.BulkModePerItem: 0,
+	}
+
+	for mode, transactions := range cases {
+		t.Run(mode, func(t *testing.T) {
+			var tx, assignees int
+			s := New(bulkRepository{transactions: &tx, assignees: &assignees}, nil, fakeRecorder{}, logging.Discard())
+
+			_, err := s.BulkTasks(context.Background(), entity.BulkTaskRequest{
+				Action:     entity.BulkActionReassign,
+				Mode:       mode,
+				Ids:        []int{1, 2, 3},
+				AssigneeId: 1,
+				UserId:     2,
+				Scope:      entity.ScopeTeam,
+			})
+			require.NoError(t, err)
+			require.Equal(t, transactions, tx)
+			require.Equal(t, 1, assignees)
+		})
+	}
+}
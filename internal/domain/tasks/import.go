@@ -0,0 +1,251 @@
+package tasks
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+var (
+	ErrImportMissingColumn = apperror.Invalid("import_missing_column", "import file is missing a required column")
+	ErrImportTooManyRows   = apperror.Invalid("import_too_many_rows", "import file has too many rows")
+	ErrImportInterrupted   = apperror.Unavailable("import_interrupted", "import stopped, the rows before resumeFromRow were imported")
+
+	importBatchSize = 500
+	importMaxRows   = 100000
+
+	importRequiredColumns = []string{"title", "description", "username"}
+
+	// performed_at is accepted as an alias of finished_at, a task is performed
+	// when the technician finishes it.
+	importColumnAliases = map[string]string{
+		"performed_at": "finished_at",
+	}
+
+	importTimeLayouts = []string{
+		time.RFC3339,
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04:05",
+		"2006-01-02",
+	}
+)
+
+// ImportTasks reads historical tasks from a CSV file with the columns title,
+// description, username, created_at and finished_at. Every row is validated and
+// reported, the users of the rows must be teammates of the user importing. On
+// dry run nothing is written, otherwise valid rows are inserted in batches,
+// each batch in its own transaction. When a batch fails the report of the
+// batches imported before it is returned with ErrImportInterrupted.
+func (s service) ImportTasks(ctx context.Context, userId int, r io.Reader, dryRun bool) (entity.TaskImportReport, error) {
+	report := entity.TaskImportReport{
+		DryRun: dryRun,
+		Errors: []entity.TaskImportError{},
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return report, fmt.Errorf("error to read header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if alias, ok := importColumnAliases[name]; ok {
+			name = alias
+		}
+		columns[name] = i
+	}
+
+	for _, name := range importRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return report, ErrImportMissingColumn.Withf("import file is missing the column %s", name)
+		}
+	}
+
+	var rows []entity.TaskImport
+	usernames := map[string]bool{}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		report.Total++
+
+		if report.Total > importMaxRows {
+			return report, ErrImportTooManyRows.Withf("import file has more than %d rows", importMaxRows)
+		}
+
+		if err != nil {
+			report.Errors = append(report.Errors, entity.TaskImportError{Row: line, Message: err.Error()})
+			continue
+		}
+
+		row, rowErrors := parseImportRow(line, record, columns)
+		if len(rowErrors) > 0 {
+			report.Errors = append(report.Errors, rowErrors...)
+			continue
+		}
+
+		usernames[row.Username] = true
+		rows = append(rows, row)
+	}
+
+	names := make([]string, 0, len(usernames))
+	for name := range usernames {
+		names = append(names, name)
+	}
+
+	userIds, err := s.repository.GetUserIdsByUsernames(ctx, userId, names)
+	if err != nil {
+		return report, err
+	}
+
+	valid := make([]entity.TaskImport, 0, len(rows))
+
+	for _, row := range rows {
+		userId, ok := userIds[row.Username]
+		if !ok {
+			report.Errors = append(report.Errors, entity.TaskImportError{
+				Row:     row.Row,
+				Field:   "username",
+				Message: fmt.Sprintf("user %q isn't in your teams", row.Username),
+			})
+			continue
+		}
+
+		row.UserId = userId
+		valid = append(valid, row)
+	}
+
+	report.Valid = len(valid)
+	report.Invalid = report.Total - report.Valid
+
+	sort.SliceStable(report.Errors, func(i, j int) bool {
+		if report.Errors[i].Row != report.Errors[j].Row {
+			return report.Errors[i].Row < report.Errors[j].Row
+		}
+		return report.Errors[i].Field < report.Errors[j].Field
+	})
+
+	if dryRun {
+		return report, nil
+	}
+
+	for start := 0; start < len(valid); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+
+		imported, err := s.repository.ImportTasks(ctx, valid[start:end])
+		if err != nil {
+			report.ResumeFromRow = valid[start].Row
+			s.imported(report)
+
+			return report, ErrImportInterrupted.Withf("error to import rows %d to %d, the rows before were imported", valid[start].Row, valid[end-1].Row).Wrap(err)
+		}
+
+		report.Imported += int(imported)
+	}
+
+	s.imported(report)
+
+	return report, nil
+}
+
+// imported tells the listeners and the metrics about the tasks imported, also
+// when the import stopped after some batches.
+func (s service) imported(report entity.TaskImportReport) {
+	if report.Imported == 0 {
+		return
+	}
+
+	s.changed()
+	// only managers import tasks
+	s.recorder.TasksCreated(entity.ManagerRole, report.Imported)
+}
+
+// parseImportRow validates a row with the same rules used to create tasks.
+func parseImportRow(line int, record []string, columns map[string]int) (entity.TaskImport, []entity.TaskImportError) {
+	value := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rowErrors []entity.TaskImportError
+
+	row := entity.TaskImport{
+		Row:         line,
+		Title:       value("title"),
+		Description: value("description"),
+		Username:    value("username"),
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	err := entity.TaskRequest{Title: row.Title, Description: row.Description}.Validate()
+	if err != nil {
+		var fieldErrors validation.Errors
+		if errors.As(err, &fieldErrors) {
+			for field, fieldErr := range fieldErrors {
+				rowErrors = append(rowErrors, entity.TaskImportError{Row: line, Field: field, Message: fieldErr.Error()})
+			}
+		} else {
+			rowErrors = append(rowErrors, entity.TaskImportError{Row: line, Message: err.Error()})
+		}
+	}
+
+	if row.Username == "" {
+		rowErrors = append(rowErrors, entity.TaskImportError{Row: line, Field: "username", Message: "cannot be blank"})
+	}
+
+	if v := value("created_at"); v != "" {
+		createdAt, err := parseImportTime(v)
+		if err != nil {
+			rowErrors = append(rowErrors, entity.TaskImportError{Row: line, Field: "created_at", Message: err.Error()})
+		} else {
+			row.CreatedAt = createdAt
+		}
+	}
+
+	if v := value("finished_at"); v != "" {
+		finishedAt, err := parseImportTime(v)
+		if err != nil {
+			rowErrors = append(rowErrors, entity.TaskImportError{Row: line, Field: "finished_at", Message: err.Error()})
+		} else if finishedAt.Before(row.CreatedAt) {
+			rowErrors = append(rowErrors, entity.TaskImportError{Row: line, Field: "finished_at", Message: "must be after created_at"})
+		} else {
+			row.FinishedAt = &finishedAt
+		}
+	}
+
+	return row, rowErrors
+}
+
+func parseImportTime(value string) (time.Time, error) {
+	for _, layout := range importTimeLayouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
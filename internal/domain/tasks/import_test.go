@@ -0,0 +1,56 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/logging"
+	"github.com/stretchr/testify/require"
+)
+
+// importRepository imports the first fail batches and fails the next ones.
+type importRepository struct {
+	fakeRepository
+	batches *int
+	fail    int
+}
+
+func (importRepository) GetUserIdsByUsernames(context.Context, int, []string) (map[string]int, error) {
+	return map[string]int{"lsimao": 1}, nil
+}
+
+func (r importRepository) ImportTasks(_ context.Context, rows []entity.TaskImport) (int64, error) {
+	*r.batches++
+	if *r.batches > r.fail {
+		return 0, errors.New("connection reset")
+	}
+	return int64(len(rows)), nil
+}
+
+type countedListener struct{ invalidated *int }
+
+func (l countedListener) Invalidate() { *l.invalidated++ }
+
+func TestImportTasksInterrupted(t *testing.T) {
+	defer func(size int) { importBatchSize = size }(importBatchSize)
+	importBatchSize = 2
+
+	var batches, invalidated int
+	repo := importRepository{batches: &batches, fail: 1}
+	s := New(repo, nil, fakeRecorder{}, logging.Discard(), countedListener{invalidated: &invalidated})
+
+	file := "title,description,username\n" + strings.Repeat("pump,replace the seal,lsimao\n", 5)
+
+	report, err := s.ImportTasks(context.Background(), 2, strings.NewReader(file), false)
+	require.ErrorIs(t, err, ErrImportInterrupted)
+
+	// the first batch, rows 2 and 3, was imported
+	require.Equal(t, 5, report.Valid)
+	require.Equal(t, 2, report.Imported)
+	require.Equal(t, 4, report.ResumeFromRow)
+	require.Equal(t, 2, batches)
+	require.Equal(t, 1, invalidated)
+}
@@ -2,15 +2,26 @@ package tasks
 
 import (
 	"context"
+	"io"
 
 	"github.com/lucas-simao/api-tasks/internal/entity"
 )
 
 type Service interface {
 	CreateTask(context.Context, entity.TaskRequest) (int64, error)
-	GetTasks(context.Context, int, int) ([]entity.TaskResponse, error)
-	GetTaskById(context.Context, int, int, int) (entity.TaskResponse, error)
-	DeleteTaskById(context.Context, int, int) error
+	GetTasks(context.Context, int, entity.Scope) ([]entity.TaskResponse, error)
+	GetTaskById(context.Context, int, int, entity.Scope) (entity.TaskResponse, error)
+	DeleteTaskById(context.Context, int, int, entity.Scope) error
 	UpdateTaskById(context.Context, entity.TaskUpdateRequest) (entity.TaskResponse, error)
-	FinishTaskById(context.Context, int, int) (entity.TaskResponse, error)
+	FinishTaskById(context.Context, int, int, int) (entity.TaskResponse, error)
+	StartTimer(context.Context, int, int) (entity.WorkLogResponse, error)
+	StopTimer(context.Context, entity.TimerStopRequest) (entity.WorkLogResponse, error)
+	CreateWorkLog(context.Context, entity.WorkLogRequest) (entity.WorkLogResponse, error)
+	GetWorkLogs(context.Context, int, int, entity.Scope) ([]entity.WorkLogResponse, error)
+	ExportTasks(context.Context, int, entity.Scope, func(entity.TaskExport) error) error
+	ImportTasks(context.Context, int, io.Reader, bool) (entity.TaskImportReport, error)
+	BulkTasks(context.Context, entity.BulkTaskRequest) (entity.BulkTaskResponse, error)
+	// Drain waits for the work still running in background, such as
+	// notifications, it is called on shutdown after the last request.
+	Drain(context.Context) error
 }
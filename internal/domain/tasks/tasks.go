@@ -2,52 +2,189 @@ package tasks
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
 
 	"github.com/lucas-simao/api-tasks/internal/entity"
 	"github.com/lucas-simao/api-tasks/internal/gateway/notifications"
 	"github.com/lucas-simao/api-tasks/internal/repository"
+	"github.com/lucas-simao/api-tasks/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ChangeListener is notified whenever a task is created, updated, finished or
+// deleted, so derived data such as cached statistics can be dropped.
+type ChangeListener interface {
+	Invalidate()
+}
+
+// Recorder counts the tasks created and finished by the role of the user.
+type Recorder interface {
+	TasksCreated(roleCode, count int)
+	TasksFinished(roleCode, count int)
+}
+
 type service struct {
 	repository    repository.Repository
 	notifications notifications.Notifications
+	recorder      Recorder
+	logger        *slog.Logger
+	listeners     []ChangeListener
+	background    *sync.WaitGroup
 }
 
-func New(r repository.Repository, n notifications.Notifications) Service {
-	return service{
-		repository:    r,
-		notifications: n,
+func New(r repository.Repository, n notifications.Notifications, m Recorder, logger *slog.Logger, listeners ...ChangeListener) Service {
+	return traced{
+		next: service{
+			repository:    r,
+			notifications: n,
+			recorder:      m,
+			logger:        logger,
+			listeners:     listeners,
+			background:    &sync.WaitGroup{},
+		},
 	}
 }
 
 func (s service) CreateTask(ctx context.Context, t entity.TaskRequest) (int64, error) {
-	return s.repository.CreateTask(ctx, t)
+	id, err := s.repository.CreateTask(ctx, t)
+	if err == nil {
+		s.changed()
+		s.recorder.TasksCreated(t.CodeRole, 1)
+	}
+
+	return id, err
 }
 
-func (s service) GetTasks(ctx context.Context, userId, roleCode int) ([]entity.TaskResponse, error) {
-	return s.repository.GetTasks(ctx, userId, roleCode)
+func (s service) GetTasks(ctx context.Context, userId int, scope entity.Scope) ([]entity.TaskResponse, error) {
+	return s.repository.GetTasks(ctx, userId, scope)
 }
 
-func (s service) GetTaskById(ctx context.Context, taskId, userId, roleCode int) (entity.TaskResponse, error) {
-	return s.repository.GetTaskById(ctx, taskId, userId, roleCode)
+func (s service) GetTaskById(ctx context.Context, taskId, userId int, scope entity.Scope) (entity.TaskResponse, error) {
+	return s.repository.GetTaskById(ctx, taskId, userId, scope)
 }
 
-func (s service) DeleteTaskById(ctx context.Context, taskId, userId int) error {
-	return s.repository.DeleteTaskById(ctx, taskId, userId)
+func (s service) DeleteTaskById(ctx context.Context, taskId, userId int, scope entity.Scope) error {
+	err := s.repository.DeleteTaskById(ctx, taskId, userId, scope)
+	if err == nil {
+		s.changed()
+	}
+
+	return err
 }
 
 func (s service) UpdateTaskById(ctx context.Context, task entity.TaskUpdateRequest) (entity.TaskResponse, error) {
-	return s.repository.UpdateTaskById(ctx, task)
+	taskUpdated, err := s.repository.UpdateTaskById(ctx, task)
+	if err == nil {
+		s.changed()
+	}
+
+	return taskUpdated, err
 }
 
-func (s service) FinishTaskById(ctx context.Context, taskId, userId int) (entity.TaskResponse, error) {
+func (s service) FinishTaskById(ctx context.Context, taskId, userId, roleCode int) (entity.TaskResponse, error) {
 	task, err := s.repository.FinishTaskById(ctx, taskId, userId)
+	if err == nil {
+		s.changed()
+		s.recorder.TasksFinished(roleCode, 1)
+	}
+
+	if err == nil {
+		s.notify(ctx, task)
+	}
+
+	return task, err
+}
+
+func (s service) StartTimer(ctx context.Context, taskId, userId int) (entity.WorkLogResponse, error) {
+	return s.repository.StartTimer(ctx, taskId, userId)
+}
+
+func (s service) StopTimer(ctx context.Context, t entity.TimerStopRequest) (entity.WorkLogResponse, error) {
+	return s.repository.StopTimer(ctx, t)
+}
+
+func (s service) CreateWorkLog(ctx context.Context, w entity.WorkLogRequest) (entity.WorkLogResponse, error) {
+	return s.repository.CreateWorkLog(ctx, w)
+}
 
+func (s service) GetWorkLogs(ctx context.Context, taskId, userId int, scope entity.Scope) ([]entity.WorkLogResponse, error) {
+	return s.repository.GetWorkLogs(ctx, taskId, userId, scope)
+}
+
+func (s service) ExportTasks(ctx context.Context, userId int, scope entity.Scope, fn func(entity.TaskExport) error) error {
+	return s.repository.ExportTasks(ctx, userId, scope, fn)
+}
+
+// notify sends the notification to the managers of the teams of the technician
+// in the background. It gets its own trace, linked to the request, because it
+// usually ends after the response is sent. The request context is kept without
+// its cancellation so the log fields of the request are still there.
+func (s service) notify(ctx context.Context, task entity.TaskResponse) {
+	link := trace.LinkFromContext(ctx)
+	ctx = context.WithoutCancel(ctx)
+
+	s.background.Add(1)
 	go func() {
-		if err == nil {
-			s.notifications.NotifyManager(task)
+		defer s.background.Done()
+
+		ctx, span := tracing.Tracer().Start(ctx, "tasks.notify",
+			trace.WithNewRoot(),
+			trace.WithLinks(link),
+			trace.WithAttributes(attribute.Int("task.id", task.Id)),
+		)
+
+		managers, err := s.repository.GetTeamManagersByUser(ctx, task.CreatedBy.Id)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "error to get team managers", slog.Int("task_id", task.Id), slog.Any("error", err))
+			tracing.End(span, err)
+			return
+		}
+
+		if len(managers) == 0 {
+			s.logger.WarnContext(ctx, "technician has no team manager to notify",
+				slog.Int("task_id", task.Id),
+				slog.Int("technician_id", task.CreatedBy.Id),
+			)
+		}
+
+		var errs []error
+		for _, manager := range managers {
+			err := s.notifications.NotifyManager(ctx, manager, task)
+			if err != nil {
+				s.logger.ErrorContext(ctx, "error to notify manager",
+					slog.Int("task_id", task.Id),
+					slog.Int("manager_id", manager.Id),
+					slog.Any("error", err),
+				)
+				errs = append(errs, err)
+			}
 		}
+
+		tracing.End(span, errors.Join(errs...))
 	}()
+}
 
-	return task, err
+func (s service) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("error to drain background work: %w", ctx.Err())
+	}
+}
+
+func (s service) changed() {
+	for _, l := range s.listeners {
+		l.Invalidate()
+	}
 }
@@ -0,0 +1,63 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/logging"
+	"github.com/stretchr/testify/require"
+)
+
+type blockedNotifications struct{ release chan struct{} }
+
+func (n blockedNotifications) NotifyManager(context.Context, entity.User, entity.TaskResponse) error {
+	<-n.release
+	return nil
+}
+
+func TestDrain(t *testing.T) {
+	notifications := blockedNotifications{release: make(chan struct{})}
+	s := New(fakeRepository{}, notifications, fakeRecorder{}, logging.Discard())
+
+	_, err := s.FinishTaskById(context.Background(), 1, 2, entity.TechnicianRole)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, s.Drain(ctx), context.DeadlineExceeded)
+
+	close(notifications.release)
+	require.NoError(t, s.Drain(context.Background()))
+}
+
+type teamRepository struct{ fakeRepository }
+
+func (teamRepository) GetTeamManagersByUser(context.Context, int) ([]entity.User, error) {
+	return []entity.User{{Id: 3, CodeRole: entity.ManagerRole}, {Id: 4, CodeRole: entity.ManagerRole}}, nil
+}
+
+type recordedNotifications struct{ managers chan int }
+
+func (n recordedNotifications) NotifyManager(_ context.Context, manager entity.User, _ entity.TaskResponse) error {
+	n.managers <- manager.Id
+	return nil
+}
+
+func TestFinishTaskByIdNotifiesTeamManagers(t *testing.T) {
+	notifications := recordedNotifications{managers: make(chan int, 2)}
+	s := New(teamRepository{}, notifications, fakeRecorder{}, logging.Discard())
+
+	_, err := s.FinishTaskById(context.Background(), 1, 2, entity.TechnicianRole)
+	require.NoError(t, err)
+	require.NoError(t, s.Drain(context.Background()))
+
+	close(notifications.managers)
+
+	var managers []int
+	for id := range notifications.managers {
+		managers = append(managers, id)
+	}
+	require.Equal(t, []int{3, 4}, managers)
+}
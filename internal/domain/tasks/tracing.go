@@ -0,0 +1,134 @@
+package tasks
+
+import (
+	"context"
+	"io"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traced starts a span for every method of the service, the queries made by
+// the repository are its children.
+type traced struct {
+	next Service
+}
+
+func start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "tasks."+name, trace.WithAttributes(attrs...))
+}
+
+func taskAttrs(taskId, userId int) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.Int("task.id", taskId), attribute.Int("user.id", userId)}
+}
+
+func (t traced) CreateTask(ctx context.Context, task entity.TaskRequest) (int64, error) {
+	ctx, span := start(ctx, "CreateTask", attribute.Int("user.id", task.UserId))
+	id, err := t.next.CreateTask(ctx, task)
+	span.SetAttributes(attribute.Int64("task.id", id))
+	tracing.End(span, err)
+
+	return id, err
+}
+
+func (t traced) GetTasks(ctx context.Context, userId int, scope entity.Scope) ([]entity.TaskResponse, error) {
+	ctx, span := start(ctx, "GetTasks", attribute.Int("user.id", userId))
+	tasks, err := t.next.GetTasks(ctx, userId, scope)
+	tracing.End(span, err)
+
+	return tasks, err
+}
+
+func (t traced) GetTaskById(ctx context.Context, taskId, userId int, scope entity.Scope) (entity.TaskResponse, error) {
+	ctx, span := start(ctx, "GetTaskById", taskAttrs(taskId, userId)...)
+	task, err := t.next.GetTaskById(ctx, taskId, userId, scope)
+	tracing.End(span, err)
+
+	return task, err
+}
+
+func (t traced) DeleteTaskById(ctx context.Context, taskId, userId int, scope entity.Scope) error {
+	ctx, span := start(ctx, "DeleteTaskById", taskAttrs(taskId, userId)...)
+	err := t.next.DeleteTaskById(ctx, taskId, userId, scope)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (t traced) UpdateTaskById(ctx context.Context, task entity.TaskUpdateRequest) (entity.TaskResponse, error) {
+	ctx, span := start(ctx, "UpdateTaskById", taskAttrs(task.Id, task.UserId)...)
+	taskUpdated, err := t.next.UpdateTaskById(ctx, task)
+	tracing.End(span, err)
+
+	return taskUpdated, err
+}
+
+func (t traced) FinishTaskById(ctx context.Context, taskId, userId, roleCode int) (entity.TaskResponse, error) {
+	ctx, span := start(ctx, "FinishTaskById", taskAttrs(taskId, userId)...)
+	task, err := t.next.FinishTaskById(ctx, taskId, userId, roleCode)
+	tracing.End(span, err)
+
+	return task, err
+}
+
+func (t traced) StartTimer(ctx context.Context, taskId, userId int) (entity.WorkLogResponse, error) {
+	ctx, span := start(ctx, "StartTimer", taskAttrs(taskId, userId)...)
+	workLog, err := t.next.StartTimer(ctx, taskId, userId)
+	tracing.End(span, err)
+
+	return workLog, err
+}
+
+func (t traced) StopTimer(ctx context.Context, r entity.TimerStopRequest) (entity.WorkLogResponse, error) {
+	ctx, span := start(ctx, "StopTimer", taskAttrs(r.TaskId, r.UserId)...)
+	workLog, err := t.next.StopTimer(ctx, r)
+	tracing.End(span, err)
+
+	return workLog, err
+}
+
+func (t traced) CreateWorkLog(ctx context.Context, w entity.WorkLogRequest) (entity.WorkLogResponse, error) {
+	ctx, span := start(ctx, "CreateWorkLog", taskAttrs(w.TaskId, w.UserId)...)
+	workLog, err := t.next.CreateWorkLog(ctx, w)
+	tracing.End(span, err)
+
+	return workLog, err
+}
+
+func (t traced) GetWorkLogs(ctx context.Context, taskId, userId int, scope entity.Scope) ([]entity.WorkLogResponse, error) {
+	ctx, span := start(ctx, "GetWorkLogs", taskAttrs(taskId, userId)...)
+	workLogs, err := t.next.GetWorkLogs(ctx, taskId, userId, scope)
+	tracing.End(span, err)
+
+	return workLogs, err
+}
+
+func (t traced) ExportTasks(ctx context.Context, userId int, scope entity.Scope, fn func(entity.TaskExport) error) error {
+	ctx, span := start(ctx, "ExportTasks", attribute.Int("user.id", userId))
+	err := t.next.ExportTasks(ctx, userId, scope, fn)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (t traced) ImportTasks(ctx context.Context, userId int, r io.Reader, dryRun bool) (entity.TaskImportReport, error) {
+	ctx, span := start(ctx, "ImportTasks", attribute.Bool("import.dry_run", dryRun))
+	report, err := t.next.ImportTasks(ctx, userId, r, dryRun)
+	tracing.End(span, err)
+
+	return report, err
+}
+
+func (t traced) BulkTasks(ctx context.Context, b entity.BulkTaskRequest) (entity.BulkTaskResponse, error) {
+	ctx, span := start(ctx, "BulkTasks", attribute.String("bulk.action", b.Action), attribute.Int("user.id", b.UserId))
+	response, err := t.next.BulkTasks(ctx, b)
+	tracing.End(span, err)
+
+	return response, err
+}
+
+func (t traced) Drain(ctx context.Context) error {
+	return t.next.Drain(ctx)
+}
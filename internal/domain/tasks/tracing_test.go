@@ -0,0 +1,74 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/logging"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+	"github.com/lucas-simao/api-tasks/internal/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type fakeRepository struct{ repository.Repository }
+
+func (fakeRepository) FinishTaskById(ctx context.Context, taskId, userId int) (entity.TaskResponse, error) {
+	_, span := tracing.Tracer().Start(ctx, "UPDATE")
+	span.End()
+
+	return entity.TaskResponse{Id: taskId}, nil
+}
+
+func (fakeRepository) GetTeamManagersByUser(context.Context, int) ([]entity.User, error) {
+	return []entity.User{{Id: 3, CodeRole: entity.ManagerRole}}, nil
+}
+
+type fakeNotifications struct{ sent chan trace.SpanContext }
+
+func (n fakeNotifications) NotifyManager(ctx context.Context, manager entity.User, t entity.TaskResponse) error {
+	n.sent <- trace.SpanContextFromContext(ctx)
+	return nil
+}
+
+type fakeRecorder struct{}
+
+func (fakeRecorder) TasksCreated(roleCode, count int)  {}
+func (fakeRecorder) TasksFinished(roleCode, count int) {}
+
+func TestFinishTaskByIdTracing(t *testing.T) {
+	exporter := tracing.SetupInMemory()
+	notifications := fakeNotifications{sent: make(chan trace.SpanContext, 1)}
+	s := New(fakeRepository{}, notifications, fakeRecorder{}, logging.Discard())
+
+	ctx, request := tracing.Tracer().Start(context.Background(), "PATCH /tasks/:id")
+	_, err := s.FinishTaskById(ctx, 1, 2, entity.TechnicianRole)
+	request.End()
+	require.NoError(t, err)
+
+	notified := <-notifications.sent
+
+	spans := exporter.GetSpans()
+	names := map[string]int{}
+	for i, span := range spans {
+		names[span.Name] = i
+	}
+
+	query := spans[names["UPDATE"]]
+	service := spans[names["tasks.FinishTaskById"]]
+	require.Equal(t, service.SpanContext.SpanID(), query.Parent.SpanID())
+	require.Equal(t, request.SpanContext().SpanID(), service.Parent.SpanID())
+
+	// the notification starts a new trace linked to the request
+	require.NotEqual(t, request.SpanContext().TraceID(), notified.TraceID())
+	require.Eventually(t, func() bool {
+		for _, span := range exporter.GetSpans() {
+			if span.Name == "tasks.notify" {
+				return len(span.Links) == 1 && span.Links[0].SpanContext.SpanID() == service.SpanContext.SpanID()
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,14 @@
+package teams
+
+import (
+	"context"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+type Service interface {
+	CreateTeam(context.Context, entity.TeamRequest) (int64, error)
+	GetTeams(context.Context, int) ([]entity.TeamResponse, error)
+	AddTeamMember(context.Context, entity.TeamMemberRequest) error
+	RemoveTeamMember(context.Context, entity.TeamMemberRequest) error
+}
@@ -0,0 +1,38 @@
+package teams
+
+import (
+	"context"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/repository"
+)
+
+type service struct {
+	repository repository.Repository
+}
+
+func New(r repository.Repository) Service {
+	return service{
+		repository: r,
+	}
+}
+
+// CreateTeam creates a team managed by the user creating it.
+func (s service) CreateTeam(ctx context.Context, t entity.TeamRequest) (int64, error) {
+	return s.repository.CreateTeam(ctx, t)
+}
+
+// GetTeams returns the teams the user is a member of.
+func (s service) GetTeams(ctx context.Context, userId int) ([]entity.TeamResponse, error) {
+	return s.repository.GetTeamsByUser(ctx, userId)
+}
+
+// AddTeamMember adds a user to a team of the manager, the manager sees the
+// tasks of the user from then on.
+func (s service) AddTeamMember(ctx context.Context, m entity.TeamMemberRequest) error {
+	return s.repository.AddTeamMember(ctx, m)
+}
+
+func (s service) RemoveTeamMember(ctx context.Context, m entity.TeamMemberRequest) error {
+	return s.repository.RemoveTeamMember(ctx, m)
+}
@@ -8,5 +8,6 @@ import (
 
 type Service interface {
 	SignUp(context.Context, entity.SignUpRequest) error
-	SignIn(context.Context, entity.SignInRequest) (string, error)
+	SignIn(context.Context, entity.SignInRequest) (entity.SignInResponse, error)
+	SignInMFA(context.Context, entity.MFASignInRequest) (entity.SignInResponse, error)
 }
@@ -0,0 +1,38 @@
+package users
+
+import (
+	"context"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/tracing"
+)
+
+// traced starts a span for every method of the service, the queries made by
+// the repository are its children.
+type traced struct {
+	next Service
+}
+
+func (t traced) SignUp(ctx context.Context, u entity.SignUpRequest) error {
+	ctx, span := tracing.Tracer().Start(ctx, "users.SignUp")
+	err := t.next.SignUp(ctx, u)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (t traced) SignIn(ctx context.Context, u entity.SignInRequest) (entity.SignInResponse, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "users.SignIn")
+	response, err := t.next.SignIn(ctx, u)
+	tracing.End(span, err)
+
+	return response, err
+}
+
+func (t traced) SignInMFA(ctx context.Context, r entity.MFASignInRequest) (entity.SignInResponse, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "users.SignInMFA")
+	response, err := t.next.SignInMFA(ctx, r)
+	tracing.End(span, err)
+
+	return response, err
+}
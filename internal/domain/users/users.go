@@ -3,8 +3,10 @@ package users
 import (
 	"context"
 	"errors"
-	"os"
 
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/domain/mfa"
+	"github.com/lucas-simao/api-tasks/internal/domain/organizations"
 	"github.com/lucas-simao/api-tasks/internal/entity"
 	"github.com/lucas-simao/api-tasks/internal/repository"
 	"github.com/lucas-simao/api-tasks/internal/utils"
@@ -13,19 +15,28 @@ import (
 
 type service struct {
 	repository repository.Repository
+	mfa        mfa.Service
+	jwtSecret  string
 }
 
-func New(r repository.Repository) Service {
-	return service{
-		repository: r,
+func New(r repository.Repository, m mfa.Service, jwtSecret string) Service {
+	return traced{
+		next: service{
+			repository: r,
+			mfa:        m,
+			jwtSecret:  jwtSecret,
+		},
 	}
 }
 
 var (
-	ErrWrongPassword        = errors.New("wrong password")
-	ErrUserWithoutValidRole = errors.New("user don't have valid role")
+	ErrWrongPassword        = apperror.Unauthorized("invalid_credentials", "wrong username or password")
+	ErrUserWithoutValidRole = apperror.Forbidden("user_without_role", "user don't have valid role")
+	ErrInvalidInviteCode    = apperror.Invalid("invalid_invite_code", "invite code is invalid")
 )
 
+// SignUp creates the organization of the request with the user as its manager,
+// or adds the user to the organization of the invite code as a technician.
 func (s service) SignUp(ctx context.Context, u entity.SignUpRequest) error {
 	password, err := s.EncryptPassword(u.Password)
 	if err != nil {
@@ -33,31 +44,95 @@ func (s service) SignUp(ctx context.Context, u entity.SignUpRequest) error {
 	}
 	u.Password = password
 
-	return s.repository.SignUp(ctx, u)
+	return s.repository.WithTx(ctx, func(r repository.Repository) error {
+		if u.InviteCode != "" {
+			o, err := r.GetOrganizationByInviteCode(ctx, u.InviteCode)
+			if err != nil {
+				if errors.Is(err, repository.ErrOrganizationNotFound) {
+					return ErrInvalidInviteCode
+				}
+				return err
+			}
+
+			u.OrganizationId, u.CodeRole = o.Id, entity.TechnicianRole
+
+			return r.SignUp(ctx, u)
+		}
+
+		inviteCode, err := organizations.NewInviteCode()
+		if err != nil {
+			return err
+		}
+
+		id, err := r.CreateOrganization(ctx, entity.Organization{Name: u.Organization, InviteCode: inviteCode})
+		if err != nil {
+			return err
+		}
+
+		u.OrganizationId, u.CodeRole = int(id), entity.ManagerRole
+
+		return r.SignUp(ctx, u)
+	})
 }
 
-func (s service) SignIn(ctx context.Context, u entity.SignInRequest) (string, error) {
+// SignIn returns the token of the user, or the MFA token to send a code with
+// when the user has two-factor authentication, see SignInMFA.
+func (s service) SignIn(ctx context.Context, u entity.SignInRequest) (entity.SignInResponse, error) {
 	userDB, err := s.repository.SignIn(ctx, u.Username)
 	if err != nil {
-		return "", err
+		if errors.Is(err, repository.ErrUserNotExist) {
+			return entity.SignInResponse{}, ErrWrongPassword
+		}
+		return entity.SignInResponse{}, err
 	}
 
 	if err := s.ComparePassword(userDB.Password, u.Password); err != nil {
-		return "", ErrWrongPassword
+		return entity.SignInResponse{}, ErrWrongPassword
 	}
 
 	if userDB.CodeRole == entity.VisitorRole {
-		return "", ErrUserWithoutValidRole
+		return entity.SignInResponse{}, ErrUserWithoutValidRole
+	}
+
+	challenge, err := s.mfa.Challenge(ctx, userDB)
+	if err != nil {
+		return entity.SignInResponse{}, err
 	}
 
-	secret := os.Getenv("JWT_SECRET")
+	if challenge.Required {
+		return entity.SignInResponse{
+			MFAToken:              challenge.Token,
+			MFAEnrollmentRequired: challenge.EnrollmentRequired,
+		}, nil
+	}
 
-	token, err := utils.GenerateToken(secret, userDB)
+	token, err := utils.GenerateToken(s.jwtSecret, userDB)
 	if err != nil {
-		return "", err
+		return entity.SignInResponse{}, err
+	}
+
+	return entity.SignInResponse{Token: token}, nil
+}
+
+// SignInMFA finishes the sign in of SignIn with a code, users enabling two-factor
+// authentication get their recovery codes with the token.
+func (s service) SignInMFA(ctx context.Context, r entity.MFASignInRequest) (entity.SignInResponse, error) {
+	userDB, recoveryCodes, err := s.mfa.VerifyChallenge(ctx, r)
+	if err != nil {
+		return entity.SignInResponse{}, err
+	}
+
+	// the role may have changed since the password
+	if userDB.CodeRole == entity.VisitorRole {
+		return entity.SignInResponse{}, ErrUserWithoutValidRole
+	}
+
+	token, err := utils.GenerateToken(s.jwtSecret, userDB)
+	if err != nil {
+		return entity.SignInResponse{}, err
 	}
 
-	return token, nil
+	return entity.SignInResponse{Token: token, RecoveryCodes: recoveryCodes}, nil
 }
 
 func (s service) EncryptPassword(password string) (string, error) {
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// ApiKeyRequest creates a key for a machine client. The key acts as a service
+// user of the organization named after it, with the permissions of the key.
+type ApiKeyRequest struct {
+	Name        string    `json:"name"`
+	Permissions []string  `json:"permissions"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	// UserId is the manager creating the key, Username the one of the service
+	// user and KeyHash the hash of the key, the key itself isn't stored
+	UserId   int    `json:"-"`
+	Username string `json:"-"`
+	KeyHash  string `json:"-"`
+}
+
+func (c ApiKeyRequest) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required, validation.Length(1, 50)),
+		validation.Field(&c.Permissions, validation.Required, validation.Length(1, 20),
+			validation.Each(validation.Required, validation.Length(1, 50))),
+		validation.Field(&c.ExpiresAt, validation.Required, validation.Min(time.Now()).Exclusive()))
+}
+
+type ApiKeyResponse struct {
+	Id          int      `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	// UserId is the service user the key acts as, the author of what the key
+	// creates
+	UserId     int                       `json:"userId"`
+	ExpiresAt  string                    `json:"expiresAt"`
+	LastUsedAt string                    `json:"lastUsedAt"`
+	RevokedAt  string                    `json:"revokedAt"`
+	CreatedBy  TaskUserOperationResponse `json:"createdBy"`
+}
+
+// ApiKeyCreated is the only response with the key, it can't be read again.
+type ApiKeyCreated struct {
+	ApiKeyResponse
+	Key string `json:"key"`
+}
+
+// ApiKeyPrincipal is the service user of a valid key and the permissions of
+// the key.
+type ApiKeyPrincipal struct {
+	ApiKeyId    int
+	User        User
+	Permissions []string
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var (
+	BulkActionDelete   = "delete"
+	BulkActionRestore  = "restore"
+	BulkActionReassign = "reassign"
+	BulkActionFinish   = "finish"
+
+	BulkModeTransaction = "transaction"
+	BulkModePerItem     = "per-item"
+
+	BulkStatusOk         = "ok"
+	BulkStatusFailed     = "failed"
+	BulkStatusRolledBack = "rolled_back"
+	BulkStatusSkipped    = "skipped"
+
+	BulkMaxIds = 500
+)
+
+type BulkTaskRequest struct {
+	Action     string `json:"action"`
+	Mode       string `json:"mode"`
+	Ids        []int  `json:"ids"`
+	AssigneeId int    `json:"assigneeId"`
+	UserId     int    `json:"-"`
+	CodeRole   int    `json:"-"`
+	// Scope of the tasks deleted and restored
+	Scope Scope `json:"-"`
+}
+
+func (c BulkTaskRequest) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Action, validation.Required, validation.In(BulkActionDelete, BulkActionRestore, BulkActionReassign, BulkActionFinish)),
+		validation.Field(&c.Mode, validation.In(BulkModeTransaction, BulkModePerItem)),
+		validation.Field(&c.Ids, validation.Required, validation.Length(1, BulkMaxIds), validation.Each(validation.Min(1))),
+		validation.Field(&c.AssigneeId, validation.When(c.Action == BulkActionReassign, validation.Required)))
+}
+
+type BulkTaskResult struct {
+	Id      int    `json:"id"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	// Err is the cause of a failure not safe to show to clients
+	Err error `json:"-"`
+}
+
+type BulkTaskResponse struct {
+	Action    string           `json:"action"`
+	Mode      string           `json:"mode"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkTaskResult `json:"results"`
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"strconv"
+)
+
+// TaskExportColumns is the column order of task exports, new columns should
+// only be appended so spreadsheets built on top of it keep working.
+var TaskExportColumns = []string{
+	"id",
+	"title",
+	"description",
+	"created_by_id",
+	"created_by_name",
+	"created_at",
+	"updated_at",
+	"finished_by_id",
+	"finished_by_name",
+	"finished_at",
+	"deleted_by_id",
+	"deleted_by_name",
+	"deleted_at",
+	"time_spent_seconds",
+}
+
+type TaskExport struct {
+	Id               int    `json:"id"`
+	Title            string `json:"title"`
+	Description      string `json:"description"`
+	CreatedById      int    `json:"createdById"`
+	CreatedByName    string `json:"createdByName"`
+	CreatedAt        string `json:"createdAt"`
+	UpdatedAt        string `json:"updatedAt"`
+	FinishedById     int    `json:"finishedById"`
+	FinishedByName   string `json:"finishedByName"`
+	FinishedAt       string `json:"finishedAt"`
+	DeletedById      int    `json:"deletedById"`
+	DeletedByName    string `json:"deletedByName"`
+	DeletedAt        string `json:"deletedAt"`
+	TimeSpentSeconds int64  `json:"timeSpentSeconds"`
+}
+
+// Record returns the task as a CSV record following TaskExportColumns.
+func (t TaskExport) Record() []string {
+	return []string{
+		strconv.Itoa(t.Id),
+		t.Title,
+		t.Description,
+		strconv.Itoa(t.CreatedById),
+		t.CreatedByName,
+		t.CreatedAt,
+		t.UpdatedAt,
+		strconv.Itoa(t.FinishedById),
+		t.FinishedByName,
+		t.FinishedAt,
+		strconv.Itoa(t.DeletedById),
+		t.DeletedByName,
+		t.DeletedAt,
+		strconv.FormatInt(t.TimeSpentSeconds, 10),
+	}
+}
@@ -0,0 +1,10 @@
+package entity
+
+const (
+	HealthStatusOk    = "ok"
+	HealthStatusReady = "ready"
+)
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
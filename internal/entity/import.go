@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+)
+
+// TaskImport is a validated row of a task import file.
+type TaskImport struct {
+	Row         int
+	Title       string
+	Description string
+	Username    string
+	UserId      int
+	CreatedAt   time.Time
+	FinishedAt  *time.Time
+}
+
+type TaskImportError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// TaskImportReport tells the rows imported. ResumeFromRow is the first row of
+// the file not imported when the import stopped, the rows before it were.
+type TaskImportReport struct {
+	DryRun        bool              `json:"dryRun"`
+	Total         int               `json:"total"`
+	Valid         int               `json:"valid"`
+	Invalid       int               `json:"invalid"`
+	Imported      int               `json:"imported"`
+	ResumeFromRow int               `json:"resumeFromRow,omitempty"`
+	Errors        []TaskImportError `json:"errors"`
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// UserMFA is the two-factor authentication of a user, enabled once a code of
+// the secret was verified. FailedAttempts are the recent wrong codes.
+type UserMFA struct {
+	UserId         int    `db:"user_id"`
+	Secret         string `db:"secret"`
+	Enabled        bool   `db:"enabled"`
+	LastStep       int64  `db:"last_step"`
+	FailedAttempts int    `db:"failed_attempts"`
+}
+
+// SignInResponse has the token of the user, or the MFA token to finish the sign
+// in with a code when two-factor authentication is enabled or required.
+type SignInResponse struct {
+	Token                 string   `json:"token,omitempty"`
+	MFAToken              string   `json:"mfaToken,omitempty"`
+	MFAEnrollmentRequired bool     `json:"mfaEnrollmentRequired,omitempty"`
+	RecoveryCodes         []string `json:"recoveryCodes,omitempty"`
+}
+
+// MFAChallenge tells whether the user needs a code to sign in, and the token
+// the code is sent with.
+type MFAChallenge struct {
+	Required           bool
+	Token              string
+	EnrollmentRequired bool
+}
+
+// MFASignInRequest finishes a sign in with a code of the authenticator or a
+// recovery code.
+type MFASignInRequest struct {
+	MFAToken string `json:"mfaToken"`
+	Code     string `json:"code"`
+}
+
+func (c MFASignInRequest) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MFAToken, validation.Required),
+		validation.Field(&c.Code, validation.Required, validation.Length(6, 20)))
+}
+
+// MFAEnrollRequest starts the enrolment of users who must enable two-factor
+// authentication to sign in.
+type MFAEnrollRequest struct {
+	MFAToken string `json:"mfaToken"`
+}
+
+func (c MFAEnrollRequest) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MFAToken, validation.Required))
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code"`
+}
+
+func (c MFACodeRequest) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Code, validation.Required, validation.Length(6, 20)))
+}
+
+// TOTPEnrollment is the secret of an authenticator app, URI is the otpauth uri
+// shown as a QR code.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// RecoveryCodes are only returned when two-factor authentication is enabled,
+// the database keeps their hashes.
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
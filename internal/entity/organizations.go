@@ -0,0 +1,7 @@
+package entity
+
+type Organization struct {
+	Id         int    `json:"id" db:"id"`
+	Name       string `json:"name" db:"name"`
+	InviteCode string `json:"inviteCode" db:"invite_code"`
+}
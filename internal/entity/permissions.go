@@ -0,0 +1,83 @@
+package entity
+
+import "strings"
+
+// Permissions of the roles, stored in the database. Permissions ending in
+// :own, :team or :any are scopes of the same action, e.g. task:read:own lets a
+// user read their own tasks and task:read:team the tasks of their teammates.
+const (
+	PermissionTaskCreate       = "task:create"
+	PermissionTaskRead         = "task:read"
+	PermissionTaskUpdateOwn    = "task:update:own"
+	PermissionTaskDelete       = "task:delete"
+	PermissionTaskReassignTeam = "task:reassign:team"
+	PermissionTaskImport       = "task:import"
+	PermissionWorkLogCreateOwn = "worklog:create:own"
+	PermissionReportRead       = "report:read"
+	PermissionStatsRead        = "stats:read"
+	PermissionTeamManage       = "team:manage"
+	PermissionUserManage       = "user:manage"
+)
+
+// Scope is the set of tasks an action applies to, each scope includes the
+// ones before it.
+type Scope int
+
+const (
+	ScopeNone Scope = iota
+	ScopeOwn
+	ScopeTeam
+	ScopeAny
+)
+
+var scopeSuffixes = map[string]Scope{
+	"own":  ScopeOwn,
+	"team": ScopeTeam,
+	"any":  ScopeAny,
+}
+
+// Permissions are the permissions of a role.
+type Permissions map[string]bool
+
+func NewPermissions(names ...string) Permissions {
+	p := Permissions{}
+	for _, name := range names {
+		p[name] = true
+	}
+
+	return p
+}
+
+// Scope returns the widest scope of the action, ScopeNone when the role can't
+// do it at all.
+func (p Permissions) Scope(action string) Scope {
+	var widest Scope
+
+	for name := range p {
+		prefix, suffix, found := cutLast(name, ":")
+		if !found || prefix != action {
+			continue
+		}
+
+		if scope := scopeSuffixes[suffix]; scope > widest {
+			widest = scope
+		}
+	}
+
+	return widest
+}
+
+// Allows reports whether the role has the permission, or any scope of it when
+// the permission is an action with scopes.
+func (p Permissions) Allows(permission string) bool {
+	return p[permission] || p.Scope(permission) != ScopeNone
+}
+
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+
+	return s[:i], s[i+len(sep):], true
+}
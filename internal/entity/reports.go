@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"time"
+)
+
+// WorkLogEntry is a work log joined with its task, used to build reports.
+// Running timers have EndedAt set to the moment the entry was read.
+type WorkLogEntry struct {
+	Id        int       `db:"id"`
+	TaskId    int       `db:"task_id"`
+	TaskTitle string    `db:"task_title"`
+	StartedAt time.Time `db:"started_at"`
+	EndedAt   time.Time `db:"ended_at"`
+	Note      string    `db:"note"`
+}
+
+// TimesheetRequest is the timesheet of UserId asked by ViewerId, who reads the
+// timesheets of the users in Scope.
+type TimesheetRequest struct {
+	UserId   int
+	Date     time.Time
+	ViewerId int
+	Scope    Scope
+}
+
+type FinishedTask struct {
+	Id         int       `db:"id"`
+	Title      string    `db:"title"`
+	FinishedAt time.Time `db:"finished_at"`
+}
+
+type TimesheetTask struct {
+	TaskId          int    `json:"taskId"`
+	Title           string `json:"title"`
+	StartedAt       string `json:"startedAt"`
+	EndedAt         string `json:"endedAt"`
+	FinishedAt      string `json:"finishedAt"`
+	DurationSeconds int64  `json:"durationSeconds"`
+	Entries         int    `json:"entries"`
+}
+
+type TimesheetGap struct {
+	StartedAt       string `json:"startedAt"`
+	EndedAt         string `json:"endedAt"`
+	DurationSeconds int64  `json:"durationSeconds"`
+}
+
+type TimesheetResponse struct {
+	UserId       int             `json:"userId"`
+	UserName     string          `json:"userName"`
+	Date         string          `json:"date"`
+	TotalSeconds int64           `json:"totalSeconds"`
+	GapSeconds   int64           `json:"gapSeconds"`
+	Tasks        []TimesheetTask `json:"tasks"`
+	Gaps         []TimesheetGap  `json:"gaps"`
+}
+
+type WeeklyTimesheetResponse struct {
+	UserId       int                 `json:"userId"`
+	UserName     string              `json:"userName"`
+	WeekStart    string              `json:"weekStart"`
+	WeekEnd      string              `json:"weekEnd"`
+	TotalSeconds int64               `json:"totalSeconds"`
+	Days         []TimesheetResponse `json:"days"`
+}
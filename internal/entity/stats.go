@@ -0,0 +1,23 @@
+package entity
+
+type TasksPerDay struct {
+	Date     string `json:"date" db:"day"`
+	Created  int    `json:"created" db:"created"`
+	Finished int    `json:"finished" db:"finished"`
+	Deleted  int    `json:"deleted" db:"deleted"`
+}
+
+type TechnicianTaskCount struct {
+	UserId int    `json:"userId" db:"user_id"`
+	Name   string `json:"name" db:"name"`
+	Count  int    `json:"count" db:"count"`
+}
+
+type StatsResponse struct {
+	From                    string                `json:"from"`
+	To                      string                `json:"to"`
+	TasksPerDay             []TasksPerDay         `json:"tasksPerDay"`
+	MeanTimeToFinishSeconds int64                 `json:"meanTimeToFinishSeconds"`
+	OpenBacklog             []TechnicianTaskCount `json:"openBacklog"`
+	TopTechnicians          []TechnicianTaskCount `json:"topTechnicians"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type TeamRequest struct {
+	Name   string `json:"name"`
+	UserId int    `json:"-"`
+}
+
+func (c TeamRequest) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required, validation.Length(1, 50)))
+}
+
+// TeamMemberRequest adds or removes UserId of the team TeamId, ManagerId must
+// be a member of the team.
+type TeamMemberRequest struct {
+	TeamId    int
+	UserId    int
+	ManagerId int
+}
+
+type TeamResponse struct {
+	Id      int          `json:"id"`
+	Name    string       `json:"name"`
+	Members []TeamMember `json:"members"`
+}
+
+type TeamMember struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
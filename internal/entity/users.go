@@ -9,27 +9,55 @@ var (
 	VisitorRole    = 0
 	ManagerRole    = 10
 	TechnicianRole = 20
+	ServiceRole    = 30 // users api keys act as
 )
 
+// RoleName is the name of a role code, used in logs and metrics.
+func RoleName(codeRole int) string {
+	switch codeRole {
+	case ManagerRole:
+		return "manager"
+	case TechnicianRole:
+		return "technician"
+	case ServiceRole:
+		return "service"
+	default:
+		return "visitor"
+	}
+}
+
 type JwtCustomClaims struct {
-	Id       int    `json:"id"`
-	Name     string `json:"name"`
-	Username string `json:"username"`
-	CodeRole int    `json:"codeRole"`
+	Id             int    `json:"id"`
+	Name           string `json:"name"`
+	Username       string `json:"username"`
+	CodeRole       int    `json:"codeRole"`
+	OrganizationId int    `json:"organizationId"`
 	jwt.StandardClaims
 }
 
+// SignUpRequest either creates the organization named Organization, the user
+// becomes its manager, or joins the organization of InviteCode as a technician.
 type SignUpRequest struct {
-	Name     string `json:"name" db:"name"`
-	Username string `json:"username" db:"username"`
-	Password string `json:"password" db:"name"`
+	Name           string `json:"name" db:"name"`
+	Username       string `json:"username" db:"username"`
+	Password       string `json:"password" db:"name"`
+	Organization   string `json:"organization"`
+	InviteCode     string `json:"inviteCode"`
+	OrganizationId int    `json:"-"`
+	CodeRole       int    `json:"-"`
 }
 
 func (c SignUpRequest) Validate() error {
 	return validation.ValidateStruct(&c,
 		validation.Field(&c.Name, validation.Required, validation.Length(1, 50)),
 		validation.Field(&c.Username, validation.Required, validation.Length(1, 30)),
-		validation.Field(&c.Password, validation.Required, validation.Length(1, 50)))
+		validation.Field(&c.Password, validation.Required, validation.Length(1, 50)),
+		validation.Field(&c.Organization,
+			validation.When(c.InviteCode == "", validation.Required.Error("is required without an invite code")),
+			validation.Length(1, 100)),
+		validation.Field(&c.InviteCode,
+			validation.When(c.Organization != "", validation.Empty.Error("must be blank to create an organization")),
+			validation.Length(1, 64)))
 }
 
 type UserRole struct {
@@ -50,17 +78,27 @@ func (c SignInRequest) Validate() error {
 }
 
 type User struct {
-	Id       int    `json:"id" db:"id"`
-	Name     string `json:"name" db:"name"`
-	Username string `json:"username" db:"username"`
-	CodeRole int    `json:"codeRole" db:"code_role"`
-	Password string `json:"-" db:"password"`
+	Id             int    `json:"id" db:"id"`
+	Name           string `json:"name" db:"name"`
+	Username       string `json:"username" db:"username"`
+	CodeRole       int    `json:"codeRole" db:"code_role"`
+	OrganizationId int    `json:"organizationId" db:"organization_id"`
+	Password       string `json:"-" db:"password"`
+}
+
+// UserIdentity links the user UserId to the subject of the id tokens of an
+// identity provider.
+type UserIdentity struct {
+	Issuer  string
+	Subject string
+	UserId  int
 }
 
 type TaskRequest struct {
 	Title       string `json:"title" db:"title"`
 	Description string `json:"description" db:"description"`
 	UserId      int    `json:"-" db:"user_id"`
+	CodeRole    int    `json:"-" db:"-"`
 }
 
 func (c TaskRequest) Validate() error {
@@ -70,13 +108,14 @@ func (c TaskRequest) Validate() error {
 }
 
 type TaskResponse struct {
-	Id          int                       `json:"id"`
-	Title       string                    `json:"title"`
-	Description string                    `json:"description"`
-	UpdatedAt   string                    `json:"updatedAt"`
-	FinishedAt  string                    `json:"finishedAt"`
-	CreatedBy   TaskUserOperationResponse `json:"createdBy"`
-	DeletedBy   TaskUserOperationResponse `json:"deletedBy"`
+	Id               int                       `json:"id"`
+	Title            string                    `json:"title"`
+	Description      string                    `json:"description"`
+	UpdatedAt        string                    `json:"updatedAt"`
+	FinishedAt       string                    `json:"finishedAt"`
+	TimeSpentSeconds int64                     `json:"timeSpentSeconds"`
+	CreatedBy        TaskUserOperationResponse `json:"createdBy"`
+	DeletedBy        TaskUserOperationResponse `json:"deletedBy"`
 }
 
 type TaskUserOperationResponse struct {
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type WorkLogRequest struct {
+	TaskId    int       `json:"-" db:"task_id"`
+	UserId    int       `json:"-" db:"user_id"`
+	StartedAt time.Time `json:"startedAt" db:"started_at"`
+	EndedAt   time.Time `json:"endedAt" db:"ended_at"`
+	Note      string    `json:"note" db:"note"`
+}
+
+func (c WorkLogRequest) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.StartedAt, validation.Required, validation.Max(time.Now())),
+		validation.Field(&c.EndedAt, validation.Required, validation.Min(c.StartedAt).Exclusive(), validation.Max(time.Now())),
+		validation.Field(&c.Note, validation.Length(0, 500)))
+}
+
+type TimerStopRequest struct {
+	TaskId int    `json:"-"`
+	UserId int    `json:"-"`
+	Note   string `json:"note"`
+}
+
+func (c TimerStopRequest) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Note, validation.Length(0, 500)))
+}
+
+type WorkLogResponse struct {
+	Id              int                       `json:"id"`
+	TaskId          int                       `json:"taskId"`
+	StartedAt       string                    `json:"startedAt"`
+	EndedAt         string                    `json:"endedAt"`
+	DurationSeconds int64                     `json:"durationSeconds"`
+	Note            string                    `json:"note"`
+	LoggedBy        TaskUserOperationResponse `json:"loggedBy"`
+}
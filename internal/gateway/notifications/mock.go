@@ -1,6 +1,8 @@
 package notifications
 
 import (
+	"context"
+
 	"github.com/lucas-simao/api-tasks/internal/entity"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,4 +11,6 @@ type MockNotifications struct {
 	mock.Mock
 }
 
-func (ref *MockNotifications) NotifyManager(t entity.TaskResponse) {}
+func (ref *MockNotifications) NotifyManager(ctx context.Context, manager entity.User, t entity.TaskResponse) error {
+	return nil
+}
@@ -1,21 +1,44 @@
 package notifications
 
 import (
-	"fmt"
+	"context"
+	"log/slog"
 
 	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Notifications interface {
-	NotifyManager(t entity.TaskResponse)
+	// NotifyManager tells a manager of the technician that the task finished.
+	NotifyManager(ctx context.Context, manager entity.User, t entity.TaskResponse) error
 }
 
-func New() Notifications {
-	return notifications{}
+// New returns notifications written to logger, until managers have a real
+// channel to receive them.
+func New(logger *slog.Logger) Notifications {
+	return notifications{logger: logger}
 }
 
-type notifications struct{}
+type notifications struct {
+	logger *slog.Logger
+}
+
+func (n notifications) NotifyManager(ctx context.Context, manager entity.User, t entity.TaskResponse) error {
+	ctx, span := tracing.Tracer().Start(ctx, "notifications.NotifyManager",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attribute.Int("task.id", t.Id), attribute.Int("manager.id", manager.Id)),
+	)
+	defer span.End()
+
+	n.logger.InfoContext(ctx, "task finished, manager notified",
+		slog.Int("manager_id", manager.Id),
+		slog.Int("task_id", t.Id),
+		slog.String("title", t.Title),
+		slog.Int("technician_id", t.CreatedBy.Id),
+		slog.String("finished_at", t.FinishedAt),
+	)
 
-func (n notifications) NotifyManager(t entity.TaskResponse) {
-	fmt.Printf("\nThe tech %v performed the task %d - (%v), on date %v\n", t.CreatedBy.Name, t.Id, t.Title, t.FinishedAt)
+	return nil
 }
@@ -0,0 +1,353 @@
+// Package oidc signs users in with an OpenID Connect provider, with the
+// authorization code flow and PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+)
+
+var (
+	ErrSignInFailed   = apperror.Unauthorized("oidc_sign_in_failed", "sign in with the identity provider failed")
+	ErrInvalidIDToken = apperror.Unauthorized("invalid_id_token", "id token of the identity provider is invalid")
+	ErrUnavailable    = apperror.Unavailable("oidc_provider_unavailable", "identity provider is unavailable")
+)
+
+type Config struct {
+	// Issuer is the url of the provider, its configuration is read from
+	// Issuer/.well-known/openid-configuration
+	Issuer       string
+	ClientId     string
+	ClientSecret string
+	// RedirectURL is the callback of the api registered in the provider
+	RedirectURL string
+	// GroupsClaim is the claim of the id token with the groups of the user
+	GroupsClaim string
+}
+
+// Claims are the claims of a verified id token the api uses.
+type Claims struct {
+	Issuer            string
+	Subject           string
+	Name              string
+	PreferredUsername string
+	Email             string
+	Groups            []string
+}
+
+// Provider is an OpenID Connect provider. Its configuration is discovered on
+// first use, so the api starts while the provider is unavailable.
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]*rsa.PublicKey
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func New(c Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if c.GroupsClaim == "" {
+		c.GroupsClaim = "groups"
+	}
+
+	return &Provider{
+		config: c,
+		client: client,
+	}
+}
+
+// AuthCodeURL returns the url of the provider the user signs in at. The
+// provider redirects to the callback with the state, the id token has the
+// nonce and the code is only exchanged with the verifier.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientId},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {"openid profile email"},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {Challenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return d.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange exchanges the code of the callback for the id token and returns its
+// claims, once the token is verified.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (Claims, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientId},
+		"code_verifier": {verifier},
+	}
+	if p.config.ClientSecret != "" {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Claims{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Claims{}, ErrUnavailable.Wrap(fmt.Errorf("error to exchange the code: %w", err))
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+
+	decodeErr := json.NewDecoder(resp.Body).Decode(&token)
+
+	// invalid or expired codes are errors of the user, not of the api
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+		return Claims{}, ErrSignInFailed.Withf("sign in with the identity provider failed: %s", strings.TrimSpace(token.Error+" "+token.ErrorDescription))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Claims{}, ErrUnavailable.Wrap(fmt.Errorf("error to exchange the code: status %d", resp.StatusCode))
+	}
+	if decodeErr != nil {
+		return Claims{}, fmt.Errorf("error to read the token response: %w", decodeErr)
+	}
+	if token.IDToken == "" {
+		return Claims{}, ErrInvalidIDToken.Withf("token response without id token")
+	}
+
+	return p.verify(ctx, d, token.IDToken, nonce)
+}
+
+// verify checks the signature of the id token with the keys of the provider,
+// its issuer, audience, expiry and nonce.
+func (p *Provider) verify(ctx context.Context, d *discovery, idToken, nonce string) (Claims, error) {
+	var (
+		claims   = jwt.MapClaims{}
+		fetchErr error
+	)
+
+	_, err := jwt.ParseWithClaims(idToken, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %s", t.Header["alg"])
+		}
+
+		kid, _ := t.Header["kid"].(string)
+
+		key, err := p.key(ctx, d, kid)
+		if err != nil && !errors.Is(err, errUnknownKey) {
+			fetchErr = err
+		}
+
+		return key, err
+	})
+	if fetchErr != nil {
+		// the token may be valid, the provider is unavailable
+		return Claims{}, fetchErr
+	}
+	if err != nil {
+		return Claims{}, ErrInvalidIDToken.Wrap(err)
+	}
+
+	switch {
+	case !claims.VerifyIssuer(d.Issuer, true):
+		return Claims{}, ErrInvalidIDToken.Withf("id token of another issuer")
+	case !claims.VerifyAudience(p.config.ClientId, true):
+		return Claims{}, ErrInvalidIDToken.Withf("id token of another client")
+	case !claims.VerifyExpiresAt(time.Now().Unix(), true):
+		return Claims{}, ErrInvalidIDToken.Withf("id token expired")
+	case claims["nonce"] != nonce:
+		return Claims{}, ErrInvalidIDToken.Withf("id token of another sign in")
+	}
+
+	c := Claims{Issuer: d.Issuer}
+	c.Subject, _ = claims["sub"].(string)
+	c.Name, _ = claims["name"].(string)
+	c.PreferredUsername, _ = claims["preferred_username"].(string)
+	c.Email, _ = claims["email"].(string)
+
+	if c.Subject == "" {
+		return Claims{}, ErrInvalidIDToken.Withf("id token without subject")
+	}
+
+	// a single group may be sent as a string
+	switch groups := claims[p.config.GroupsClaim].(type) {
+	case string:
+		c.Groups = []string{groups}
+	case []interface{}:
+		for _, g := range groups {
+			if s, ok := g.(string); ok {
+				c.Groups = append(c.Groups, s)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+var errUnknownKey = errors.New("id token signed with an unknown key")
+
+// key returns the key kid of the provider, the keys are read again when kid
+// is unknown as providers rotate them.
+func (p *Provider) key(ctx context.Context, d *discovery, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+	p.mu.Unlock()
+
+	if ok {
+		return key, nil
+	}
+
+	keys, err := p.fetchKeys(ctx, d.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	key, ok = keys[kid]
+	if !ok {
+		return nil, errUnknownKey
+	}
+
+	return key, nil
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+
+	if err := p.get(ctx, jwksURI, &set); err != nil {
+		return nil, ErrUnavailable.Wrap(fmt.Errorf("error to get the keys of the identity provider: %w", err))
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s of the identity provider: %w", k.Kid, err)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s of the identity provider: %w", k.Kid, err)
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var d discovery
+
+	err := p.get(ctx, strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", &d)
+	if err != nil {
+		return nil, ErrUnavailable.Wrap(fmt.Errorf("error to discover the identity provider: %w", err))
+	}
+
+	if d.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("error to discover the identity provider: issuer %q instead of %q", d.Issuer, p.config.Issuer)
+	}
+
+	p.discovery = &d
+
+	return p.discovery, nil
+}
+
+func (p *Provider) get(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d from %s", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// Challenge is the S256 code challenge of the verifier.
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,114 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/gateway/oidc"
+	"github.com/lucas-simao/api-tasks/internal/gateway/oidc/oidctest"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "http://localhost:9000/sign-in/oidc/callback"
+
+func TestExchange(t *testing.T) {
+	idp := oidctest.New(oidctest.User{
+		Subject:           "248289761001",
+		Name:              "Jane Doe",
+		PreferredUsername: "jane",
+		Email:             "jane@example.com",
+		Groups:            []string{"maintenance-technicians"},
+	})
+	defer idp.Close()
+
+	p := oidc.New(idp.Config(redirectURL), nil)
+	ctx := context.Background()
+
+	authCodeURL, err := p.AuthCodeURL(ctx, "state", "nonce", "verifier")
+	require.NoError(t, err)
+
+	u, err := url.Parse(authCodeURL)
+	require.NoError(t, err)
+	require.Equal(t, oidc.Challenge("verifier"), u.Query().Get("code_challenge"))
+	require.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+
+	code, state, err := idp.Authorize(authCodeURL)
+	require.NoError(t, err)
+	require.Equal(t, "state", state)
+
+	claims, err := p.Exchange(ctx, code, "verifier", "nonce")
+	require.NoError(t, err)
+	require.Equal(t, oidc.Claims{
+		Issuer:            idp.Issuer(),
+		Subject:           "248289761001",
+		Name:              "Jane Doe",
+		PreferredUsername: "jane",
+		Email:             "jane@example.com",
+		Groups:            []string{"maintenance-technicians"},
+	}, claims)
+
+	// codes are used once
+	_, err = p.Exchange(ctx, code, "verifier", "nonce")
+	require.ErrorIs(t, err, oidc.ErrSignInFailed)
+}
+
+func TestExchangeErrors(t *testing.T) {
+	idp := oidctest.New(oidctest.User{Subject: "248289761001"})
+	defer idp.Close()
+
+	cases := map[string]struct {
+		config   func(c *oidc.Config)
+		verifier string
+		nonce    string
+		err      error
+	}{
+		"1 - Shouldn't exchange without the verifier of the challenge": {
+			verifier: "another verifier",
+			nonce:    "nonce",
+			err:      oidc.ErrSignInFailed,
+		},
+		"2 - Shouldn't accept id tokens of another sign in": {
+			verifier: "verifier",
+			nonce:    "another nonce",
+			err:      oidc.ErrInvalidIDToken,
+		},
+		"3 - Shouldn't exchange with a wrong client secret": {
+			config:   func(c *oidc.Config) { c.ClientSecret = "wrong" },
+			verifier: "verifier",
+			nonce:    "nonce",
+			err:      oidc.ErrSignInFailed,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := idp.Config(redirectURL)
+			if tc.config != nil {
+				tc.config(&c)
+			}
+
+			p := oidc.New(c, nil)
+			ctx := context.Background()
+
+			authCodeURL, err := p.AuthCodeURL(ctx, "state", "nonce", "verifier")
+			require.NoError(t, err)
+
+			code, _, err := idp.Authorize(authCodeURL)
+			require.NoError(t, err)
+
+			_, err = p.Exchange(ctx, code, tc.verifier, tc.nonce)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestUnavailableProvider(t *testing.T) {
+	idp := oidctest.New(oidctest.User{Subject: "248289761001"})
+	idp.Close()
+
+	p := oidc.New(idp.Config(redirectURL), nil)
+
+	_, err := p.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	require.ErrorIs(t, err, oidc.ErrUnavailable)
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware logs one line per request and adds the request id and the route
+// to every line logged with the request context. Client IPs, user agents and
+// query strings are left out, they can identify the user.
+func Middleware(logger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			req := c.Request()
+			ctx := WithFields(req.Context(),
+				slog.String("request_id", c.Response().Header().Get(echo.HeaderXRequestID)),
+				slog.String("route", c.Path()),
+			)
+			c.SetRequest(req.WithContext(ctx))
+
+			// errors are written here to know the status sent to the client,
+			// the error is still returned to the middlewares before this one
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+
+			level := slog.LevelInfo
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+
+			logger.LogAttrs(ctx, level, "request",
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Int("status", status),
+				slog.Int64("bytes_out", c.Response().Size),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			)
+
+			return err
+		}
+	}
+}
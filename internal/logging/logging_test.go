@@ -0,0 +1,138 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func lines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var result []map[string]interface{}
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &fields))
+		result = append(result, fields)
+	}
+
+	return result
+}
+
+func TestNew(t *testing.T) {
+	cases := map[string]struct {
+		config Config
+		err    bool
+	}{
+		"1 - Should use the defaults":       {config: Config{}},
+		"2 - Should accept text format":     {config: Config{Level: "debug", Format: FormatText}},
+		"3 - Should accept upper levels":    {config: Config{Level: "WARN"}},
+		"4 - Should reject unknown levels":  {config: Config{Level: "verbose"}, err: true},
+		"5 - Should reject unknown formats": {config: Config{Format: "xml"}, err: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(&bytes.Buffer{}, tc.config)
+			require.Equal(t, tc.err, err != nil)
+		})
+	}
+}
+
+func TestLevel(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger, err := New(&buf, Config{Level: "warn"})
+	require.NoError(t, err)
+
+	logger.Info("hidden")
+	logger.Warn("shown")
+
+	result := lines(t, &buf)
+	require.Len(t, result, 1)
+	require.Equal(t, "shown", result[0]["msg"])
+}
+
+func TestRedaction(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger, err := New(&buf, Config{})
+	require.NoError(t, err)
+
+	logger.Info("sign up",
+		slog.String("username", "lucas"),
+		slog.String("Password", "123456"),
+		slog.Group("task", slog.Int("id", 1), slog.String("description", "fix the client's door")),
+	)
+
+	result := lines(t, &buf)
+	require.Len(t, result, 1)
+	require.Equal(t, "lucas", result[0]["username"])
+	require.Equal(t, redactedValue, result[0]["Password"])
+	require.Equal(t, map[string]interface{}{"id": 1.0, "description": redactedValue}, result[0]["task"])
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger, err := New(&buf, Config{})
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.Use(middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		Generator: func() string { return "request-1" },
+	}))
+	e.Use(Middleware(logger))
+	e.GET("/tasks/:id", func(c echo.Context) error {
+		AddFields(c.Request().Context(), slog.Int("user_id", 7))
+		logger.InfoContext(c.Request().Context(), "get task")
+
+		if c.Param("id") == "0" {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	cases := map[string]struct {
+		path   string
+		status float64
+		level  string
+	}{
+		"1 - Should log success as info":       {path: "/tasks/1?token=secret", status: http.StatusOK, level: "INFO"},
+		"2 - Should log client errors as warn": {path: "/tasks/0", status: http.StatusNotFound, level: "WARN"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buf.Reset()
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			result := lines(t, &buf)
+			require.Len(t, result, 2)
+
+			for _, line := range result {
+				require.Equal(t, "request-1", line["request_id"])
+				require.Equal(t, "/tasks/:id", line["route"])
+				require.Equal(t, 7.0, line["user_id"])
+			}
+
+			request := result[1]
+			require.Equal(t, "request", request["msg"])
+			require.Equal(t, tc.level, request["level"])
+			require.Equal(t, tc.status, request["status"])
+			require.NotContains(t, request["path"], "token")
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/gateway/notifications"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "api_tasks"
+
+// Metrics keeps the collectors of the api in its own registry, so tests can
+// create as many as they need and read them without a Prometheus server.
+type Metrics struct {
+	registry         *prometheus.Registry
+	requests         *prometheus.CounterVec
+	requestsDuration *prometheus.HistogramVec
+	notifications    *prometheus.CounterVec
+	tasksCreated     *prometheus.CounterVec
+	tasksFinished    *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of http requests by route and status.",
+		}, []string{"method", "route", "status"}),
+		requestsDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of http requests by route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		notifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifications_total",
+			Help:      "Number of notifications sent to managers by result.",
+		}, []string{"result"}),
+		tasksCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tasks_created_total",
+			Help:      "Number of tasks created by the role of the user.",
+		}, []string{"role"}),
+		tasksFinished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tasks_finished_total",
+			Help:      "Number of tasks finished by the role of the user.",
+		}, []string{"role"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestsDuration,
+		m.notifications,
+		m.tasksCreated,
+		m.tasksFinished,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() echo.HandlerFunc {
+	return echo.WrapHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
+
+// Middleware counts the requests by route template, so /tasks/1 and /tasks/2
+// are the same route.
+func (m *Metrics) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			// errors are written here to know the status sent to the client,
+			// the error is still returned to the middlewares before this one
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			// paths without a route are grouped, otherwise every scan of the api
+			// would create new series
+			route := c.Path()
+			if route == "" || errors.Is(err, echo.ErrNotFound) || errors.Is(err, echo.ErrMethodNotAllowed) {
+				route = "unmatched"
+			}
+
+			status := strconv.Itoa(c.Response().Status)
+			method := c.Request().Method
+
+			m.requests.WithLabelValues(method, route, status).Inc()
+			m.requestsDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
+
+// CollectDB exposes the connection pool statistics returned by stats.
+func (m *Metrics) CollectDB(stats func() sql.DBStats) {
+	m.registry.MustRegister(newDBStatsCollector(stats))
+}
+
+// TasksCreated and TasksFinished implement tasks.Recorder.
+func (m *Metrics) TasksCreated(roleCode, count int) {
+	m.tasksCreated.WithLabelValues(entity.RoleName(roleCode)).Add(float64(count))
+}
+
+func (m *Metrics) TasksFinished(roleCode, count int) {
+	m.tasksFinished.WithLabelValues(entity.RoleName(roleCode)).Add(float64(count))
+}
+
+// Notifications counts the result of every notification sent by n.
+func (m *Metrics) Notifications(n notifications.Notifications) notifications.Notifications {
+	return countedNotifications{next: n, counter: m.notifications}
+}
+
+type countedNotifications struct {
+	next    notifications.Notifications
+	counter *prometheus.CounterVec
+}
+
+func (n countedNotifications) NotifyManager(ctx context.Context, manager entity.User, t entity.TaskResponse) error {
+	err := n.next.NotifyManager(ctx, manager, t)
+	if err != nil {
+		n.counter.WithLabelValues("failure").Inc()
+		return err
+	}
+
+	n.counter.WithLabelValues("success").Inc()
+	return nil
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNotifications struct{ err error }
+
+func (n fakeNotifications) NotifyManager(context.Context, entity.User, entity.TaskResponse) error {
+	return n.err
+}
+
+func scrape(t *testing.T, e *echo.Echo) string {
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	return rec.Body.String()
+}
+
+func TestMiddleware(t *testing.T) {
+	m := New()
+
+	// the middlewares before this one still see the errors
+	var errs []error
+
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			return err
+		}
+	})
+	e.Use(m.Middleware())
+	e.GET("/metrics", m.Handler())
+	e.GET("/tasks/:id", func(c echo.Context) error {
+		if c.Param("id") == "0" {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, path := range []string{"/tasks/1", "/tasks/2", "/tasks/0", "/unknown"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t, e)
+
+	require.Contains(t, body, `api_tasks_http_requests_total{method="GET",route="/tasks/:id",status="200"} 2`)
+	require.Contains(t, body, `api_tasks_http_requests_total{method="GET",route="/tasks/:id",status="404"} 1`)
+	require.Contains(t, body, `api_tasks_http_request_duration_seconds_count{method="GET",route="/tasks/:id",status="200"} 2`)
+	require.NotContains(t, body, `route="/tasks/1"`)
+	require.NotContains(t, body, `route="/unknown"`)
+	require.Contains(t, body, `api_tasks_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	require.Len(t, errs, 2)
+}
+
+func TestDomainCounters(t *testing.T) {
+	m := New()
+
+	m.TasksCreated(entity.TechnicianRole, 1)
+	m.TasksCreated(entity.ManagerRole, 3)
+	m.TasksFinished(entity.TechnicianRole, 2)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(m.tasksCreated.WithLabelValues("technician")))
+	require.Equal(t, 3.0, testutil.ToFloat64(m.tasksCreated.WithLabelValues("manager")))
+	require.Equal(t, 2.0, testutil.ToFloat64(m.tasksFinished.WithLabelValues("technician")))
+
+	ok := m.Notifications(fakeNotifications{})
+	failing := m.Notifications(fakeNotifications{err: errors.New("smtp down")})
+
+	require.NoError(t, ok.NotifyManager(context.Background(), entity.User{}, entity.TaskResponse{}))
+	require.NoError(t, ok.NotifyManager(context.Background(), entity.User{}, entity.TaskResponse{}))
+	require.Error(t, failing.NotifyManager(context.Background(), entity.User{}, entity.TaskResponse{}))
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.notifications.WithLabelValues("success")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.notifications.WithLabelValues("failure")))
+}
+
+func TestCollectDB(t *testing.T) {
+	m := New()
+	m.CollectDB(func() sql.DBStats {
+		return sql.DBStats{
+			MaxOpenConnections: 10,
+			OpenConnections:    4,
+			InUse:              3,
+			Idle:               1,
+			WaitCount:          7,
+			WaitDuration:       1500 * time.Millisecond,
+		}
+	})
+
+	e := echo.New()
+	e.GET("/metrics", m.Handler())
+
+	body := scrape(t, e)
+
+	require.Contains(t, body, "api_tasks_db_max_open_connections 10")
+	require.Contains(t, body, "api_tasks_db_open_connections 4")
+	require.Contains(t, body, "api_tasks_db_in_use_connections 3")
+	require.Contains(t, body, "api_tasks_db_idle_connections 1")
+	require.Contains(t, body, "api_tasks_db_wait_count_total 7")
+	require.Contains(t, body, "api_tasks_db_wait_duration_seconds_total 1.5")
+	require.Contains(t, body, `api_tasks_db_closed_connections_total{reason="max_lifetime"} 0`)
+}
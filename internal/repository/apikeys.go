@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+var (
+	ErrApiKeyNotFound    = apperror.NotFound("api_key_not_found", "api key not found")
+	ErrInvalidPermission = apperror.Invalid("invalid_permission", "permission don't exist or isn't given to any role")
+)
+
+// CreateApiKey creates the service user of the key in the organization of the
+// context, in the teams of the manager k.UserId, and the key with its
+// permissions.
+func (r *repository) CreateApiKey(ctx context.Context, k entity.ApiKeyRequest) (int64, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int64
+
+	err = r.inTx(ctx, func(tx *repository) error {
+		role, err := tx.GetUserRoleByCode(ctx, entity.ServiceRole)
+		if err != nil {
+			return err
+		}
+
+		// without a password the service user can't sign in
+		userId, err := tx.insert(ctx, r.sql.signUp, k.Name, k.Username, "", role.Id, organizationId)
+		if err != nil {
+			var duplicate *ErrDuplicate
+			if errors.As(err, &duplicate) && duplicate.Field == "username" {
+				return ErrUsernameUnavailable
+			}
+			return err
+		}
+
+		_, err = tx.db.ExecContext(ctx, r.sql.addApiKeyTeams, userId, organizationId, k.UserId)
+		if err != nil {
+			return err
+		}
+
+		id, err = tx.insert(ctx, r.sql.createApiKey, organizationId, userId, k.Name, k.KeyHash, k.UserId, k.ExpiresAt)
+		if err != nil {
+			return err
+		}
+
+		added := map[string]bool{}
+
+		for _, permission := range k.Permissions {
+			if added[permission] {
+				continue
+			}
+
+			result, err := tx.db.ExecContext(ctx, r.sql.addApiKeyPermission, id, permission)
+			if err != nil {
+				return err
+			}
+
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+
+			if affected == 0 {
+				return ErrInvalidPermission.Withf("permission %s don't exist or isn't given to any role", permission)
+			}
+
+			added[permission] = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetApiKeys returns the keys of the organization of the context, revoked and
+// expired ones included.
+func (r *repository) GetApiKeys(ctx context.Context) ([]entity.ApiKeyResponse, error) {
+	return r.getApiKeys(ctx, 0)
+}
+
+// GetApiKeyById returns a key of the organization of the context.
+func (r *repository) GetApiKeyById(ctx context.Context, id int) (entity.ApiKeyResponse, error) {
+	keys, err := r.getApiKeys(ctx, id)
+	if err != nil {
+		return entity.ApiKeyResponse{}, err
+	}
+
+	if len(keys) == 0 {
+		return entity.ApiKeyResponse{}, ErrApiKeyNotFound
+	}
+
+	return keys[0], nil
+}
+
+// getApiKeys returns the key id, or every key when id is 0.
+func (r *repository) getApiKeys(ctx context.Context, id int) ([]entity.ApiKeyResponse, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args := r.sql.getApiKeys, []interface{}{organizationId}
+	if id != 0 {
+		query, args = query+` AND k.id = ?`, append(args, id)
+	}
+
+	var keys []entity.ApiKeyResponse
+
+	err = r.read(ctx, func() error {
+		keys = []entity.ApiKeyResponse{}
+
+		rows, err := r.db.QueryContext(ctx, query+` ORDER BY k.id`, args...)
+		if err != nil {
+			return err
+		}
+
+		defer rows.Close()
+
+		for rows.Next() {
+			k := entity.ApiKeyResponse{Permissions: []string{}}
+
+			err := rows.Scan(
+				&k.Id,
+				&k.Name,
+				&k.UserId,
+				&k.ExpiresAt,
+				&k.LastUsedAt,
+				&k.RevokedAt,
+				&k.CreatedBy.Id,
+				&k.CreatedBy.Name,
+				&k.CreatedBy.Date,
+			)
+			if err != nil {
+				return err
+			}
+
+			keys = append(keys, k)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := r.getApiKeyPermissions(ctx, organizationId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range keys {
+		keys[i].Permissions = append(keys[i].Permissions, permissions[keys[i].Id]...)
+	}
+
+	return keys, nil
+}
+
+// getApiKeyPermissions returns the permissions of the keys of the
+// organization by key, of the key id only unless it's 0.
+func (r *repository) getApiKeyPermissions(ctx context.Context, organizationId, id int) (map[int][]string, error) {
+	query, args := r.sql.getApiKeyPermissions, []interface{}{organizationId}
+	if id != 0 {
+		query, args = query+` AND k.id = ?`, append(args, id)
+	}
+
+	var permissions map[int][]string
+
+	err := r.read(ctx, func() error {
+		permissions = map[int][]string{}
+
+		rows, err := r.db.QueryContext(ctx, query+` ORDER BY p.name`, args...)
+		if err != nil {
+			return err
+		}
+
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				keyId int
+				name  string
+			)
+
+			if err := rows.Scan(&keyId, &name); err != nil {
+				return err
+			}
+
+			permissions[keyId] = append(permissions[keyId], name)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// RevokeApiKey revokes a key of the organization of the context, requests with
+// it fail right away. Its service user stays the author of what it created.
+func (r *repository) RevokeApiKey(ctx context.Context, id int) error {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, r.sql.revokeApiKey, organizationId, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrApiKeyNotFound
+	}
+
+	return nil
+}
+
+// GetApiKeyPrincipal isn't scoped, the key of a request tells its organization.
+// Revoked and expired keys aren't found.
+func (r *repository) GetApiKeyPrincipal(ctx context.Context, keyHash string) (entity.ApiKeyPrincipal, error) {
+	var p entity.ApiKeyPrincipal
+
+	err := r.read(ctx, func() error {
+		return r.db.QueryRowContext(ctx, r.sql.getApiKeyPrincipal, keyHash, time.Now().UTC()).Scan(
+			&p.ApiKeyId,
+			&p.User.Id,
+			&p.User.Name,
+			&p.User.Username,
+			&p.User.CodeRole,
+			&p.User.OrganizationId,
+		)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ApiKeyPrincipal{}, ErrApiKeyNotFound
+		}
+		return entity.ApiKeyPrincipal{}, err
+	}
+
+	permissions, err := r.getApiKeyPermissions(ctx, p.User.OrganizationId, p.ApiKeyId)
+	if err != nil {
+		return entity.ApiKeyPrincipal{}, err
+	}
+
+	p.Permissions = permissions[p.ApiKeyId]
+
+	return p, nil
+}
+
+// TouchApiKey records the last use of the key, at most once a minute so keys
+// used by every request of a client don't write every time.
+func (r *repository) TouchApiKey(ctx context.Context, id int, usedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, r.sql.touchApiKey, usedAt, id, usedAt.Add(-time.Minute))
+	return err
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/tenant"
+	"github.com/stretchr/testify/suite"
+)
+
+type ApiKeysTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestApiKeysTestSuite(t *testing.T) {
+	suite.Run(t, new(ApiKeysTestSuite))
+}
+
+func (suite *ApiKeysTestSuite) SetupSuite() {
+	suite.ctx = organizationContext()
+}
+
+func (suite *ApiKeysTestSuite) createApiKey(name string, expiresAt time.Time, permissions ...string) (int, error) {
+	id, err := repo.CreateApiKey(suite.ctx, entity.ApiKeyRequest{
+		Name:        name,
+		Permissions: permissions,
+		ExpiresAt:   expiresAt,
+		UserId:      ManagerUser.Id,
+		Username:    "key-" + name,
+		KeyHash:     "hash-" + name,
+	})
+
+	return int(id), err
+}
+
+func (suite *ApiKeysTestSuite) TestCreateApiKey() {
+	id, err := suite.createApiKey("bms", time.Now().AddDate(0, 1, 0), entity.PermissionTaskCreate, "task:read:own", entity.PermissionTaskCreate)
+	suite.Require().NoError(err)
+
+	key, err := repo.GetApiKeyById(suite.ctx, id)
+	suite.NoError(err)
+	suite.Equal("bms", key.Name)
+	suite.Equal([]string{"task:create", "task:read:own"}, key.Permissions)
+	suite.Equal(ManagerUser.Id, key.CreatedBy.Id)
+	suite.Empty(key.LastUsedAt)
+	suite.Empty(key.RevokedAt)
+
+	p, err := repo.GetApiKeyPrincipal(context.Background(), "hash-bms")
+	suite.NoError(err)
+	suite.Equal(id, p.ApiKeyId)
+	suite.Equal(key.UserId, p.User.Id)
+	suite.Equal(entity.ServiceRole, p.User.CodeRole)
+	suite.Equal(OrganizationId, p.User.OrganizationId)
+	suite.Equal(key.Permissions, p.Permissions)
+
+	// the tasks of the key are tasks of the teams of the manager
+	taskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{Title: "alarm", Description: "sensor alarmed", UserId: p.User.Id})
+	suite.Require().NoError(err)
+
+	task, err := repo.GetTaskById(suite.ctx, int(taskId), ManagerUser.Id, entity.ScopeTeam)
+	suite.NoError(err)
+	suite.Equal(p.User.Id, task.CreatedBy.Id)
+	suite.Equal("bms", task.CreatedBy.Name)
+
+	err = repo.TouchApiKey(suite.ctx, id, time.Now().UTC())
+	suite.NoError(err)
+
+	key, err = repo.GetApiKeyById(suite.ctx, id)
+	suite.NoError(err)
+	suite.NotEmpty(key.LastUsedAt)
+}
+
+func (suite *ApiKeysTestSuite) TestCreateApiKeyWithInvalidPermission() {
+	cases := map[string]struct {
+		permission string
+	}{
+		"1 - Shouldn't create - permission don't exist": {
+			permission: "task:fly",
+		},
+		"2 - Shouldn't create - permission isn't given to any role": {
+			permission: "task:delete:any",
+		},
+	}
+
+	for key := range cases {
+		suite.Run(key, func() {
+			_, err := suite.createApiKey("invalid", time.Now().AddDate(0, 1, 0), entity.PermissionTaskCreate, cases[key].permission)
+			suite.ErrorIs(err, ErrInvalidPermission)
+
+			// nothing of the key is left
+			_, err = repo.GetApiKeyPrincipal(context.Background(), "hash-invalid")
+			suite.Equal(ErrApiKeyNotFound, err)
+		})
+	}
+}
+
+func (suite *ApiKeysTestSuite) TestRevokedAndExpiredKeysAreNotFound() {
+	id, err := suite.createApiKey("revoked", time.Now().AddDate(0, 1, 0), entity.PermissionTaskCreate)
+	suite.Require().NoError(err)
+
+	// keys of another organization can't be revoked
+	err = repo.RevokeApiKey(tenant.WithOrganization(context.Background(), OrganizationId+1000), id)
+	suite.Equal(ErrApiKeyNotFound, err)
+
+	err = repo.RevokeApiKey(suite.ctx, id)
+	suite.NoError(err)
+
+	err = repo.RevokeApiKey(suite.ctx, id)
+	suite.Equal(ErrApiKeyNotFound, err)
+
+	_, err = repo.GetApiKeyPrincipal(context.Background(), "hash-revoked")
+	suite.Equal(ErrApiKeyNotFound, err)
+
+	_, err = suite.createApiKey("expired", time.Now().Add(-time.Minute), entity.PermissionTaskCreate)
+	suite.Require().NoError(err)
+
+	_, err = repo.GetApiKeyPrincipal(context.Background(), "hash-expired")
+	suite.Equal(ErrApiKeyNotFound, err)
+
+	keys, err := repo.GetApiKeys(suite.ctx)
+	suite.NoError(err)
+
+	names := map[string]bool{}
+	for _, k := range keys {
+		names[k.Name] = true
+	}
+	suite.True(names["revoked"])
+	suite.True(names["expired"])
+}
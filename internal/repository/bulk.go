@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+var ErrBulkUnknownAction = apperror.Invalid("unknown_bulk_action", "unknown bulk action")
+
+// errBulkRollback rolls back the transaction of a bulk with a failed task, the
+// failure is reported in the results.
+var errBulkRollback = errors.New("bulk rolled back")
+
+// BulkUpdateTasks applies the action to every task id. In transaction mode all
+// changes are rolled back when any task fails, in per-item mode every task is
+// applied on its own. Task ids that don't match the action rules (e.g. finishing
+// a task of another technician, or deleting a task out of the manager's teams)
+// fail with ErrNoTaskInResult.
+func (r *repository) BulkUpdateTasks(ctx context.Context, b entity.BulkTaskRequest) (entity.BulkTaskResponse, error) {
+	response := entity.BulkTaskResponse{
+		Action:  b.Action,
+		Mode:    b.Mode,
+		Results: make([]entity.BulkTaskResult, 0, len(b.Ids)),
+	}
+
+	if b.Mode == entity.BulkModePerItem {
+		for _, id := range b.Ids {
+			// a savepoint when called in a transaction, where a failed
+			// statement can abort the rest of it on some databases
+			err := r.inTx(ctx, func(tx *repository) error {
+				return tx.bulkApply(ctx, b, id)
+			})
+			response.Results = append(response.Results, bulkResult(id, err))
+		}
+
+		countBulkResults(&response)
+
+		return response, nil
+	}
+
+	err := r.inTx(ctx, func(tx *repository) error {
+		var failed bool
+
+		for _, id := range b.Ids {
+			if failed {
+				response.Results = append(response.Results, entity.BulkTaskResult{Id: id, Status: entity.BulkStatusSkipped})
+				continue
+			}
+
+			err := tx.bulkApply(ctx, b, id)
+			if err != nil && !errors.Is(err, ErrNoTaskInResult) {
+				return err
+			}
+
+			result := bulkResult(id, err)
+			failed = result.Status == entity.BulkStatusFailed
+
+			response.Results = append(response.Results, result)
+		}
+
+		if failed {
+			return errBulkRollback
+		}
+
+		return nil
+	})
+	if errors.Is(err, errBulkRollback) {
+		for i := range response.Results {
+			if response.Results[i].Status == entity.BulkStatusOk {
+				response.Results[i].Status = entity.BulkStatusRolledBack
+			}
+		}
+	} else if err != nil {
+		return entity.BulkTaskResponse{}, err
+	}
+
+	countBulkResults(&response)
+
+	return response, nil
+}
+
+func (r *repository) bulkApply(ctx context.Context, b entity.BulkTaskRequest, taskId int) error {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var query string
+	var args []interface{}
+
+	switch b.Action {
+	case entity.BulkActionDelete:
+		query, args = scopeTasks(r.sql.deleteTaskById, []interface{}{b.UserId, organizationId, taskId}, b.UserId, b.Scope)
+	case entity.BulkActionRestore:
+		query, args = scopeTasks(r.sql.restoreTaskById, []interface{}{organizationId, taskId}, b.UserId, b.Scope)
+	case entity.BulkActionReassign:
+		query, args = r.sql.reassignTaskById, []interface{}{b.AssigneeId, organizationId, taskId, b.UserId}
+	case entity.BulkActionFinish:
+		query, args = r.sql.doneTaskById, []interface{}{organizationId, b.UserId, taskId}
+	default:
+		return ErrBulkUnknownAction.Withf("unknown bulk action %q", b.Action)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	idAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if idAffected == 0 {
+		return ErrNoTaskInResult
+	}
+
+	return nil
+}
+
+// bulkResult shows the message of app errors, other errors are kept in Err and
+// reported as an internal error.
+func bulkResult(taskId int, err error) entity.BulkTaskResult {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) && appErr.Kind != apperror.KindInternal {
+		return entity.BulkTaskResult{Id: taskId, Status: entity.BulkStatusFailed, Message: appErr.Message}
+	}
+
+	if err != nil {
+		return entity.BulkTaskResult{Id: taskId, Status: entity.BulkStatusFailed, Message: "internal error", Err: err}
+	}
+
+	return entity.BulkTaskResult{Id: taskId, Status: entity.BulkStatusOk}
+}
+
+func countBulkResults(response *entity.BulkTaskResponse) {
+	for _, r := range response.Results {
+		switch r.Status {
+		case entity.BulkStatusOk:
+			response.Succeeded++
+		default:
+			response.Failed++
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type BulkTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestBulkTestSuite(t *testing.T) {
+	suite.Run(t, new(BulkTestSuite))
+}
+
+func (suite *BulkTestSuite) SetupSuite() {
+	suite.ctx = organizationContext()
+}
+
+func (suite *BulkTestSuite) createTasks(n int) []int {
+	ids := make([]int, 0, n)
+
+	for i := 0; i < n; i++ {
+		id, err := repo.CreateTask(suite.ctx, entity.TaskRequest{
+			Title:       "test bulk",
+			Description: "test bulk for test",
+			UserId:      TechnicianUser.Id,
+		})
+		suite.NoError(err)
+
+		ids = append(ids, int(id))
+	}
+
+	return ids
+}
+
+func (suite *BulkTestSuite) TestBulkTransaction() {
+	ids := suite.createTasks(2)
+
+	response, err := repo.BulkUpdateTasks(suite.ctx, entity.BulkTaskRequest{
+		Action: entity.BulkActionDelete,
+		Mode:   entity.BulkModeTransaction,
+		Ids:    []int{ids[0], 0, ids[1]},
+		UserId: ManagerUser.Id,
+		Scope:  entity.ScopeTeam,
+	})
+	suite.NoError(err)
+	suite.Equal(0, response.Succeeded)
+	suite.Equal(3, response.Failed)
+	suite.Equal(entity.BulkStatusRolledBack, response.Results[0].Status)
+	suite.Equal(entity.BulkStatusFailed, response.Results[1].Status)
+	suite.Equal(ErrNoTaskInResult.Message, response.Results[1].Message)
+	suite.NoError(response.Results[1].Err)
+	suite.Equal(entity.BulkStatusSkipped, response.Results[2].Status)
+
+	task, err := repo.GetTaskById(suite.ctx, ids[0], ManagerUser.Id, entity.ScopeTeam)
+	suite.NoError(err)
+	suite.Equal(0, task.DeletedBy.Id)
+
+	response, err = repo.BulkUpdateTasks(suite.ctx, entity.BulkTaskRequest{
+		Action: entity.BulkActionDelete,
+		Mode:   entity.BulkModeTransaction,
+		Ids:    ids,
+		UserId: ManagerUser.Id,
+		Scope:  entity.ScopeTeam,
+	})
+	suite.NoError(err)
+	suite.Equal(2, response.Succeeded)
+
+	response, err = repo.BulkUpdateTasks(suite.ctx, entity.BulkTaskRequest{
+		Action: entity.BulkActionRestore,
+		Mode:   entity.BulkModeTransaction,
+		Ids:    ids,
+		UserId: ManagerUser.Id,
+		Scope:  entity.ScopeTeam,
+	})
+	suite.NoError(err)
+	suite.Equal(2, response.Succeeded)
+
+	task, err = repo.GetTaskById(suite.ctx, ids[0], ManagerUser.Id, entity.ScopeTeam)
+	suite.NoError(err)
+	suite.Equal(0, task.DeletedBy.Id)
+}
+
+func (suite *BulkTestSuite) TestBulkPerItem() {
+	ids := suite.createTasks(2)
+
+	response, err := repo.BulkUpdateTasks(suite.ctx, entity.BulkTaskRequest{
+		Action: entity.BulkActionFinish,
+		Mode:   entity.BulkModePerItem,
+		Ids:    []int{ids[0], 0, ids[1]},
+		UserId: TechnicianUser.Id,
+	})
+	suite.NoError(err)
+	suite.Equal(2, response.Succeeded)
+	suite.Equal(1, response.Failed)
+	suite.Equal(entity.BulkStatusFailed, response.Results[1].Status)
+
+	response, err = repo.BulkUpdateTasks(suite.ctx, entity.BulkTaskRequest{
+		Action:     entity.BulkActionReassign,
+		Mode:       entity.BulkModePerItem,
+		Ids:        ids,
+		AssigneeId: TechnicianUser.Id,
+		UserId:     ManagerUser.Id,
+	})
+	suite.NoError(err)
+	suite.Equal(0, response.Succeeded, "finished tasks can't be reassigned")
+
+	// the errors of the database aren't shown to clients
+	ctx, cancel := context.WithCancel(suite.ctx)
+	cancel()
+
+	response, err = repo.BulkUpdateTasks(ctx, entity.BulkTaskRequest{
+		Action: entity.BulkActionFinish,
+		Mode:   entity.BulkModePerItem,
+		Ids:    ids,
+		UserId: TechnicianUser.Id,
+	})
+	suite.NoError(err)
+	suite.Equal(2, response.Failed)
+	suite.Equal("internal error", response.Results[0].Message)
+	suite.ErrorIs(response.Results[0].Err, context.Canceled)
+}
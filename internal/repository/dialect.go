@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// dialect is what changes between the databases the repository runs on.
+type dialect struct {
+	// name is the driver of Config, driver the one registered in database/sql
+	name   string
+	driver string
+	system attribute.KeyValue
+	sql    queries
+	// returning databases return the id of inserts with RETURNING id, the
+	// others with LastInsertId
+	returning bool
+	// duplicate reports unique violations and the column, if the driver tells
+	duplicate    func(error) (string, bool)
+	isForeignKey func(error) bool
+	isTransient  func(error) bool
+	// isServerError reports errors sent by the database, as opposed to
+	// network errors
+	isServerError func(error) bool
+	// timeFormat is the text layout of times on databases without a time
+	// type, they're stored in UTC so comparing the text compares the times
+	timeFormat string
+	// dataSource adds the options the repository needs to the data source,
+	// nil keeps it
+	dataSource func(string) (string, error)
+	// migrate databases belong to the api, New applies their migrations
+	migrate bool
+}
+
+func dialectOf(driver string) (*dialect, error) {
+	switch driver {
+	case "", DriverMySQL:
+		return &mysqlDialect, nil
+	case DriverPostgres:
+		return &postgresDialect, nil
+	case DriverSQLite:
+		return &sqliteDialect, nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %q, expected %s, %s or %s", driver, DriverMySQL, DriverPostgres, DriverSQLite)
+	}
+}
+
+// bind converts the times in args to the timeFormat of the database.
+func (d *dialect) bind(args ...interface{}) []interface{} {
+	if d.timeFormat == "" {
+		return args
+	}
+
+	bound := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch t := arg.(type) {
+		case time.Time:
+			bound[i] = t.UTC().Format(d.timeFormat)
+		case *time.Time:
+			if t != nil {
+				bound[i] = t.UTC().Format(d.timeFormat)
+			}
+		default:
+			bound[i] = arg
+		}
+	}
+
+	return bound
+}
+
+// insert runs an INSERT and returns the id of the new row. sql.ErrNoRows is
+// returned when an INSERT ... SELECT inserts nothing.
+func (r *repository) insert(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	if r.returning {
+		var id int64
+		err := r.db.QueryRowContext(ctx, query+` RETURNING id`, args...).Scan(&id)
+		return id, r.classify(err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if affected == 0 {
+		return 0, sql.ErrNoRows
+	}
+
+	return result.LastInsertId()
+}
+
+// rebound rebinds the ? placeholders of every statement to the syntax of the
+// database, e.g. $1 on PostgreSQL, binds the arguments and classifies the
+// errors with the dialect. Statements returned by PreparexContext are only
+// rebound, the errors of rows are read with Scan.
+type rebound struct {
+	queryer
+	dialect *dialect
+}
+
+func (q rebound) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	result, err := q.queryer.ExecContext(ctx, q.Rebind(query), q.dialect.bind(args...)...)
+	return result, q.dialect.classify(err)
+}
+
+func (q rebound) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := q.queryer.QueryContext(ctx, q.Rebind(query), q.dialect.bind(args...)...)
+	return rows, q.dialect.classify(err)
+}
+
+func (q rebound) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	rows, err := q.queryer.QueryxContext(ctx, q.Rebind(query), q.dialect.bind(args...)...)
+	return rows, q.dialect.classify(err)
+}
+
+func (q rebound) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	return q.queryer.QueryRowxContext(ctx, q.Rebind(query), q.dialect.bind(args...)...)
+}
+
+func (q rebound) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return q.queryer.QueryRowContext(ctx, q.Rebind(query), q.dialect.bind(args...)...)
+}
+
+func (q rebound) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return q.dialect.classify(q.queryer.GetContext(ctx, dest, q.Rebind(query), q.dialect.bind(args...)...))
+}
+
+func (q rebound) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return q.dialect.classify(q.queryer.SelectContext(ctx, dest, q.Rebind(query), q.dialect.bind(args...)...))
+}
+
+func (q rebound) PreparexContext(ctx context.Context, query string) (*sqlx.Stmt, error) {
+	return q.queryer.PreparexContext(ctx, q.Rebind(query))
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+)
+
+// ErrForeignKey is returned by writes referencing a row that doesn't exist.
+var ErrForeignKey = apperror.Invalid("invalid_reference", "a referenced resource doesn't exist")
+
+// ErrDuplicate is returned by writes of a value another row already has in a
+// unique column. Field is the column, empty when the database doesn't say.
+type ErrDuplicate struct {
+	Field string
+	err   error
+}
+
+func (e *ErrDuplicate) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("duplicate value: %v", e.err)
+	}
+	return fmt.Sprintf("duplicate %s: %v", e.Field, e.err)
+}
+
+// Unwrap returns the conflict shown to clients and the error of the driver.
+func (e *ErrDuplicate) Unwrap() []error {
+	conflict := apperror.Conflict("duplicate_value", "value is already taken")
+	if e.Field != "" {
+		conflict.Fields = []apperror.FieldError{{Field: e.Field, Message: "is already taken"}}
+	}
+
+	return []error{conflict, e.err}
+}
+
+// classify converts the constraint violations of the driver to ErrDuplicate and
+// ErrForeignKey, other errors, sql.ErrNoRows included, are returned as they are.
+func (d *dialect) classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if field, ok := d.duplicate(err); ok {
+		return &ErrDuplicate{Field: field, err: err}
+	}
+
+	if d.isForeignKey(err) {
+		return ErrForeignKey.Wrap(err)
+	}
+
+	return err
+}
+
+// column returns the column of an index or a qualified column name, e.g.
+// username for users.username.
+func column(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	mysqlDuplicate := &mysql.MySQLError{Number: errMySQLDuplicate, Message: "Duplicate entry 'for key 'x'' for key 'users.username'"}
+	postgresDuplicate := &pgconn.PgError{Code: errPostgresDuplicate, TableName: "users", ConstraintName: "users_username_key"}
+
+	cases := map[string]struct {
+		dialect    dialect
+		err        error
+		duplicate  bool
+		field      string
+		foreignKey bool
+	}{
+		"1 - Should classify mysql duplicated keys":              {dialect: mysqlDialect, err: mysqlDuplicate, duplicate: true, field: "username"},
+		"2 - Should classify mysql 5.7 duplicated keys":          {dialect: mysqlDialect, err: &mysql.MySQLError{Number: errMySQLDuplicate, Message: "Duplicate entry 'lucas' for key 'username'"}, duplicate: true, field: "username"},
+		"3 - Should classify mysql foreign keys":                 {dialect: mysqlDialect, err: &mysql.MySQLError{Number: errMySQLForeignKey}, foreignKey: true},
+		"4 - Should classify wrapped postgres unique violations": {dialect: postgresDialect, err: fmt.Errorf("sign up: %w", postgresDuplicate), duplicate: true, field: "username"},
+		"5 - Should classify unique indexes without a field":     {dialect: postgresDialect, err: &pgconn.PgError{Code: errPostgresDuplicate, TableName: "users", ConstraintName: "idx_users_name"}, duplicate: true},
+		"6 - Should classify postgres foreign keys":              {dialect: postgresDialect, err: &pgconn.PgError{Code: errPostgresForeignKey}, foreignKey: true},
+		"7 - Should keep no rows":                                {dialect: mysqlDialect, err: sql.ErrNoRows},
+		"8 - Should keep other errors":                           {dialect: postgresDialect, err: &pgconn.PgError{Code: errPostgresDeadlock}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.dialect.classify(tc.err)
+
+			// the error of the driver stays reachable
+			require.ErrorIs(t, err, tc.err)
+
+			var duplicate *ErrDuplicate
+			require.Equal(t, tc.duplicate, errors.As(err, &duplicate))
+			if tc.duplicate {
+				require.Equal(t, tc.field, duplicate.Field)
+			}
+
+			require.Equal(t, tc.foreignKey, errors.Is(err, ErrForeignKey))
+		})
+	}
+
+	require.NoError(t, mysqlDialect.classify(nil))
+}
+
+func TestErrDuplicateIsConflict(t *testing.T) {
+	err := fmt.Errorf("error to sign up: %w", &ErrDuplicate{Field: "username", err: errors.New("driver")})
+
+	var appErr *apperror.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperror.KindConflict, appErr.Kind)
+	require.Equal(t, []apperror.FieldError{{Field: "username", Message: "is already taken"}}, appErr.Fields)
+}
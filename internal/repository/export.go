@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+// ExportTasks walks every task visible to the user calling fn for each one.
+// Rows are read from the open cursor one at a time, so the export size doesn't
+// affect memory usage. Returning an error from fn stops the export.
+func (r *repository) ExportTasks(ctx context.Context, userId int, scope entity.Scope, fn func(entity.TaskExport) error) error {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return err
+	}
+
+	query, args := scopeTasks(r.sql.exportTasks, []interface{}{organizationId}, userId, scope)
+
+	query += ` ORDER BY t.id`
+
+	// only opening the cursor is retried, fn may have seen rows already
+	var rows *sql.Rows
+	err = r.read(ctx, func() error {
+		var err error
+		rows, err = r.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			t                     entity.TaskExport
+			createdAt, updatedAt  sql.NullTime
+			finishedAt, deletedAt sql.NullTime
+		)
+
+		err := rows.Scan(
+			&t.Id,
+			&t.Title,
+			&t.Description,
+			&t.CreatedById,
+			&t.CreatedByName,
+			&createdAt,
+			&updatedAt,
+			&t.FinishedById,
+			&t.FinishedByName,
+			&finishedAt,
+			&t.DeletedById,
+			&t.DeletedByName,
+			&deletedAt,
+			&t.TimeSpentSeconds,
+		)
+		if err != nil {
+			return err
+		}
+
+		t.CreatedAt = formatNullTime(createdAt)
+		t.UpdatedAt = formatNullTime(updatedAt)
+		t.FinishedAt = formatNullTime(finishedAt)
+		t.DeletedAt = formatNullTime(deletedAt)
+
+		err = fn(t)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func formatNullTime(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+
+	return t.Time.UTC().Format(time.RFC3339)
+}
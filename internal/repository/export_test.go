@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExportTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestExportTestSuite(t *testing.T) {
+	suite.Run(t, new(ExportTestSuite))
+}
+
+func (suite *ExportTestSuite) SetupSuite() {
+	suite.ctx = organizationContext()
+}
+
+func (suite *ExportTestSuite) TestExportTasks() {
+	taskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{
+		Title:       "test export",
+		Description: "test export for test",
+		UserId:      TechnicianUser.Id,
+	})
+	suite.NoError(err)
+
+	_, err = repo.FinishTaskById(suite.ctx, int(taskId), TechnicianUser.Id)
+	suite.NoError(err)
+
+	var exported entity.TaskExport
+	var count int
+
+	err = repo.ExportTasks(suite.ctx, TechnicianUser.Id, entity.ScopeOwn, func(t entity.TaskExport) error {
+		count++
+		suite.Equal(TechnicianUser.Id, t.CreatedById)
+		if t.Id == int(taskId) {
+			exported = t
+		}
+		return nil
+	})
+	suite.NoError(err)
+	suite.GreaterOrEqual(count, 1)
+	suite.Equal(TechnicianUser.Id, exported.FinishedById)
+	suite.NotEmpty(exported.FinishedAt)
+	suite.Empty(exported.DeletedAt)
+	suite.Len(exported.Record(), len(entity.TaskExportColumns))
+
+	count = 0
+	err = repo.ExportTasks(suite.ctx, ManagerUser.Id, entity.ScopeTeam, func(t entity.TaskExport) error {
+		count++
+		return nil
+	})
+	suite.NoError(err)
+	suite.GreaterOrEqual(count, 1)
+
+	errStop := errors.New("stop")
+	err = repo.ExportTasks(suite.ctx, ManagerUser.Id, entity.ScopeTeam, func(t entity.TaskExport) error {
+		return errStop
+	})
+	suite.Equal(errStop, err)
+}
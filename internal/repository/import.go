@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+// GetUserIdsByUsernames maps the given usernames to user ids, usernames that
+// don't exist in the organization or aren't teammates of the user are left out
+// of the result.
+func (r *repository) GetUserIdsByUsernames(ctx context.Context, userId int, usernames []string) (map[string]int, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids = map[string]int{}
+
+	if len(usernames) == 0 {
+		return ids, nil
+	}
+
+	query, args, err := sqlx.In(r.sql.getUsersByUsernames, organizationId, usernames, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []entity.User
+
+	err = r.read(ctx, func() error {
+		users = users[:0]
+		return r.db.SelectContext(ctx, &users, query, args...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range users {
+		ids[u.Username] = u.Id
+	}
+
+	return ids, nil
+}
+
+// ImportTasks inserts the tasks in a single transaction keeping their original
+// timestamps, either all of them are imported or none.
+func (r *repository) ImportTasks(ctx context.Context, tasks []entity.TaskImport) (int64, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var imported int64
+
+	err = r.inTx(ctx, func(tx *repository) error {
+		stmt, err := tx.db.PreparexContext(ctx, r.sql.importTask)
+		if err != nil {
+			return err
+		}
+
+		defer stmt.Close()
+
+		for _, t := range tasks {
+			var finishedBy interface{}
+			updatedAt := t.CreatedAt
+
+			if t.FinishedAt != nil {
+				finishedBy = t.UserId
+				updatedAt = *t.FinishedAt
+			}
+
+			// prepared statements don't bind the arguments nor classify the errors
+			_, err := stmt.ExecContext(ctx, tx.bind(organizationId, t.Title, t.Description, t.UserId, finishedBy, t.CreatedAt, updatedAt, t.FinishedAt)...)
+			if err != nil {
+				return tx.classify(err)
+			}
+
+			imported++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return imported, nil
+}
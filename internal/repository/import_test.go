@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type ImportTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestImportTestSuite(t *testing.T) {
+	suite.Run(t, new(ImportTestSuite))
+}
+
+func (suite *ImportTestSuite) SetupSuite() {
+	suite.ctx = organizationContext()
+}
+
+func (suite *ImportTestSuite) TestGetUserIdsByUsernames() {
+	// a technician of the team of another manager
+	manager := SignUpManager(entity.User{Name: "nora", Username: "noraImport", Password: "123456"})
+	technician := SignUpTechnician(entity.User{Name: "tiago", Username: "tiagoImport", Password: "123456"})
+
+	teamId, err := repo.CreateTeam(suite.ctx, entity.TeamRequest{Name: "import", UserId: manager})
+	suite.Require().NoError(err)
+
+	err = repo.AddTeamMember(suite.ctx, entity.TeamMemberRequest{TeamId: int(teamId), UserId: technician, ManagerId: manager})
+	suite.Require().NoError(err)
+
+	ids, err := repo.GetUserIdsByUsernames(suite.ctx, ManagerUser.Id, []string{TechnicianUser.Username, ManagerUser.Username, "tiagoImport", "noraImport", "unknownUser"})
+	suite.NoError(err)
+	suite.Len(ids, 2)
+	suite.Equal(TechnicianUser.Id, ids[TechnicianUser.Username])
+	suite.Equal(ManagerUser.Id, ids[ManagerUser.Username])
+
+	ids, err = repo.GetUserIdsByUsernames(suite.ctx, manager, []string{TechnicianUser.Username, "tiagoImport"})
+	suite.NoError(err)
+	suite.Equal(map[string]int{"tiagoImport": technician}, ids)
+}
+
+func (suite *ImportTestSuite) TestImportTasks() {
+	createdAt := time.Date(2019, 3, 1, 8, 0, 0, 0, time.UTC)
+	finishedAt := createdAt.Add(2 * time.Hour)
+
+	imported, err := repo.ImportTasks(suite.ctx, []entity.TaskImport{
+		{Title: "imported open", Description: "imported open task", UserId: TechnicianUser.Id, CreatedAt: createdAt},
+		{Title: "imported finished", Description: "imported finished task", UserId: TechnicianUser.Id, CreatedAt: createdAt, FinishedAt: &finishedAt},
+	})
+	suite.NoError(err)
+	suite.Equal(int64(2), imported)
+
+	var finishedBy int
+	err = DB.Get(&finishedBy, `SELECT finished_by_user_id FROM tasks WHERE title = 'imported finished'`)
+	suite.NoError(err)
+	suite.Equal(TechnicianUser.Id, finishedBy)
+
+	_, err = repo.ImportTasks(suite.ctx, []entity.TaskImport{
+		{Title: "imported rollback", Description: "imported rollback task", UserId: TechnicianUser.Id, CreatedAt: createdAt},
+		{Title: "imported invalid", Description: "imported task without user", CreatedAt: createdAt},
+	})
+	suite.Error(err)
+
+	var count int
+	err = DB.Get(&count, `SELECT COUNT(*) FROM tasks WHERE title = 'imported rollback'`)
+	suite.NoError(err)
+	suite.Equal(0, count)
+}
@@ -2,23 +2,100 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/lucas-simao/api-tasks/internal/entity"
 )
 
 type Repository interface {
+	// connection pool
+	Stats() sql.DBStats
+	Ping(context.Context) error
+	Close() error
+
+	// transactions
+	WithTx(context.Context, func(Repository) error) error
+
+	// migrations
+	GetAppliedMigrations(context.Context) ([]string, error)
+
+	// organizations
+	CreateOrganization(context.Context, entity.Organization) (int64, error)
+	GetOrganization(context.Context) (entity.Organization, error)
+	GetOrganizationByInviteCode(context.Context, string) (entity.Organization, error)
+	UpdateInviteCode(context.Context, string) error
+
 	// authentication
 	SignUp(context.Context, entity.SignUpRequest) error
 	SignIn(context.Context, string) (entity.User, error)
 
+	// users
+	GetUserById(context.Context, int) (entity.User, error)
+	GetUserInScope(context.Context, int, int, entity.Scope) (entity.User, error)
+	GetUserByIdentity(context.Context, string, string) (entity.User, error)
+	AddUserIdentity(context.Context, entity.UserIdentity) error
+	UpdateUserRole(context.Context, int, int) error
+
+	// two-factor authentication
+	GetUserMFA(context.Context, int, time.Time) (entity.UserMFA, error)
+	CreateMFA(context.Context, int, string) error
+	EnableMFA(context.Context, int, int64, []string) error
+	UseMFAStep(context.Context, int, int64) error
+	UseRecoveryCode(context.Context, int, string) error
+	FailMFA(context.Context, int, time.Time, time.Time) error
+	DeleteMFA(context.Context, int) error
+
 	// roles
 	GetUserRoleByCode(context.Context, int) (entity.UserRole, error)
+	GetPermissionsByRole(context.Context, int) ([]string, error)
+
+	// api keys
+	CreateApiKey(context.Context, entity.ApiKeyRequest) (int64, error)
+	GetApiKeys(context.Context) ([]entity.ApiKeyResponse, error)
+	GetApiKeyById(context.Context, int) (entity.ApiKeyResponse, error)
+	RevokeApiKey(context.Context, int) error
+	GetApiKeyPrincipal(context.Context, string) (entity.ApiKeyPrincipal, error)
+	TouchApiKey(context.Context, int, time.Time) error
 
 	// tasks
 	CreateTask(context.Context, entity.TaskRequest) (int64, error)
-	GetTasks(context.Context, int, int) ([]entity.TaskResponse, error)
-	GetTaskById(context.Context, int, int, int) (entity.TaskResponse, error)
-	DeleteTaskById(context.Context, int, int) error
+	GetTasks(context.Context, int, entity.Scope) ([]entity.TaskResponse, error)
+	GetTaskById(context.Context, int, int, entity.Scope) (entity.TaskResponse, error)
+	DeleteTaskById(context.Context, int, int, entity.Scope) error
 	UpdateTaskById(context.Context, entity.TaskUpdateRequest) (entity.TaskResponse, error)
 	FinishTaskById(context.Context, int, int) (entity.TaskResponse, error)
+
+	// work logs
+	StartTimer(context.Context, int, int) (entity.WorkLogResponse, error)
+	StopTimer(context.Context, entity.TimerStopRequest) (entity.WorkLogResponse, error)
+	CreateWorkLog(context.Context, entity.WorkLogRequest) (entity.WorkLogResponse, error)
+	GetWorkLogs(context.Context, int, int, entity.Scope) ([]entity.WorkLogResponse, error)
+
+	// reports
+	GetWorkLogEntriesByUser(context.Context, int, time.Time, time.Time) ([]entity.WorkLogEntry, error)
+	GetFinishedTasksByUser(context.Context, int, time.Time, time.Time) ([]entity.FinishedTask, error)
+
+	// stats
+	GetTasksPerDay(context.Context, int, time.Time, time.Time) ([]entity.TasksPerDay, error)
+	GetMeanTimeToFinish(context.Context, int, time.Time, time.Time) (float64, error)
+	GetOpenBacklogByTechnician(context.Context, int) ([]entity.TechnicianTaskCount, error)
+	GetTopTechniciansByFinished(context.Context, int, time.Time, time.Time, int) ([]entity.TechnicianTaskCount, error)
+
+	// export
+	ExportTasks(context.Context, int, entity.Scope, func(entity.TaskExport) error) error
+
+	// import
+	GetUserIdsByUsernames(context.Context, int, []string) (map[string]int, error)
+	ImportTasks(context.Context, []entity.TaskImport) (int64, error)
+
+	// bulk
+	BulkUpdateTasks(context.Context, entity.BulkTaskRequest) (entity.BulkTaskResponse, error)
+
+	// teams
+	CreateTeam(context.Context, entity.TeamRequest) (int64, error)
+	GetTeamsByUser(context.Context, int) ([]entity.TeamResponse, error)
+	GetTeamManagersByUser(context.Context, int) ([]entity.User, error)
+	AddTeamMember(context.Context, entity.TeamMemberRequest) error
+	RemoveTeamMember(context.Context, entity.TeamMemberRequest) error
 }
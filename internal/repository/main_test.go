@@ -1,13 +1,16 @@
 package repository
 
 import (
+	"context"
 	"log"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lucas-simao/api-tasks/configs"
 	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/tenant"
 )
 
 var (
@@ -27,44 +30,88 @@ var (
 	}
 	ManagerRoleId    int = 2
 	TechnicianRoleId int = 3
+	TeamId           int
+	OrganizationId   int
 )
 
 func TestMain(m *testing.M) {
-	port := "3322"
-	newContainer := configs.ContainerRun(port)
-	newContainer.RunMigrations("../../scripts/migrations")
-	repo = New()
-	DB = newContainer.DB
+	// REPOSITORY_DRIVER=postgres runs the suite on PostgreSQL, sqlite on a
+	// temporary file without Docker
+	var (
+		config Config
+		down   func()
+	)
+	switch os.Getenv("REPOSITORY_DRIVER") {
+	case DriverSQLite:
+		dir, err := os.MkdirTemp("", "repository")
+		if err != nil {
+			log.Fatal(err)
+		}
+		config = Config{Driver: DriverSQLite, DataSource: filepath.Join(dir, "api.db")}
+		down = func() { os.RemoveAll(dir) }
+	case DriverPostgres:
+		newContainer := configs.PostgresContainerRun("5433")
+		newContainer.RunMigrations("../../scripts/migrations/postgres")
+		config = Config{Driver: newContainer.Driver, DataSource: newContainer.URL}
+		down = newContainer.ContainerDown
+	default:
+		newContainer := configs.ContainerRun("3322")
+		newContainer.RunMigrations("../../scripts/migrations")
+		config = Config{Driver: newContainer.Driver, DataSource: newContainer.URL}
+		down = newContainer.ContainerDown
+	}
+	var err error
+	repo, err = New(context.Background(), config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	DB = repo.(*repository).conn
+
+	id, err := repo.CreateOrganization(context.Background(), entity.Organization{Name: "repository", InviteCode: "repository"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	OrganizationId = int(id)
 
 	TechnicianUser.Id = SignUpTechnician(TechnicianUser)
 	ManagerUser.Id = SignUpManager(ManagerUser)
 
-	code := m.Run()
-	newContainer.ContainerDown()
-	os.Exit(code)
-}
-
-func SignUpTechnician(t entity.User) int {
-	result, err := DB.Exec(sqlSignUp, t.Name, t.Username, t.Password, TechnicianRoleId)
+	// managers only see the tasks of their teams
+	id, err = repo.CreateTeam(organizationContext(), entity.TeamRequest{Name: "repository", UserId: ManagerUser.Id})
 	if err != nil {
 		log.Fatal(err)
 	}
+	TeamId = int(id)
 
-	id, err := result.LastInsertId()
+	err = repo.AddTeamMember(organizationContext(), entity.TeamMemberRequest{TeamId: TeamId, UserId: TechnicianUser.Id, ManagerId: ManagerUser.Id})
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	return int(id)
+	code := m.Run()
+	repo.Close()
+	down()
+	os.Exit(code)
+}
+
+// organizationContext scopes the queries to the organization of the users of
+// the tests.
+func organizationContext() context.Context {
+	return tenant.WithOrganization(context.Background(), OrganizationId)
+}
+
+func SignUpTechnician(t entity.User) int {
+	return signUp(t, TechnicianRoleId, OrganizationId)
 }
 
 func SignUpManager(t entity.User) int {
-	result, err := DB.Exec(sqlSignUp, t.Name, t.Username, t.Password, ManagerRoleId)
-	if err != nil {
-		log.Fatal(err)
-	}
+	return signUp(t, ManagerRoleId, OrganizationId)
+}
+
+func signUp(t entity.User, roleId, organizationId int) int {
+	r := repo.(*repository)
 
-	id, err := result.LastInsertId()
+	id, err := r.insert(context.Background(), r.sql.signUp, t.Name, t.Username, t.Password, roleId, organizationId)
 	if err != nil {
 		log.Fatal(err)
 	}
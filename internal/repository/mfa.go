@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+var (
+	ErrMFANotFound = apperror.NotFound("mfa_not_found", "two-factor authentication isn't set up")
+	ErrMFAEnabled  = apperror.Conflict("mfa_already_enabled", "two-factor authentication is already enabled")
+	ErrMFACodeUsed = apperror.Unauthorized("mfa_code_used", "code was already used")
+)
+
+// GetUserMFA returns the two-factor authentication of a user of the
+// organization of the context, with the failed attempts since the time.
+func (r *repository) GetUserMFA(ctx context.Context, userId int, failedSince time.Time) (entity.UserMFA, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return entity.UserMFA{}, err
+	}
+
+	var m entity.UserMFA
+
+	err = r.read(ctx, func() error {
+		return r.db.GetContext(ctx, &m, r.sql.getUserMFA, failedSince, organizationId, userId)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.UserMFA{}, ErrMFANotFound
+		}
+		return entity.UserMFA{}, err
+	}
+
+	return m, nil
+}
+
+// CreateMFA sets the secret of a user not enabled yet, replacing the secret of
+// an enrolment not finished.
+func (r *repository) CreateMFA(ctx context.Context, userId int, secret string) error {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.inTx(ctx, func(tx *repository) error {
+		_, err := tx.db.ExecContext(ctx, r.sql.deletePendingMFA, organizationId, userId)
+		if err != nil {
+			return err
+		}
+
+		err = tx.execOne(ctx, ErrUserNotExist, r.sql.createMFA, secret, organizationId, userId)
+		if err != nil {
+			var duplicate *ErrDuplicate
+			if errors.As(err, &duplicate) {
+				return ErrMFAEnabled
+			}
+			return err
+		}
+
+		return nil
+	})
+}
+
+// EnableMFA enables the secret of the user, verified with a code of the step,
+// and replaces its recovery codes.
+func (r *repository) EnableMFA(ctx context.Context, userId int, step int64, recoveryCodeHashes []string) error {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.inTx(ctx, func(tx *repository) error {
+		err := tx.execOne(ctx, ErrMFANotFound, r.sql.enableMFA, time.Now().UTC(), step, organizationId, userId)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.db.ExecContext(ctx, r.sql.deleteRecoveryCodes, organizationId, userId)
+		if err != nil {
+			return err
+		}
+
+		for _, hash := range recoveryCodeHashes {
+			_, err = tx.db.ExecContext(ctx, r.sql.addRecoveryCode, userId, hash)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// UseMFAStep records the step of a valid code, codes of that step or older ones
+// return ErrMFACodeUsed.
+func (r *repository) UseMFAStep(ctx context.Context, userId int, step int64) error {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.execOne(ctx, ErrMFACodeUsed, r.sql.useMFAStep, step, step, organizationId, userId)
+}
+
+// UseRecoveryCode uses a recovery code of the user, used or unknown codes
+// return ErrMFACodeUsed. Like a valid code, it resets the failures.
+func (r *repository) UseRecoveryCode(ctx context.Context, userId int, codeHash string) error {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.inTx(ctx, func(tx *repository) error {
+		err := tx.execOne(ctx, ErrMFACodeUsed, r.sql.useRecoveryCode, time.Now().UTC(), codeHash, organizationId, userId)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.db.ExecContext(ctx, r.sql.resetFailedMFA, organizationId, userId)
+		return err
+	})
+}
+
+// FailMFA records a wrong code of the user, the failures before failedSince
+// aren't counted.
+func (r *repository) FailMFA(ctx context.Context, userId int, failedAt, failedSince time.Time) error {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, r.sql.failMFA, failedSince, failedAt, organizationId, userId)
+	return err
+}
+
+// DeleteMFA disables the two-factor authentication of the user, with its
+// recovery codes.
+func (r *repository) DeleteMFA(ctx context.Context, userId int) error {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.inTx(ctx, func(tx *repository) error {
+		_, err := tx.db.ExecContext(ctx, r.sql.deleteRecoveryCodes, organizationId, userId)
+		if err != nil {
+			return err
+		}
+
+		return tx.execOne(ctx, ErrMFANotFound, r.sql.deleteMFA, organizationId, userId)
+	})
+}
+
+// execOne runs a statement changing a row, notFound is returned when it
+// changes none.
+func (r *repository) execOne(ctx context.Context, notFound error, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return notFound
+	}
+
+	return nil
+}
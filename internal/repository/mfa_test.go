@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/tenant"
+	"github.com/stretchr/testify/suite"
+)
+
+type MFATestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestMFATestSuite(t *testing.T) {
+	suite.Run(t, new(MFATestSuite))
+}
+
+func (suite *MFATestSuite) SetupSuite() {
+	suite.ctx = organizationContext()
+}
+
+func (suite *MFATestSuite) TestEnableMFA() {
+	id := SignUpManager(entity.User{Name: "eva", Username: "evaMFA", Password: "123456"})
+
+	_, err := repo.GetUserMFA(suite.ctx, id, time.Now())
+	suite.Equal(ErrMFANotFound, err)
+
+	suite.Require().NoError(repo.CreateMFA(suite.ctx, id, "FIRSTSECRET"))
+
+	// enrolments not finished are replaced
+	suite.Require().NoError(repo.CreateMFA(suite.ctx, id, "SECONDSECRET"))
+
+	m, err := repo.GetUserMFA(suite.ctx, id, time.Now())
+	suite.NoError(err)
+	suite.Equal("SECONDSECRET", m.Secret)
+	suite.False(m.Enabled)
+
+	// codes of secrets not enabled can't be used
+	err = repo.UseMFAStep(suite.ctx, id, 100)
+	suite.Equal(ErrMFACodeUsed, err)
+
+	err = repo.EnableMFA(suite.ctx, id, 100, []string{"hash-1", "hash-2"})
+	suite.NoError(err)
+
+	m, err = repo.GetUserMFA(suite.ctx, id, time.Now())
+	suite.NoError(err)
+	suite.True(m.Enabled)
+	suite.Equal(int64(100), m.LastStep)
+
+	err = repo.CreateMFA(suite.ctx, id, "THIRDSECRET")
+	suite.Equal(ErrMFAEnabled, err)
+
+	// a step is used once
+	err = repo.UseMFAStep(suite.ctx, id, 100)
+	suite.Equal(ErrMFACodeUsed, err)
+
+	err = repo.UseMFAStep(suite.ctx, id, 101)
+	suite.NoError(err)
+
+	// a recovery code is used once
+	err = repo.UseRecoveryCode(suite.ctx, id, "hash-1")
+	suite.NoError(err)
+
+	err = repo.UseRecoveryCode(suite.ctx, id, "hash-1")
+	suite.Equal(ErrMFACodeUsed, err)
+
+	err = repo.UseRecoveryCode(suite.ctx, id, "unknown")
+	suite.Equal(ErrMFACodeUsed, err)
+
+	// users of another organization can't be changed
+	other := tenant.WithOrganization(context.Background(), OrganizationId+1000)
+
+	_, err = repo.GetUserMFA(other, id, time.Now())
+	suite.Equal(ErrMFANotFound, err)
+
+	err = repo.UseRecoveryCode(other, id, "hash-2")
+	suite.Equal(ErrMFACodeUsed, err)
+
+	err = repo.DeleteMFA(other, id)
+	suite.Equal(ErrMFANotFound, err)
+
+	err = repo.DeleteMFA(suite.ctx, id)
+	suite.NoError(err)
+
+	_, err = repo.GetUserMFA(suite.ctx, id, time.Now())
+	suite.Equal(ErrMFANotFound, err)
+}
+
+func (suite *MFATestSuite) TestFailMFA() {
+	id := SignUpManager(entity.User{Name: "ivo", Username: "ivoMFA", Password: "123456"})
+
+	suite.Require().NoError(repo.CreateMFA(suite.ctx, id, "SECRET"))
+	suite.Require().NoError(repo.EnableMFA(suite.ctx, id, 100, nil))
+
+	failedAt := time.Now().UTC()
+
+	suite.NoError(repo.FailMFA(suite.ctx, id, failedAt, failedAt.Add(-time.Minute)))
+	suite.NoError(repo.FailMFA(suite.ctx, id, failedAt, failedAt.Add(-time.Minute)))
+
+	m, err := repo.GetUserMFA(suite.ctx, id, failedAt.Add(-time.Minute))
+	suite.NoError(err)
+	suite.Equal(2, m.FailedAttempts)
+
+	// failures before the time aren't counted
+	m, err = repo.GetUserMFA(suite.ctx, id, failedAt.Add(time.Minute))
+	suite.NoError(err)
+	suite.Equal(0, m.FailedAttempts)
+
+	// a valid code resets the failures
+	suite.NoError(repo.UseMFAStep(suite.ctx, id, 101))
+
+	m, err = repo.GetUserMFA(suite.ctx, id, failedAt.Add(-time.Minute))
+	suite.NoError(err)
+	suite.Equal(0, m.FailedAttempts)
+}
+
+func (suite *MFATestSuite) TestFailMFAAfterLock() {
+	id := SignUpManager(entity.User{Name: "rui", Username: "ruiMFA", Password: "123456"})
+
+	suite.Require().NoError(repo.CreateMFA(suite.ctx, id, "SECRET"))
+	suite.Require().NoError(repo.EnableMFA(suite.ctx, id, 100, []string{"hash-1"}))
+
+	window := 15 * time.Minute
+	lockedAt := time.Now().UTC().Add(-time.Hour)
+
+	for i := 0; i < 5; i++ {
+		suite.NoError(repo.FailMFA(suite.ctx, id, lockedAt, lockedAt.Add(-window)))
+	}
+
+	m, err := repo.GetUserMFA(suite.ctx, id, lockedAt.Add(-window))
+	suite.NoError(err)
+	suite.Equal(5, m.FailedAttempts)
+
+	// once the window passed a wrong code is the first failure again
+	failedAt := time.Now().UTC()
+
+	suite.NoError(repo.FailMFA(suite.ctx, id, failedAt, failedAt.Add(-window)))
+
+	m, err = repo.GetUserMFA(suite.ctx, id, failedAt.Add(-window))
+	suite.NoError(err)
+	suite.Equal(1, m.FailedAttempts)
+
+	// a recovery code resets the failures
+	suite.NoError(repo.UseRecoveryCode(suite.ctx, id, "hash-1"))
+
+	m, err = repo.GetUserMFA(suite.ctx, id, failedAt.Add(-window))
+	suite.NoError(err)
+	suite.Equal(0, m.FailedAttempts)
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+
+	"github.com/lucas-simao/api-tasks/scripts/migrations"
+)
+
+func (r *repository) GetAppliedMigrations(ctx context.Context) ([]string, error) {
+	var names []string
+
+	err := r.read(ctx, func() error {
+		names = names[:0]
+		return r.db.SelectContext(ctx, &names, r.sql.getAppliedMigrations)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// migrate applies the embedded migrations missing from the database, each one
+// in a transaction. The migrations record themselves in the migrations table.
+func (r *repository) migrate(ctx context.Context) error {
+	fsys, err := migrations.FS(r.name)
+	if err != nil {
+		return err
+	}
+
+	up, err := migrations.Up(r.name)
+	if err != nil {
+		return err
+	}
+
+	applied := map[string]bool{}
+
+	var tables int
+	if err := r.db.GetContext(ctx, &tables, r.sql.countMigrationsTables); err != nil {
+		return err
+	}
+
+	// a new database has no migrations table until the first migration
+	if tables > 0 {
+		names, err := r.GetAppliedMigrations(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			applied[name] = true
+		}
+	}
+
+	for _, name := range up {
+		if applied[name] {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		err = r.inTx(ctx, func(tx *repository) error {
+			_, err := tx.db.ExecContext(ctx, string(content))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("error to apply %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
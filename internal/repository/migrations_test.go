@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/scripts/migrations"
+	"github.com/stretchr/testify/suite"
+)
+
+type MigrationsTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestMigrationsTestSuite(t *testing.T) {
+	suite.Run(t, new(MigrationsTestSuite))
+}
+
+func (suite *MigrationsTestSuite) SetupSuite() {
+	suite.ctx = organizationContext()
+}
+
+func (suite *MigrationsTestSuite) TestGetAppliedMigrations() {
+	up, err := migrations.Up(repo.(*repository).dialect.name)
+	suite.NoError(err)
+
+	applied, err := repo.GetAppliedMigrations(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(up, applied)
+}
+
+func (suite *MigrationsTestSuite) TestPing() {
+	suite.NoError(repo.Ping(suite.ctx))
+}
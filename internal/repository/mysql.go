@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+const (
+	errMySQLDuplicate       = 1062
+	errMySQLForeignKey      = 1452
+	errMySQLLockWaitTimeout = 1205
+	errMySQLDeadlock        = 1213
+)
+
+var mysqlDialect = dialect{
+	name:         DriverMySQL,
+	driver:       "mysql",
+	system:       semconv.DBSystemMySQL,
+	sql:          mysqlQueries,
+	duplicate:    mysqlDuplicate,
+	isForeignKey: isMySQLForeignKey,
+	isTransient:  isMySQLTransient,
+	isServerError: func(err error) bool {
+		var mysqlErr *mysql.MySQLError
+		return errors.As(err, &mysqlErr)
+	},
+}
+
+// mysqlDuplicate returns the unique key of a duplicate entry, MySQL only sends
+// it in the message: Duplicate entry 'lucas' for key 'users.username'.
+func mysqlDuplicate(err error) (string, bool) {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != errMySQLDuplicate {
+		return "", false
+	}
+
+	i := strings.LastIndex(mysqlErr.Message, "for key '")
+	if i < 0 {
+		return "", true
+	}
+
+	return column(strings.TrimSuffix(mysqlErr.Message[i+len("for key '"):], "'")), true
+}
+
+// isMySQLForeignKey reports inserts and updates referencing a missing row.
+func isMySQLForeignKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == errMySQLForeignKey
+}
+
+// isMySQLTransient reports errors that can succeed when the query runs again.
+func isMySQLTransient(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == errMySQLDeadlock || mysqlErr.Number == errMySQLLockWaitTimeout
+	}
+
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn)
+}
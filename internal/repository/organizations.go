@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/tenant"
+)
+
+var (
+	ErrOrganizationNotFound = apperror.NotFound("organization_not_found", "organization not found")
+
+	// ErrNoOrganization is returned by queries of tenant data called with a
+	// context not scoped to an organization, see tenant.WithOrganization.
+	ErrNoOrganization = errors.New("context without organization")
+)
+
+// organization returns the organization the queries of ctx are scoped to.
+// Queries of tenant data fail without one, so a missing scope never reads the
+// data of every organization.
+func organization(ctx context.Context) (int, error) {
+	id, ok := tenant.Organization(ctx)
+	if !ok {
+		return 0, ErrNoOrganization
+	}
+
+	return id, nil
+}
+
+// CreateOrganization isn't scoped, users create an organization at sign up.
+func (r *repository) CreateOrganization(ctx context.Context, o entity.Organization) (int64, error) {
+	return r.insert(ctx, r.sql.createOrganization, o.Name, o.InviteCode)
+}
+
+// GetOrganization returns the organization of the context.
+func (r *repository) GetOrganization(ctx context.Context) (entity.Organization, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return entity.Organization{}, err
+	}
+
+	return r.getOrganization(ctx, r.sql.getOrganizationById, organizationId)
+}
+
+// GetOrganizationByInviteCode isn't scoped, users join an organization with
+// its invite code at sign up.
+func (r *repository) GetOrganizationByInviteCode(ctx context.Context, inviteCode string) (entity.Organization, error) {
+	return r.getOrganization(ctx, r.sql.getOrganizationByInviteCode, inviteCode)
+}
+
+// UpdateInviteCode replaces the invite code of the organization of the
+// context, the previous code stops working.
+func (r *repository) UpdateInviteCode(ctx context.Context, inviteCode string) error {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, r.sql.updateOrganizationInviteCode, inviteCode, organizationId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrOrganizationNotFound
+	}
+
+	return nil
+}
+
+func (r *repository) getOrganization(ctx context.Context, query string, args ...interface{}) (entity.Organization, error) {
+	var o entity.Organization
+
+	err := r.read(ctx, func() error {
+		return r.db.GetContext(ctx, &o, query, args...)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Organization{}, ErrOrganizationNotFound
+		}
+		return entity.Organization{}, err
+	}
+
+	return o, nil
+}
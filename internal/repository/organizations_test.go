@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/tenant"
+	"github.com/stretchr/testify/suite"
+)
+
+type OrganizationsTestSuite struct {
+	suite.Suite
+	ctx context.Context
+
+	// other organization with a manager and a technician in a team, the
+	// technician has a task
+	other                       context.Context
+	otherId                     int
+	manager, technician, teamId int
+	taskId                      int
+}
+
+func TestOrganizationsTestSuite(t *testing.T) {
+	suite.Run(t, new(OrganizationsTestSuite))
+}
+
+func (suite *OrganizationsTestSuite) SetupSuite() {
+	suite.ctx = organizationContext()
+
+	id, err := repo.CreateOrganization(context.Background(), entity.Organization{Name: "other", InviteCode: "other"})
+	suite.Require().NoError(err)
+	suite.otherId = int(id)
+	suite.other = tenant.WithOrganization(context.Background(), suite.otherId)
+
+	suite.manager = signUp(entity.User{Name: "ana", Username: "anaOrganizations", Password: "123456"}, ManagerRoleId, suite.otherId)
+	suite.technician = signUp(entity.User{Name: "bia", Username: "biaOrganizations", Password: "123456"}, TechnicianRoleId, suite.otherId)
+
+	// team names are unique per organization only
+	teamId, err := repo.CreateTeam(suite.other, entity.TeamRequest{Name: "repository", UserId: suite.manager})
+	suite.Require().NoError(err)
+	suite.teamId = int(teamId)
+
+	err = repo.AddTeamMember(suite.other, entity.TeamMemberRequest{TeamId: suite.teamId, UserId: suite.technician, ManagerId: suite.manager})
+	suite.Require().NoError(err)
+
+	taskId, err := repo.CreateTask(suite.other, entity.TaskRequest{Title: "other", Description: "other task", UserId: suite.technician})
+	suite.Require().NoError(err)
+	suite.taskId = int(taskId)
+}
+
+func (suite *OrganizationsTestSuite) TestCrossTenantReadsFail() {
+	// even with the ids of the users of the other organization
+	_, err := repo.GetTaskById(suite.ctx, suite.taskId, suite.technician, entity.ScopeOwn)
+	suite.Equal(ErrNoTaskInResult, err)
+
+	_, err = repo.GetTaskById(suite.ctx, suite.taskId, suite.manager, entity.ScopeTeam)
+	suite.Equal(ErrNoTaskInResult, err)
+
+	tasks, err := repo.GetTasks(suite.ctx, suite.manager, entity.ScopeTeam)
+	suite.NoError(err)
+	suite.Empty(tasks)
+
+	workLogs, err := repo.GetWorkLogs(suite.ctx, suite.taskId, suite.technician, entity.ScopeOwn)
+	suite.NoError(err)
+	suite.Empty(workLogs)
+
+	err = repo.ExportTasks(suite.ctx, suite.manager, entity.ScopeTeam, func(t entity.TaskExport) error {
+		suite.Failf("exported a task of another organization", "task %d", t.Id)
+		return nil
+	})
+	suite.NoError(err)
+
+	_, err = repo.GetUserById(suite.ctx, suite.technician)
+	suite.Equal(ErrUserNotExist, err)
+
+	ids, err := repo.GetUserIdsByUsernames(suite.ctx, suite.manager, []string{"biaOrganizations"})
+	suite.NoError(err)
+	suite.Empty(ids)
+
+	managers, err := repo.GetTeamManagersByUser(suite.ctx, suite.technician)
+	suite.NoError(err)
+	suite.Empty(managers)
+
+	teams, err := repo.GetTeamsByUser(suite.ctx, suite.manager)
+	suite.NoError(err)
+	suite.Empty(teams)
+
+	backlog, err := repo.GetOpenBacklogByTechnician(suite.ctx, suite.manager)
+	suite.NoError(err)
+	for _, b := range backlog {
+		suite.NotEqual(suite.technician, b.UserId)
+	}
+
+	now := time.Now().UTC()
+	perDay, err := repo.GetTasksPerDay(suite.other, suite.manager, now.AddDate(0, 0, -1), now.AddDate(0, 0, 1))
+	suite.NoError(err)
+	suite.Len(perDay, 1)
+	suite.Equal(1, perDay[0].Created, "should count only the task of the organization")
+
+	o, err := repo.GetOrganization(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(OrganizationId, o.Id)
+}
+
+func (suite *OrganizationsTestSuite) TestCrossTenantWritesFail() {
+	_, err := repo.CreateTask(suite.ctx, entity.TaskRequest{Title: "cross", Description: "cross tenant", UserId: suite.technician})
+	suite.Equal(ErrTaskWithoutUser, err)
+
+	_, err = repo.UpdateTaskById(suite.ctx, entity.TaskUpdateRequest{Id: suite.taskId, UserId: suite.technician, Title: "cross", Description: "cross tenant"})
+	suite.Equal(ErrNoTaskInResult, err)
+
+	_, err = repo.FinishTaskById(suite.ctx, suite.taskId, suite.technician)
+	suite.Equal(ErrNoTaskInResult, err)
+
+	err = repo.DeleteTaskById(suite.ctx, suite.taskId, suite.manager, entity.ScopeAny)
+	suite.Equal(ErrNoTaskInResult, err)
+
+	_, err = repo.StartTimer(suite.ctx, suite.taskId, suite.technician)
+	suite.Equal(ErrNoTaskInResult, err)
+
+	_, err = repo.CreateWorkLog(suite.ctx, entity.WorkLogRequest{
+		TaskId:    suite.taskId,
+		UserId:    suite.technician,
+		StartedAt: time.Date(2023, 9, 18, 8, 0, 0, 0, time.UTC),
+		EndedAt:   time.Date(2023, 9, 18, 9, 0, 0, 0, time.UTC),
+	})
+	suite.Equal(ErrNoTaskInResult, err)
+
+	bulk, err := repo.BulkUpdateTasks(suite.ctx, entity.BulkTaskRequest{
+		Action: entity.BulkActionDelete,
+		Mode:   entity.BulkModePerItem,
+		Ids:    []int{suite.taskId},
+		UserId: suite.manager,
+		Scope:  entity.ScopeAny,
+	})
+	suite.NoError(err)
+	suite.Equal(1, bulk.Failed)
+
+	// a user of another organization can't be added to a team
+	err = repo.AddTeamMember(suite.ctx, entity.TeamMemberRequest{TeamId: TeamId, UserId: suite.technician, ManagerId: ManagerUser.Id})
+	suite.Equal(ErrUserNotExist, err)
+
+	// nor the teams of another organization changed
+	err = repo.AddTeamMember(suite.ctx, entity.TeamMemberRequest{TeamId: suite.teamId, UserId: TechnicianUser.Id, ManagerId: suite.manager})
+	suite.Equal(ErrTeamNotFound, err)
+
+	err = repo.RemoveTeamMember(suite.ctx, entity.TeamMemberRequest{TeamId: suite.teamId, UserId: suite.technician, ManagerId: suite.manager})
+	suite.Equal(ErrTeamNotFound, err)
+
+	// the task is untouched
+	task, err := repo.GetTaskById(suite.other, suite.taskId, suite.technician, entity.ScopeOwn)
+	suite.NoError(err)
+	suite.Equal("other", task.Title)
+	suite.Empty(task.FinishedAt)
+	suite.Zero(task.DeletedBy.Id)
+}
+
+func (suite *OrganizationsTestSuite) TestQueriesWithoutOrganizationFail() {
+	ctx := context.Background()
+
+	_, err := repo.GetTasks(ctx, suite.manager, entity.ScopeTeam)
+	suite.Equal(ErrNoOrganization, err)
+
+	_, err = repo.CreateTask(ctx, entity.TaskRequest{Title: "none", Description: "no organization", UserId: suite.technician})
+	suite.Equal(ErrNoOrganization, err)
+
+	_, err = repo.GetTeamsByUser(ctx, suite.manager)
+	suite.Equal(ErrNoOrganization, err)
+
+	_, err = repo.GetOrganization(ctx)
+	suite.Equal(ErrNoOrganization, err)
+}
+
+func (suite *OrganizationsTestSuite) TestUpdateInviteCode() {
+	o, err := repo.GetOrganizationByInviteCode(context.Background(), "other")
+	suite.NoError(err)
+	suite.Equal(suite.otherId, o.Id)
+
+	err = repo.UpdateInviteCode(suite.other, "other rotated")
+	suite.NoError(err)
+
+	_, err = repo.GetOrganizationByInviteCode(context.Background(), "other")
+	suite.Equal(ErrOrganizationNotFound, err)
+
+	o, err = repo.GetOrganization(suite.other)
+	suite.NoError(err)
+	suite.Equal("other rotated", o.InviteCode)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import "context"
+
+// GetPermissionsByRole returns the names of the permissions of the role, roles
+// are shared by every organization.
+func (r *repository) GetPermissionsByRole(ctx context.Context, code int) ([]string, error) {
+	var permissions = []string{}
+
+	err := r.read(ctx, func() error {
+		permissions = permissions[:0]
+		return r.db.SelectContext(ctx, &permissions, r.sql.getPermissionsByRole, code)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
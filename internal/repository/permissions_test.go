@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type PermissionsTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestPermissionsTestSuite(t *testing.T) {
+	suite.Run(t, new(PermissionsTestSuite))
+}
+
+func (suite *PermissionsTestSuite) SetupSuite() {
+	suite.ctx = organizationContext()
+}
+
+func (suite *PermissionsTestSuite) TestGetPermissionsByRole() {
+	// the defaults keep the behaviour of the roles before permissions, managers
+	// only read the reports of their teams
+	manager, err := repo.GetPermissionsByRole(suite.ctx, entity.ManagerRole)
+	suite.NoError(err)
+	suite.Equal([]string{
+		"report:read:team",
+		"stats:read",
+		"task:delete:team",
+		"task:import",
+		"task:read:team",
+		"task:reassign:team",
+		"team:manage",
+		"user:manage",
+	}, manager)
+
+	technician, err := repo.GetPermissionsByRole(suite.ctx, entity.TechnicianRole)
+	suite.NoError(err)
+	suite.Equal([]string{
+		"report:read:own",
+		"task:create",
+		"task:read:own",
+		"task:update:own",
+		"worklog:create:own",
+	}, technician)
+
+	visitor, err := repo.GetPermissionsByRole(suite.ctx, entity.VisitorRole)
+	suite.NoError(err)
+	suite.Empty(visitor)
+}
+
+func (suite *PermissionsTestSuite) TestScopes() {
+	taskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{Title: "scopes", Description: "task of the technician", UserId: TechnicianUser.Id})
+	suite.Require().NoError(err)
+
+	// a manager outside of the teams of the technician
+	manager := SignUpManager(entity.User{Name: "rui", Username: "ruiPermissions", Password: "123456"})
+
+	_, err = repo.GetTaskById(suite.ctx, int(taskId), manager, entity.ScopeTeam)
+	suite.Equal(ErrNoTaskInResult, err)
+
+	_, err = repo.GetTaskById(suite.ctx, int(taskId), manager, entity.ScopeAny)
+	suite.NoError(err)
+
+	_, err = repo.GetTaskById(suite.ctx, int(taskId), TechnicianUser.Id, entity.ScopeNone)
+	suite.Equal(ErrNoTaskInResult, err)
+
+	err = repo.DeleteTaskById(suite.ctx, int(taskId), TechnicianUser.Id, entity.ScopeOwn)
+	suite.NoError(err)
+
+	response, err := repo.BulkUpdateTasks(suite.ctx, entity.BulkTaskRequest{
+		Action: entity.BulkActionRestore,
+		Mode:   entity.BulkModePerItem,
+		Ids:    []int{int(taskId)},
+		UserId: manager,
+		Scope:  entity.ScopeTeam,
+	})
+	suite.NoError(err)
+	suite.Equal(1, response.Failed)
+
+	response, err = repo.BulkUpdateTasks(suite.ctx, entity.BulkTaskRequest{
+		Action: entity.BulkActionRestore,
+		Mode:   entity.BulkModePerItem,
+		Ids:    []int{int(taskId)},
+		UserId: manager,
+		Scope:  entity.ScopeAny,
+	})
+	suite.NoError(err)
+	suite.Equal(1, response.Succeeded)
+}
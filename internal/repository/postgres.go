@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	errPostgresDuplicate            = "23505"
+	errPostgresForeignKey           = "23503"
+	errPostgresSerializationFailure = "40001"
+	errPostgresDeadlock             = "40P01"
+	errPostgresLockNotAvailable     = "55P03"
+)
+
+var postgresDialect = dialect{
+	name:         DriverPostgres,
+	driver:       "pgx",
+	system:       semconv.DBSystemPostgreSQL,
+	sql:          postgresQueries(),
+	returning:    true,
+	duplicate:    postgresDuplicate,
+	isForeignKey: isPostgresForeignKey,
+	isTransient:  isPostgresTransient,
+	isServerError: func(err error) bool {
+		var pgErr *pgconn.PgError
+		return errors.As(err, &pgErr)
+	},
+}
+
+// postgresDuplicate returns the column of a unique violation from the name of
+// the constraint, PostgreSQL names the constraint of UNIQUE columns
+// <table>_<column>_key.
+func postgresDuplicate(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != errPostgresDuplicate {
+		return "", false
+	}
+
+	name, found := strings.CutPrefix(pgErr.ConstraintName, pgErr.TableName+"_")
+	if !found {
+		return "", true
+	}
+
+	name, found = strings.CutSuffix(name, "_key")
+	if !found {
+		return "", true
+	}
+
+	return name, true
+}
+
+func isPostgresForeignKey(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == errPostgresForeignKey
+}
+
+func isPostgresTransient(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case errPostgresSerializationFailure, errPostgresDeadlock, errPostgresLockNotAvailable:
+			return true
+		}
+		return false
+	}
+
+	return errors.Is(err, driver.ErrBadConn)
+}
+
+// postgresQueries are the MySQL queries with the functions PostgreSQL doesn't
+// have replaced. Dates read as text keep the format MySQL returns, and the
+// parameters of INSERT ... SELECT are cast as PostgreSQL can't infer them.
+func postgresQueries() queries {
+	q := mysqlQueries
+
+	q.countMigrationsTables = `
+		SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = 'migrations'
+	`
+
+	q.getTasks = `
+		SELECT
+			t.id,
+			t.title,
+			t.description,
+			cby.id AS created_by_id,
+			cby.name AS created_by_name,
+			` + postgresText("t.created_at") + ` AS created_at,
+			COALESCE(dby.id, 0) AS deleted_by_id,
+			COALESCE(dby.name, '') AS deleted_by_name,
+			` + postgresText("t.deleted_at") + ` AS deleted_at,
+			` + postgresText("t.updated_at") + ` AS updated_at,
+			` + postgresText("t.finished_at") + ` AS finished_at,
+			` + postgresTimeSpent + ` AS time_spent
+		FROM tasks t
+		LEFT JOIN users cby ON cby.id = t.created_by_user_id
+		LEFT JOIN users dby ON dby.id = t.deleted_by_user_id
+		WHERE t.organization_id = ?
+	`
+
+	q.getWorkLogs = `
+		SELECT
+			wl.id,
+			wl.task_id,
+			wl.started_at,
+			` + postgresText("wl.ended_at") + ` AS ended_at,
+			` + postgresSeconds("wl.started_at", "COALESCE(wl.ended_at, NOW())") + ` AS duration,
+			wl.note,
+			lby.id AS logged_by_id,
+			lby.name AS logged_by_name,
+			wl.created_at
+		FROM work_logs wl
+		INNER JOIN tasks t ON t.id = wl.task_id
+		LEFT JOIN users lby ON lby.id = wl.user_id
+		WHERE t.organization_id = ?
+	`
+
+	q.createTask = `
+		INSERT INTO tasks (title, description, created_by_user_id, organization_id)
+		SELECT ?::varchar, ?::varchar, u.id, u.organization_id
+		FROM users u
+		WHERE u.organization_id = ? AND u.id = ?
+	`
+
+	q.addApiKeyPermission = `
+		INSERT INTO api_key_permissions (api_key_id, permission_id)
+		SELECT ?::integer, p.id
+		FROM permissions p
+		WHERE p.name = ? AND EXISTS (SELECT 1 FROM users_role_permissions rp WHERE rp.permission_id = p.id)
+	`
+
+	q.addApiKeyTeams = `
+		INSERT INTO team_members (team_id, user_id)
+		SELECT tm.team_id, ?::integer
+		FROM team_members tm
+		INNER JOIN teams t ON t.id = tm.team_id
+		WHERE t.organization_id = ? AND tm.user_id = ?
+	`
+
+	q.getApiKeys = `
+		SELECT
+			k.id,
+			k.name,
+			k.user_id,
+			` + postgresText("k.expires_at") + ` AS expires_at,
+			` + postgresText("k.last_used_at") + ` AS last_used_at,
+			` + postgresText("k.revoked_at") + ` AS revoked_at,
+			cby.id AS created_by_id,
+			cby.name AS created_by_name,
+			` + postgresText("k.created_at") + ` AS created_at
+		FROM api_keys k
+		INNER JOIN users cby ON cby.id = k.created_by_user_id
+		WHERE k.organization_id = ?
+	`
+
+	q.startTimer = `
+		INSERT INTO work_logs (task_id, user_id, started_at)
+		SELECT t.id, ?::integer, NOW()
+		FROM tasks t
+		WHERE t.organization_id = ? AND t.deleted_at IS NULL AND t.finished_at IS NULL AND t.created_by_user_id = ? AND t.id = ?
+	`
+
+	q.createWorkLog = `
+		INSERT INTO work_logs (task_id, user_id, started_at, ended_at, note)
+		SELECT t.id, ?::integer, ?::timestamptz, ?::timestamptz, ?::varchar
+		FROM tasks t
+		WHERE t.organization_id = ? AND t.deleted_at IS NULL AND t.created_by_user_id = ? AND t.id = ?
+			AND NOT EXISTS (SELECT 1 FROM work_logs wl WHERE wl.user_id = ? AND wl.started_at < ? AND COALESCE(wl.ended_at, NOW()) > ?)
+	`
+
+	q.getTasksPerDay = `
+		SELECT
+			d.day,
+			SUM(d.created) AS created,
+			SUM(d.finished) AS finished,
+			SUM(d.deleted) AS deleted
+		FROM (
+			SELECT ` + postgresDay("created_at") + ` AS day, 1 AS created, 0 AS finished, 0 AS deleted
+			FROM tasks WHERE organization_id = ? AND created_at >= ? AND created_at < ? AND created_by_user_id IN (` + teammates + `)
+			UNION ALL
+			SELECT ` + postgresDay("finished_at") + `, 0, 1, 0
+			FROM tasks WHERE organization_id = ? AND finished_at >= ? AND finished_at < ? AND created_by_user_id IN (` + teammates + `)
+			UNION ALL
+			SELECT ` + postgresDay("deleted_at") + `, 0, 0, 1
+			FROM tasks WHERE organization_id = ? AND deleted_at >= ? AND deleted_at < ? AND created_by_user_id IN (` + teammates + `)
+		) d
+		GROUP BY d.day
+		ORDER BY d.day
+	`
+
+	q.getMeanTimeToFinish = `
+		SELECT COALESCE(AVG(` + postgresSeconds("created_at", "finished_at") + `), 0)::float8
+		FROM tasks
+		WHERE organization_id = ? AND deleted_at IS NULL AND finished_at >= ? AND finished_at < ? AND created_by_user_id IN (` + teammates + `)
+	`
+
+	q.exportTasks = `
+		SELECT
+			t.id,
+			t.title,
+			t.description,
+			cby.id AS created_by_id,
+			cby.name AS created_by_name,
+			t.created_at,
+			t.updated_at,
+			COALESCE(fby.id, 0) AS finished_by_id,
+			COALESCE(fby.name, '') AS finished_by_name,
+			t.finished_at,
+			COALESCE(dby.id, 0) AS deleted_by_id,
+			COALESCE(dby.name, '') AS deleted_by_name,
+			t.deleted_at,
+			` + postgresTimeSpent + ` AS time_spent
+		FROM tasks t
+		LEFT JOIN users cby ON cby.id = t.created_by_user_id
+		LEFT JOIN users fby ON fby.id = t.finished_by_user_id
+		LEFT JOIN users dby ON dby.id = t.deleted_by_user_id
+		WHERE t.organization_id = ?
+	`
+
+	return q
+}
+
+// postgresText formats a nullable timestamp as MySQL casts it to text.
+func postgresText(column string) string {
+	return `COALESCE(to_char(` + column + ` AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS'), '')`
+}
+
+// postgresSeconds is TIMESTAMPDIFF(SECOND, from, to).
+func postgresSeconds(from, to string) string {
+	return `FLOOR(EXTRACT(EPOCH FROM (` + to + ` - ` + from + `)))::bigint`
+}
+
+// postgresDay is DATE_FORMAT(column, '%Y-%m-%d').
+func postgresDay(column string) string {
+	return `to_char(` + column + ` AT TIME ZONE 'UTC', 'YYYY-MM-DD')`
+}
+
+var postgresTimeSpent = `COALESCE((
+				SELECT SUM(` + postgresSeconds("wl.started_at", "wl.ended_at") + `)
+				FROM work_logs wl
+				WHERE wl.task_id = t.id AND wl.ended_at IS NOT NULL
+			), 0)::bigint`
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+// GetWorkLogEntriesByUser returns the work logs of a user that overlap the
+// period [from, to), including running timers.
+func (r *repository) GetWorkLogEntriesByUser(ctx context.Context, userId int, from, to time.Time) ([]entity.WorkLogEntry, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// running timers are read with a NULL end, SQLite can't scan NOW() into a time
+	var rows []struct {
+		entity.WorkLogEntry
+		EndedAt sql.NullTime `db:"ended_at"`
+	}
+
+	err = r.read(ctx, func() error {
+		rows = rows[:0]
+		return r.db.SelectContext(ctx, &rows, r.sql.getWorkLogEntriesByUser, organizationId, userId, to, from)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	var entries = make([]entity.WorkLogEntry, 0, len(rows))
+	for _, row := range rows {
+		row.WorkLogEntry.EndedAt = now
+		if row.EndedAt.Valid {
+			row.WorkLogEntry.EndedAt = row.EndedAt.Time
+		}
+		entries = append(entries, row.WorkLogEntry)
+	}
+
+	return entries, nil
+}
+
+// GetFinishedTasksByUser returns the tasks of a user finished in the period [from, to).
+func (r *repository) GetFinishedTasksByUser(ctx context.Context, userId int, from, to time.Time) ([]entity.FinishedTask, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks = []entity.FinishedTask{}
+
+	err = r.read(ctx, func() error {
+		tasks = tasks[:0]
+		return r.db.SelectContext(ctx, &tasks, r.sql.getFinishedTasksByUser, organizationId, userId, from, to)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
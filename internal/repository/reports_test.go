@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReportsTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestReportsTestSuite(t *testing.T) {
+	suite.Run(t, new(ReportsTestSuite))
+}
+
+func (suite *ReportsTestSuite) SetupSuite() {
+	suite.ctx = organizationContext()
+}
+
+func (suite *ReportsTestSuite) TearDownTest() {
+	_, err := DB.Exec(`DELETE FROM work_logs`)
+	suite.NoError(err)
+}
+
+func (suite *ReportsTestSuite) TestGetWorkLogEntriesByUser() {
+	taskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{
+		Title:       "test report",
+		Description: "test report for test",
+		UserId:      TechnicianUser.Id,
+	})
+	suite.NoError(err)
+
+	day := time.Date(2023, 9, 18, 0, 0, 0, 0, time.UTC)
+
+	_, err = repo.CreateWorkLog(suite.ctx, entity.WorkLogRequest{
+		TaskId:    int(taskId),
+		UserId:    TechnicianUser.Id,
+		StartedAt: day.Add(23 * time.Hour),
+		EndedAt:   day.Add(25 * time.Hour),
+	})
+	suite.NoError(err)
+
+	entries, err := repo.GetWorkLogEntriesByUser(suite.ctx, TechnicianUser.Id, day, day.AddDate(0, 0, 1))
+	suite.NoError(err)
+	suite.Len(entries, 1)
+	suite.Equal("test report", entries[0].TaskTitle)
+
+	entries, err = repo.GetWorkLogEntriesByUser(suite.ctx, TechnicianUser.Id, day.AddDate(0, 0, 2), day.AddDate(0, 0, 3))
+	suite.NoError(err)
+	suite.Len(entries, 0)
+
+	entries, err = repo.GetWorkLogEntriesByUser(suite.ctx, ManagerUser.Id, day, day.AddDate(0, 0, 1))
+	suite.NoError(err)
+	suite.Len(entries, 0)
+}
+
+func (suite *ReportsTestSuite) TestGetFinishedTasksByUser() {
+	taskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{
+		Title:       "test report finished",
+		Description: "test report finished for test",
+		UserId:      TechnicianUser.Id,
+	})
+	suite.NoError(err)
+
+	_, err = repo.FinishTaskById(suite.ctx, int(taskId), TechnicianUser.Id)
+	suite.NoError(err)
+
+	now := time.Now().UTC()
+
+	tasks, err := repo.GetFinishedTasksByUser(suite.ctx, TechnicianUser.Id, now.Add(-time.Hour), now.Add(time.Hour))
+	suite.NoError(err)
+
+	var found bool
+	for _, t := range tasks {
+		if t.Id == int(taskId) {
+			found = true
+		}
+	}
+	suite.True(found)
+}
@@ -1,31 +1,137 @@
 package repository
 
 import (
-	"log"
-	"os"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/XSAM/otelsql"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lucas-simao/api-tasks/internal/retry"
+	"github.com/lucas-simao/api-tasks/internal/tracing"
 )
 
 type repository struct {
-	db *sqlx.DB
+	*dialect
+	// conn is the pool, db is the pool or the transaction of WithTx
+	conn  *sqlx.DB
+	db    queryer
+	tx    *sqlx.Tx
+	depth int
 }
 
-func New() Repository {
-	dataSource, ok := os.LookupEnv("DATABASE_URL")
-	if !ok {
-		log.Panic("Error to get DATABASE_URL")
+// Config is the connection to the database. Zero pool values keep the
+// defaults of database/sql.
+type Config struct {
+	// Driver is mysql, postgres or sqlite, defaults to mysql.
+	Driver          string
+	DataSource      string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// ConnectTimeout bounds the wait for the database at startup, it's common
+	// for the api to start before the database in docker-compose.
+	ConnectTimeout time.Duration
+	Logger         *slog.Logger
+}
+
+// connectBackoff is the wait between pings at startup.
+var connectBackoff = retry.Backoff{Initial: 250 * time.Millisecond, Max: 5 * time.Second}
+
+func New(ctx context.Context, c Config) (Repository, error) {
+	d, err := dialectOf(c.Driver)
+	if err != nil {
+		return nil, err
 	}
 
-	newDb, err := sqlx.Connect("mysql", dataSource)
+	dataSource := c.DataSource
+	if d.dataSource != nil {
+		if dataSource, err = d.dataSource(dataSource); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := otelsql.Open(d.driver, dataSource, tracing.SQLOptions(d.system)...)
 	if err != nil {
-		log.Panic(err)
-		return &repository{}
+		return nil, fmt.Errorf("error to open database: %w", err)
+	}
+
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetMaxIdleConns(c.MaxIdleConns)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(c.ConnMaxIdleTime)
+
+	newDb := sqlx.NewDb(db, d.driver)
+	if err := waitForDB(ctx, newDb, d, c); err != nil {
+		newDb.Close()
+		return nil, fmt.Errorf("error to connect to database: %w", err)
+	}
+
+	r := &repository{
+		dialect: d,
+		conn:    newDb,
+		db:      rebound{newDb, d},
+	}
+
+	if d.migrate {
+		if err := r.migrate(ctx); err != nil {
+			newDb.Close()
+			return nil, fmt.Errorf("error to migrate database: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// waitForDB pings the database until it answers or the timeout ends.
+func waitForDB(ctx context.Context, db *sqlx.DB, d *dialect, c Config) error {
+	if c.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.ConnectTimeout)
+		defer cancel()
 	}
 
-	return &repository{
-		db: newDb,
+	logger := c.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+
+		// the server answered, e.g. a wrong password, waiting won't help
+		if d.isServerError(err) {
+			return err
+		}
+
+		delay := connectBackoff.Delay(attempt)
+		logger.WarnContext(ctx, "database unavailable, retrying",
+			slog.Int("attempt", attempt+1),
+			slog.Duration("retry_in", delay),
+			slog.Any("error", err),
+		)
+
+		if waitErr := retry.Wait(ctx, delay); waitErr != nil {
+			return err
+		}
 	}
 }
+
+func (r *repository) Stats() sql.DBStats {
+	return r.conn.Stats()
+}
+
+func (r *repository) Ping(ctx context.Context) error {
+	return r.conn.PingContext(ctx)
+}
+
+func (r *repository) Close() error {
+	return r.conn.Close()
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/retry"
+)
+
+// transient is the policy of the idempotent queries. Writes aren't retried,
+// the driver can fail after the statement was applied.
+var transient = retry.Policy{
+	Backoff:  retry.Backoff{Initial: 25 * time.Millisecond, Max: 500 * time.Millisecond},
+	Attempts: 3,
+}
+
+// read runs an idempotent query, retrying transient errors. Queries in a
+// transaction aren't retried, a deadlock rolls back the whole transaction.
+func (r *repository) read(ctx context.Context, fn func() error) error {
+	if r.tx != nil {
+		return fn()
+	}
+
+	policy := transient
+	policy.Retryable = r.isTransient
+
+	return retry.Do(ctx, policy, fn)
+}
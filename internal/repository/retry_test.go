@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTransient(t *testing.T) {
+	cases := map[string]struct {
+		dialect   dialect
+		err       error
+		transient bool
+	}{
+		"1 - Should retry deadlocks":                       {dialect: mysqlDialect, err: &mysql.MySQLError{Number: errMySQLDeadlock}, transient: true},
+		"2 - Should retry lock wait timeouts":              {dialect: mysqlDialect, err: &mysql.MySQLError{Number: errMySQLLockWaitTimeout}, transient: true},
+		"3 - Should retry bad connections":                 {dialect: mysqlDialect, err: fmt.Errorf("query: %w", driver.ErrBadConn), transient: true},
+		"4 - Should retry invalid connections":             {dialect: mysqlDialect, err: mysql.ErrInvalidConn, transient: true},
+		"5 - Should not retry duplicated keys":             {dialect: mysqlDialect, err: &mysql.MySQLError{Number: errMySQLDuplicate}},
+		"6 - Should not retry no rows":                     {dialect: mysqlDialect, err: sql.ErrNoRows},
+		"7 - Should not retry other errors":                {dialect: mysqlDialect, err: errors.New("syntax")},
+		"8 - Should retry postgres deadlocks":              {dialect: postgresDialect, err: &pgconn.PgError{Code: errPostgresDeadlock}, transient: true},
+		"9 - Should retry postgres serialization failures": {dialect: postgresDialect, err: &pgconn.PgError{Code: errPostgresSerializationFailure}, transient: true},
+		"10 - Should retry postgres bad connections":       {dialect: postgresDialect, err: driver.ErrBadConn, transient: true},
+		"11 - Should not retry postgres duplicated keys":   {dialect: postgresDialect, err: &pgconn.PgError{Code: errPostgresDuplicate}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.transient, tc.dialect.isTransient(tc.err))
+		})
+	}
+}
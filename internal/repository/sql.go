@@ -1,25 +1,303 @@
 package repository
 
-var (
+// queries are the statements of a database. The statements use ? placeholders
+// whatever the database, they are rebound to its own syntax when run. Queries of
+// tenant data take the organization of the request, see organization.
+type queries struct {
+	// migrations
+	getAppliedMigrations  string
+	countMigrationsTables string
+
+	// organizations
+	createOrganization           string
+	getOrganizationById          string
+	getOrganizationByInviteCode  string
+	updateOrganizationInviteCode string
+
 	// authentication
-	sqlSignUp            = `INSERT INTO users (name, username, password, user_role_id) VALUES(?, ?, ?, ?)`
-	sqlGetUserByUsername = `
+	signUp            string
+	getUserByUsername string
+	getUserRoleByCode string
+	getUserById       string
+
+	// identities
+	getUserByIdentity string
+	addUserIdentity   string
+	updateUserRole    string
+
+	// two-factor authentication
+	getUserMFA          string
+	deletePendingMFA    string
+	createMFA           string
+	enableMFA           string
+	useMFAStep          string
+	failMFA             string
+	resetFailedMFA      string
+	deleteMFA           string
+	addRecoveryCode     string
+	useRecoveryCode     string
+	deleteRecoveryCodes string
+
+	// permissions
+	getPermissionsByRole string
+
+	// api keys
+	createApiKey         string
+	addApiKeyPermission  string
+	addApiKeyTeams       string
+	getApiKeys           string
+	getApiKeyPermissions string
+	revokeApiKey         string
+	getApiKeyPrincipal   string
+	touchApiKey          string
+
+	// tasks
+	createTask     string
+	getTasks       string
+	deleteTaskById string
+	updateTaskById string
+	doneTaskById   string
+
+	// work logs
+	getWorkLogs               string
+	lockUser                  string
+	getRunningWorkLogIdByTask string
+	countOverlappingWorkLogs  string
+	startTimer                string
+	stopTimer                 string
+	createWorkLog             string
+
+	// reports
+	getWorkLogEntriesByUser string
+	getFinishedTasksByUser  string
+
+	// stats
+	getTasksPerDay              string
+	getMeanTimeToFinish         string
+	getOpenBacklogByTechnician  string
+	getTopTechniciansByFinished string
+
+	// export
+	exportTasks string
+
+	// import
+	getUsersByUsernames string
+	importTask          string
+
+	// bulk
+	restoreTaskById  string
+	reassignTaskById string
+
+	// teams
+	createTeam            string
+	getTeamsByUser        string
+	getTeamManagersByUser string
+	countTeamMember       string
+	countOtherTeams       string
+	addTeamMember         string
+	removeTeamMember      string
+}
+
+// teammates selects the users sharing a team with the user of the placeholder,
+// the user included. Managers only see and change the tasks of their teammates.
+const teammates = `
+	SELECT tt.user_id
+	FROM team_members mt
+	INNER JOIN team_members tt ON tt.team_id = mt.team_id
+	WHERE mt.user_id = ?
+`
+
+var mysqlQueries = queries{
+	// migrations
+	getAppliedMigrations: `SELECT name FROM migrations ORDER BY name`,
+	countMigrationsTables: `
+		SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = 'migrations'
+	`,
+
+	// organizations
+	createOrganization:           `INSERT INTO organizations (name, invite_code) VALUES(?, ?)`,
+	getOrganizationById:          `SELECT id, name, invite_code FROM organizations WHERE id = ?`,
+	getOrganizationByInviteCode:  `SELECT id, name, invite_code FROM organizations WHERE invite_code = ?`,
+	updateOrganizationInviteCode: `UPDATE organizations SET invite_code = ? WHERE id = ?`,
+
+	// authentication
+	signUp: `INSERT INTO users (name, username, password, user_role_id, organization_id) VALUES(?, ?, ?, ?, ?)`,
+	getUserByUsername: `
 		SELECT 
 			users.id,
 			users.name,
 			username,
 			password,
-			ur.code AS code_role
+			ur.code AS code_role,
+			users.organization_id
 		FROM users
 		LEFT JOIN users_role ur ON ur.id = users.user_role_id
-		WHERE username = ?`
-	sqlGetUserRoleByCode = `SELECT id, name, code FROM users_role WHERE code = ?`
+		WHERE username = ?`,
+	getUserRoleByCode: `SELECT id, name, code FROM users_role WHERE code = ?`,
+
+	// permissions
+	getPermissionsByRole: `
+		SELECT p.name
+		FROM permissions p
+		INNER JOIN users_role_permissions rp ON rp.permission_id = p.id
+		INNER JOIN users_role ur ON ur.id = rp.user_role_id
+		WHERE ur.code = ?
+		ORDER BY p.name
+	`,
+	// api keys
+	createApiKey: `INSERT INTO api_keys (organization_id, user_id, name, key_hash, created_by_user_id, expires_at) VALUES(?, ?, ?, ?, ?, ?)`,
+
+	// keys only get permissions given to a role
+	addApiKeyPermission: `
+		INSERT INTO api_key_permissions (api_key_id, permission_id)
+		SELECT ?, p.id
+		FROM permissions p
+		WHERE p.name = ? AND EXISTS (SELECT 1 FROM users_role_permissions rp WHERE rp.permission_id = p.id)
+	`,
+
+	// the service user joins the teams of the manager creating the key, so the
+	// tasks of the key are tasks of the teams
+	addApiKeyTeams: `
+		INSERT INTO team_members (team_id, user_id)
+		SELECT tm.team_id, ?
+		FROM team_members tm
+		INNER JOIN teams t ON t.id = tm.team_id
+		WHERE t.organization_id = ? AND tm.user_id = ?
+	`,
+
+	getApiKeys: `
+		SELECT
+			k.id,
+			k.name,
+			k.user_id,
+			k.expires_at,
+			COALESCE(k.last_used_at, "") AS last_used_at,
+			COALESCE(k.revoked_at, "") AS revoked_at,
+			cby.id AS created_by_id,
+			cby.name AS created_by_name,
+			k.created_at
+		FROM api_keys k
+		INNER JOIN users cby ON cby.id = k.created_by_user_id
+		WHERE k.organization_id = ?
+	`,
+
+	getApiKeyPermissions: `
+		SELECT kp.api_key_id, p.name
+		FROM api_key_permissions kp
+		INNER JOIN api_keys k ON k.id = kp.api_key_id
+		INNER JOIN permissions p ON p.id = kp.permission_id
+		WHERE k.organization_id = ?
+	`,
+
+	revokeApiKey: `UPDATE api_keys SET revoked_at = NOW() WHERE organization_id = ? AND revoked_at IS NULL AND id = ?`,
+
+	getApiKeyPrincipal: `
+		SELECT
+			k.id,
+			u.id,
+			u.name,
+			u.username,
+			ur.code,
+			u.organization_id
+		FROM api_keys k
+		INNER JOIN users u ON u.id = k.user_id
+		INNER JOIN users_role ur ON ur.id = u.user_role_id
+		WHERE k.key_hash = ? AND k.revoked_at IS NULL AND k.expires_at > ?
+	`,
+
+	// the last use is recorded once a minute at most
+	touchApiKey: `UPDATE api_keys SET last_used_at = ? WHERE id = ? AND (last_used_at IS NULL OR last_used_at < ?)`,
+
+	getUserById: `
+		SELECT
+			users.id,
+			users.name,
+			username,
+			password,
+			COALESCE(ur.code, 0) AS code_role,
+			users.organization_id
+		FROM users
+		LEFT JOIN users_role ur ON ur.id = users.user_role_id
+		WHERE users.organization_id = ? AND users.id = ?`,
+
+	// identities
+	getUserByIdentity: `
+		SELECT
+			users.id,
+			users.name,
+			username,
+			password,
+			COALESCE(ur.code, 0) AS code_role,
+			users.organization_id
+		FROM user_identities ui
+		INNER JOIN users ON users.id = ui.user_id
+		LEFT JOIN users_role ur ON ur.id = users.user_role_id
+		WHERE ui.issuer = ? AND ui.subject = ?`,
+	addUserIdentity: `INSERT INTO user_identities (issuer, subject, user_id) VALUES(?, ?, ?)`,
+	updateUserRole: `
+		UPDATE users
+		SET user_role_id = (SELECT id FROM users_role WHERE code = ?)
+		WHERE organization_id = ? AND id = ?`,
+
+	// two-factor authentication, the users are checked to be of the
+	// organization as user_mfa has none
+	getUserMFA: `
+		SELECT
+			m.user_id,
+			m.secret,
+			CASE WHEN m.enabled_at IS NULL THEN 0 ELSE 1 END AS enabled,
+			m.last_step,
+			CASE WHEN m.failed_at > ? THEN m.failed_attempts ELSE 0 END AS failed_attempts
+		FROM user_mfa m
+		INNER JOIN users u ON u.id = m.user_id
+		WHERE u.organization_id = ? AND m.user_id = ?`,
+	deletePendingMFA: `
+		DELETE FROM user_mfa
+		WHERE enabled_at IS NULL AND user_id IN (SELECT id FROM users WHERE organization_id = ? AND id = ?)`,
+	createMFA: `
+		INSERT INTO user_mfa (user_id, secret)
+		SELECT u.id, ?
+		FROM users u
+		WHERE u.organization_id = ? AND u.id = ?`,
+	enableMFA: `
+		UPDATE user_mfa
+		SET enabled_at = ?, last_step = ?, failed_attempts = 0
+		WHERE enabled_at IS NULL AND user_id IN (SELECT id FROM users WHERE organization_id = ? AND id = ?)`,
+
+	// a code is used once, codes of older steps were seen by someone
+	useMFAStep: `
+		UPDATE user_mfa
+		SET last_step = ?, failed_attempts = 0
+		WHERE enabled_at IS NOT NULL AND last_step < ? AND user_id IN (SELECT id FROM users WHERE organization_id = ? AND id = ?)`,
+	// failures older than the window start again from one
+	failMFA: `
+		UPDATE user_mfa
+		SET failed_attempts = CASE WHEN failed_at > ? THEN failed_attempts + 1 ELSE 1 END, failed_at = ?
+		WHERE user_id IN (SELECT id FROM users WHERE organization_id = ? AND id = ?)`,
+	resetFailedMFA: `
+		UPDATE user_mfa
+		SET failed_attempts = 0
+		WHERE user_id IN (SELECT id FROM users WHERE organization_id = ? AND id = ?)`,
+	deleteMFA: `
+		DELETE FROM user_mfa
+		WHERE user_id IN (SELECT id FROM users WHERE organization_id = ? AND id = ?)`,
+	addRecoveryCode: `INSERT INTO user_recovery_codes (user_id, code_hash) VALUES(?, ?)`,
+	useRecoveryCode: `
+		UPDATE user_recovery_codes
+		SET used_at = ?
+		WHERE used_at IS NULL AND code_hash = ? AND user_id IN (SELECT id FROM users WHERE organization_id = ? AND id = ?)`,
+	deleteRecoveryCodes: `
+		DELETE FROM user_recovery_codes
+		WHERE user_id IN (SELECT id FROM users WHERE organization_id = ? AND id = ?)`,
 
 	// tasks
-	sqlCreateTask = `
-		INSERT INTO tasks (title, description, created_by_user_id) VALUES(?, ?, ?)
-	`
-	sqlGetTasks = `
+	createTask: `
+		INSERT INTO tasks (title, description, created_by_user_id, organization_id)
+		SELECT ?, ?, u.id, u.organization_id
+		FROM users u
+		WHERE u.organization_id = ? AND u.id = ?
+	`,
+	getTasks: `
 		SELECT
 			t.id,
 			t.title,
@@ -31,33 +309,280 @@ var (
 			COALESCE(dby.name, '') AS deleted_by_name,
 			COALESCE(t.deleted_at, "") AS deleted_at,
 			COALESCE(t.updated_at, "") AS updated_at,
-			COALESCE(t.finished_at, "") AS finished_at
+			COALESCE(t.finished_at, "") AS finished_at,
+			COALESCE((
+				SELECT SUM(TIMESTAMPDIFF(SECOND, wl.started_at, wl.ended_at))
+				FROM work_logs wl
+				WHERE wl.task_id = t.id AND wl.ended_at IS NOT NULL
+			), 0) AS time_spent
 		FROM tasks t
 		LEFT JOIN users cby ON cby.id = t.created_by_user_id
 		LEFT JOIN users dby ON dby.id = t.deleted_by_user_id
-		WHERE true
-	`
+		WHERE t.organization_id = ?
+	`,
 
-	sqlDeleteTaskById = `
+	deleteTaskById: `
 		UPDATE tasks 
 		SET 
 			deleted_by_user_id = ?,
 			deleted_at = now()
-		WHERE deleted_at IS NULL AND id = ?
-	`
+		WHERE organization_id = ? AND deleted_at IS NULL AND id = ?
+	`,
 
-	sqlUpdateTaskById = `
+	updateTaskById: `
 		UPDATE tasks 
 		SET 
 			title = ?,
 			description = ?
-		WHERE deleted_at IS NULL AND finished_at IS NULL AND created_by_user_id = ? AND id = ?
-	`
+		WHERE organization_id = ? AND deleted_at IS NULL AND finished_at IS NULL AND created_by_user_id = ? AND id = ?
+	`,
 
-	sqlDoneTaskById = `
+	doneTaskById: `
 		UPDATE tasks 
 		SET 
-			finished_at = now()
-		WHERE deleted_at IS NULL AND finished_at IS NULL AND created_by_user_id = ? AND id = ?
-	`
-)
+			finished_at = now(),
+			finished_by_user_id = created_by_user_id
+		WHERE organization_id = ? AND deleted_at IS NULL AND finished_at IS NULL AND created_by_user_id = ? AND id = ?
+	`,
+
+	// work logs
+	getWorkLogs: `
+		SELECT
+			wl.id,
+			wl.task_id,
+			wl.started_at,
+			COALESCE(wl.ended_at, "") AS ended_at,
+			TIMESTAMPDIFF(SECOND, wl.started_at, COALESCE(wl.ended_at, NOW())) AS duration,
+			wl.note,
+			lby.id AS logged_by_id,
+			lby.name AS logged_by_name,
+			wl.created_at
+		FROM work_logs wl
+		INNER JOIN tasks t ON t.id = wl.task_id
+		LEFT JOIN users lby ON lby.id = wl.user_id
+		WHERE t.organization_id = ?
+	`,
+
+	lockUser: `
+		SELECT id FROM users WHERE id = ? FOR UPDATE
+	`,
+
+	getRunningWorkLogIdByTask: `
+		SELECT id FROM work_logs WHERE user_id = ? AND task_id = ? AND ended_at IS NULL LIMIT 1
+	`,
+
+	countOverlappingWorkLogs: `
+		SELECT COUNT(*)
+		FROM work_logs
+		WHERE user_id = ? AND started_at < ? AND COALESCE(ended_at, NOW()) > ?
+	`,
+
+	startTimer: `
+		INSERT INTO work_logs (task_id, user_id, started_at)
+		SELECT t.id, ?, NOW()
+		FROM tasks t
+		WHERE t.organization_id = ? AND t.deleted_at IS NULL AND t.finished_at IS NULL AND t.created_by_user_id = ? AND t.id = ?
+	`,
+
+	stopTimer: `
+		UPDATE work_logs
+		SET
+			ended_at = NOW(),
+			note = ?
+		WHERE ended_at IS NULL AND id = ?
+	`,
+
+	createWorkLog: `
+		INSERT INTO work_logs (task_id, user_id, started_at, ended_at, note)
+		SELECT t.id, ?, ?, ?, ?
+		FROM tasks t
+		WHERE t.organization_id = ? AND t.deleted_at IS NULL AND t.created_by_user_id = ? AND t.id = ?
+			AND NOT EXISTS (SELECT 1 FROM work_logs wl WHERE wl.user_id = ? AND wl.started_at < ? AND COALESCE(wl.ended_at, NOW()) > ?)
+	`,
+
+	// reports
+	getWorkLogEntriesByUser: `
+		SELECT
+			wl.id,
+			wl.task_id,
+			t.title AS task_title,
+			wl.started_at,
+			wl.ended_at,
+			wl.note
+		FROM work_logs wl
+		INNER JOIN tasks t ON t.id = wl.task_id
+		WHERE t.organization_id = ? AND t.deleted_at IS NULL AND wl.user_id = ? AND wl.started_at < ? AND COALESCE(wl.ended_at, NOW()) > ?
+		ORDER BY wl.started_at
+	`,
+
+	getFinishedTasksByUser: `
+		SELECT id, title, finished_at
+		FROM tasks
+		WHERE organization_id = ? AND deleted_at IS NULL AND created_by_user_id = ? AND finished_at >= ? AND finished_at < ?
+		ORDER BY finished_at
+	`,
+
+	// stats, of the teammates of the user
+	getTasksPerDay: `
+		SELECT
+			d.day,
+			SUM(d.created) AS created,
+			SUM(d.finished) AS finished,
+			SUM(d.deleted) AS deleted
+		FROM (
+			SELECT DATE_FORMAT(created_at, '%Y-%m-%d') AS day, 1 AS created, 0 AS finished, 0 AS deleted
+			FROM tasks WHERE organization_id = ? AND created_at >= ? AND created_at < ? AND created_by_user_id IN (` + teammates + `)
+			UNION ALL
+			SELECT DATE_FORMAT(finished_at, '%Y-%m-%d'), 0, 1, 0
+			FROM tasks WHERE organization_id = ? AND finished_at >= ? AND finished_at < ? AND created_by_user_id IN (` + teammates + `)
+			UNION ALL
+			SELECT DATE_FORMAT(deleted_at, '%Y-%m-%d'), 0, 0, 1
+			FROM tasks WHERE organization_id = ? AND deleted_at >= ? AND deleted_at < ? AND created_by_user_id IN (` + teammates + `)
+		) d
+		GROUP BY d.day
+		ORDER BY d.day
+	`,
+
+	getMeanTimeToFinish: `
+		SELECT COALESCE(AVG(TIMESTAMPDIFF(SECOND, created_at, finished_at)), 0)
+		FROM tasks
+		WHERE organization_id = ? AND deleted_at IS NULL AND finished_at >= ? AND finished_at < ? AND created_by_user_id IN (` + teammates + `)
+	`,
+
+	getOpenBacklogByTechnician: `
+		SELECT
+			u.id AS user_id,
+			u.name,
+			COUNT(t.id) AS count
+		FROM users u
+		INNER JOIN users_role ur ON ur.id = u.user_role_id
+		LEFT JOIN tasks t ON t.created_by_user_id = u.id AND t.deleted_at IS NULL AND t.finished_at IS NULL
+		WHERE u.organization_id = ? AND ur.code = ? AND u.id IN (` + teammates + `)
+		GROUP BY u.id, u.name
+		ORDER BY count DESC, u.id
+	`,
+
+	getTopTechniciansByFinished: `
+		SELECT
+			u.id AS user_id,
+			u.name,
+			COUNT(t.id) AS count
+		FROM tasks t
+		INNER JOIN users u ON u.id = t.created_by_user_id
+		WHERE t.organization_id = ? AND t.deleted_at IS NULL AND t.finished_at >= ? AND t.finished_at < ? AND t.created_by_user_id IN (` + teammates + `)
+		GROUP BY u.id, u.name
+		ORDER BY count DESC, u.id
+		LIMIT ?
+	`,
+
+	// export
+	exportTasks: `
+		SELECT
+			t.id,
+			t.title,
+			t.description,
+			cby.id AS created_by_id,
+			cby.name AS created_by_name,
+			t.created_at,
+			t.updated_at,
+			COALESCE(fby.id, 0) AS finished_by_id,
+			COALESCE(fby.name, '') AS finished_by_name,
+			t.finished_at,
+			COALESCE(dby.id, 0) AS deleted_by_id,
+			COALESCE(dby.name, '') AS deleted_by_name,
+			t.deleted_at,
+			COALESCE((
+				SELECT SUM(TIMESTAMPDIFF(SECOND, wl.started_at, wl.ended_at))
+				FROM work_logs wl
+				WHERE wl.task_id = t.id AND wl.ended_at IS NOT NULL
+			), 0) AS time_spent
+		FROM tasks t
+		LEFT JOIN users cby ON cby.id = t.created_by_user_id
+		LEFT JOIN users fby ON fby.id = t.finished_by_user_id
+		LEFT JOIN users dby ON dby.id = t.deleted_by_user_id
+		WHERE t.organization_id = ?
+	`,
+
+	// import
+	getUsersByUsernames: `SELECT id, username FROM users WHERE organization_id = ? AND username IN (?) AND id IN (` + teammates + `)`,
+
+	importTask: `
+		INSERT INTO tasks (organization_id, title, description, created_by_user_id, finished_by_user_id, created_at, updated_at, finished_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+
+	// bulk
+	restoreTaskById: `
+		UPDATE tasks
+		SET
+			deleted_by_user_id = NULL,
+			deleted_at = NULL
+		WHERE organization_id = ? AND deleted_at IS NOT NULL AND id = ?
+	`,
+
+	reassignTaskById: `
+		UPDATE tasks
+		SET
+			created_by_user_id = ?
+		WHERE organization_id = ? AND deleted_at IS NULL AND finished_at IS NULL AND id = ? AND created_by_user_id IN (` + teammates + `)
+	`,
+
+	// teams
+	createTeam: `INSERT INTO teams (organization_id, name) VALUES(?, ?)`,
+
+	getTeamsByUser: `
+		SELECT
+			t.id,
+			t.name,
+			u.id AS member_id,
+			u.name AS member_name,
+			u.username AS member_username,
+			COALESCE(ur.code, 0) AS member_code_role
+		FROM teams t
+		INNER JOIN team_members mt ON mt.team_id = t.id
+		INNER JOIN team_members tm ON tm.team_id = t.id
+		INNER JOIN users u ON u.id = tm.user_id
+		LEFT JOIN users_role ur ON ur.id = u.user_role_id
+		WHERE t.organization_id = ? AND mt.user_id = ?
+		ORDER BY t.name, t.id, u.name, u.id
+	`,
+
+	getTeamManagersByUser: `
+		SELECT
+			u.id,
+			u.name,
+			u.username,
+			ur.code AS code_role
+		FROM users u
+		INNER JOIN users_role ur ON ur.id = u.user_role_id
+		WHERE u.organization_id = ? AND ur.code = ? AND u.id IN (` + teammates + `)
+		ORDER BY u.id
+	`,
+
+	countTeamMember: `
+		SELECT COUNT(*)
+		FROM team_members tm
+		INNER JOIN teams t ON t.id = tm.team_id
+		WHERE t.organization_id = ? AND tm.team_id = ? AND tm.user_id = ?
+	`,
+
+	// teams of the user the manager isn't a member of
+	countOtherTeams: `
+		SELECT COUNT(*)
+		FROM team_members tm
+		INNER JOIN teams t ON t.id = tm.team_id
+		WHERE t.organization_id = ? AND tm.user_id = ? AND tm.team_id NOT IN (
+			SELECT team_id FROM team_members WHERE user_id = ?
+		)
+	`,
+
+	// the user must be of the organization of the team
+	addTeamMember: `
+		INSERT INTO team_members (team_id, user_id)
+		SELECT t.id, u.id
+		FROM teams t
+		INNER JOIN users u ON u.organization_id = t.organization_id
+		WHERE t.id = ? AND u.id = ?
+	`,
+	removeTeamMember: `DELETE FROM team_members WHERE team_id = ? AND user_id = ?`,
+}
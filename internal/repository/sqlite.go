@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"modernc.org/sqlite"
+)
+
+// https://www.sqlite.org/rescode.html, extended codes keep the primary code in
+// the lower byte
+const (
+	errSQLiteBusy       = 5
+	errSQLiteLocked     = 6
+	errSQLiteForeignKey = 787
+	errSQLitePrimaryKey = 1555
+	errSQLiteUnique     = 2067
+)
+
+// sqlitePragmas run on every connection. Foreign keys are off by default in
+// SQLite, busy_timeout waits for the writer instead of failing right away.
+var sqlitePragmas = []string{
+	"journal_mode(WAL)",
+	"synchronous(NORMAL)",
+	"foreign_keys(1)",
+	"busy_timeout(5000)",
+}
+
+var sqliteDialect = dialect{
+	name:         DriverSQLite,
+	driver:       "sqlite",
+	system:       semconv.DBSystemSqlite,
+	sql:          sqliteQueries(),
+	duplicate:    sqliteDuplicate,
+	isForeignKey: isSQLiteForeignKey,
+	isTransient:  isSQLiteTransient,
+	isServerError: func(err error) bool {
+		var sqliteErr *sqlite.Error
+		return errors.As(err, &sqliteErr)
+	},
+	timeFormat: "2006-01-02 15:04:05.999999999",
+	dataSource: sqliteDataSource,
+	migrate:    true,
+}
+
+// sqliteDuplicate returns the column of a unique violation, SQLite only sends
+// it in the message: UNIQUE constraint failed: users.username.
+func sqliteDuplicate(err error) (string, bool) {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) || (sqliteErr.Code() != errSQLiteUnique && sqliteErr.Code() != errSQLitePrimaryKey) {
+		return "", false
+	}
+
+	_, columns, found := strings.Cut(sqliteErr.Error(), "UNIQUE constraint failed: ")
+	columns, _, _ = strings.Cut(columns, " (")
+	// indexes of several columns have no single field
+	if !found || strings.Contains(columns, ",") {
+		return "", true
+	}
+
+	return column(columns), true
+}
+
+func isSQLiteForeignKey(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == errSQLiteForeignKey
+}
+
+func isSQLiteTransient(err error) bool {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code() & 0xff
+		return code == errSQLiteBusy || code == errSQLiteLocked
+	}
+
+	return false
+}
+
+// sqliteDataSource adds the pragmas to the data source, pragmas already in it
+// run after and win. Transactions take the write lock when they begin, a
+// transaction upgrading from read to write fails when another one writes.
+func sqliteDataSource(dataSource string) (string, error) {
+	path, query, _ := strings.Cut(dataSource, "?")
+	if path == "" {
+		return "", errors.New("the sqlite data source needs the path of the database")
+	}
+
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return "", fmt.Errorf("invalid sqlite data source: %w", err)
+	}
+
+	params["_pragma"] = append(append([]string{}, sqlitePragmas...), params["_pragma"]...)
+	if !params.Has("_txlock") {
+		params.Set("_txlock", "immediate")
+	}
+
+	return path + "?" + params.Encode(), nil
+}
+
+// Backup writes a snapshot of the SQLite database of c to path, consistent even
+// while the api writes to the database. path must not exist.
+func Backup(ctx context.Context, c Config, path string) error {
+	if c.Driver != DriverSQLite {
+		return fmt.Errorf("backup needs the %s driver, back up %q with the tools of the database", DriverSQLite, c.Driver)
+	}
+
+	repo, err := New(ctx, c)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	if _, err := repo.(*repository).conn.ExecContext(ctx, `VACUUM INTO ?`, path); err != nil {
+		return fmt.Errorf("error to back up database: %w", err)
+	}
+
+	return nil
+}
+
+// sqliteNow replaces NOW(), CURRENT_TIMESTAMP has the format times are stored in.
+var sqliteNow = strings.NewReplacer("NOW()", "CURRENT_TIMESTAMP", "now()", "CURRENT_TIMESTAMP")
+
+// sqliteQueries are the MySQL queries with the functions SQLite doesn't have
+// replaced. Dates read as text keep the format MySQL returns.
+func sqliteQueries() queries {
+	q := mysqlQueries
+
+	q.countMigrationsTables = `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'migrations'`
+
+	for _, query := range []*string{
+		&q.deleteTaskById,
+		&q.doneTaskById,
+		&q.countOverlappingWorkLogs,
+		&q.startTimer,
+		&q.stopTimer,
+		&q.createWorkLog,
+		&q.getWorkLogEntriesByUser,
+		&q.revokeApiKey,
+	} {
+		*query = sqliteNow.Replace(*query)
+	}
+
+	// transactions take the write lock when they begin, see sqliteDataSource
+	q.lockUser = `SELECT id FROM users WHERE id = ?`
+
+	q.getApiKeys = `
+		SELECT
+			k.id,
+			k.name,
+			k.user_id,
+			` + sqliteText("k.expires_at") + ` AS expires_at,
+			` + sqliteText("k.last_used_at") + ` AS last_used_at,
+			` + sqliteText("k.revoked_at") + ` AS revoked_at,
+			cby.id AS created_by_id,
+			cby.name AS created_by_name,
+			` + sqliteText("k.created_at") + ` AS created_at
+		FROM api_keys k
+		INNER JOIN users cby ON cby.id = k.created_by_user_id
+		WHERE k.organization_id = ?
+	`
+
+	q.getTasks = `
+		SELECT
+			t.id,
+			t.title,
+			t.description,
+			cby.id AS created_by_id,
+			cby.name AS created_by_name,
+			` + sqliteText("t.created_at") + ` AS created_at,
+			COALESCE(dby.id, 0) AS deleted_by_id,
+			COALESCE(dby.name, '') AS deleted_by_name,
+			` + sqliteText("t.deleted_at") + ` AS deleted_at,
+			` + sqliteText("t.updated_at") + ` AS updated_at,
+			` + sqliteText("t.finished_at") + ` AS finished_at,
+			` + sqliteTimeSpent + ` AS time_spent
+		FROM tasks t
+		LEFT JOIN users cby ON cby.id = t.created_by_user_id
+		LEFT JOIN users dby ON dby.id = t.deleted_by_user_id
+		WHERE t.organization_id = ?
+	`
+
+	q.getWorkLogs = `
+		SELECT
+			wl.id,
+			wl.task_id,
+			wl.started_at,
+			` + sqliteText("wl.ended_at") + ` AS ended_at,
+			` + sqliteSeconds("wl.started_at", "COALESCE(wl.ended_at, CURRENT_TIMESTAMP)") + ` AS duration,
+			wl.note,
+			lby.id AS logged_by_id,
+			lby.name AS logged_by_name,
+			wl.created_at
+		FROM work_logs wl
+		INNER JOIN tasks t ON t.id = wl.task_id
+		LEFT JOIN users lby ON lby.id = wl.user_id
+		WHERE t.organization_id = ?
+	`
+
+	q.getTasksPerDay = `
+		SELECT
+			d.day,
+			SUM(d.created) AS created,
+			SUM(d.finished) AS finished,
+			SUM(d.deleted) AS deleted
+		FROM (
+			SELECT ` + sqliteDay("created_at") + ` AS day, 1 AS created, 0 AS finished, 0 AS deleted
+			FROM tasks WHERE organization_id = ? AND created_at >= ? AND created_at < ? AND created_by_user_id IN (` + teammates + `)
+			UNION ALL
+			SELECT ` + sqliteDay("finished_at") + `, 0, 1, 0
+			FROM tasks WHERE organization_id = ? AND finished_at >= ? AND finished_at < ? AND created_by_user_id IN (` + teammates + `)
+			UNION ALL
+			SELECT ` + sqliteDay("deleted_at") + `, 0, 0, 1
+			FROM tasks WHERE organization_id = ? AND deleted_at >= ? AND deleted_at < ? AND created_by_user_id IN (` + teammates + `)
+		) d
+		GROUP BY d.day
+		ORDER BY d.day
+	`
+
+	q.getMeanTimeToFinish = `
+		SELECT COALESCE(AVG(` + sqliteSeconds("created_at", "finished_at") + `), 0.0)
+		FROM tasks
+		WHERE organization_id = ? AND deleted_at IS NULL AND finished_at >= ? AND finished_at < ? AND created_by_user_id IN (` + teammates + `)
+	`
+
+	q.exportTasks = `
+		SELECT
+			t.id,
+			t.title,
+			t.description,
+			cby.id AS created_by_id,
+			cby.name AS created_by_name,
+			t.created_at,
+			t.updated_at,
+			COALESCE(fby.id, 0) AS finished_by_id,
+			COALESCE(fby.name, '') AS finished_by_name,
+			t.finished_at,
+			COALESCE(dby.id, 0) AS deleted_by_id,
+			COALESCE(dby.name, '') AS deleted_by_name,
+			t.deleted_at,
+			` + sqliteTimeSpent + ` AS time_spent
+		FROM tasks t
+		LEFT JOIN users cby ON cby.id = t.created_by_user_id
+		LEFT JOIN users fby ON fby.id = t.finished_by_user_id
+		LEFT JOIN users dby ON dby.id = t.deleted_by_user_id
+		WHERE t.organization_id = ?
+	`
+
+	return q
+}
+
+// sqliteText formats a nullable time as MySQL casts it to text.
+func sqliteText(column string) string {
+	return `COALESCE(strftime('%Y-%m-%d %H:%M:%S', ` + column + `), '')`
+}
+
+// sqliteSeconds is TIMESTAMPDIFF(SECOND, from, to).
+func sqliteSeconds(from, to string) string {
+	return `(unixepoch(` + to + `) - unixepoch(` + from + `))`
+}
+
+// sqliteDay is DATE_FORMAT(column, '%Y-%m-%d').
+func sqliteDay(column string) string {
+	return `strftime('%Y-%m-%d', ` + column + `)`
+}
+
+var sqliteTimeSpent = `COALESCE((
+				SELECT SUM(` + sqliteSeconds("wl.started_at", "wl.ended_at") + `)
+				FROM work_logs wl
+				WHERE wl.task_id = t.id AND wl.ended_at IS NOT NULL
+			), 0)`
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/scripts/migrations"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteBackup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	config := Config{Driver: DriverSQLite, DataSource: filepath.Join(dir, "api.db")}
+
+	r, err := New(ctx, config)
+	require.NoError(t, err)
+	defer r.Close()
+
+	var journalMode string
+	require.NoError(t, r.(*repository).conn.Get(&journalMode, `PRAGMA journal_mode`))
+	require.Equal(t, "wal", journalMode)
+
+	organizationId, err := r.CreateOrganization(ctx, entity.Organization{Name: "backup", InviteCode: "backup"})
+	require.NoError(t, err)
+
+	err = r.SignUp(ctx, entity.SignUpRequest{Name: "backup", Username: "backupUser", Password: "123456", OrganizationId: int(organizationId)})
+	require.NoError(t, err)
+
+	backupPath := filepath.Join(dir, "backup.db")
+	require.NoError(t, Backup(ctx, config, backupPath))
+	require.Error(t, Backup(ctx, config, backupPath), "should not overwrite a backup")
+
+	// opening the backup applies no migration twice
+	backup, err := New(ctx, Config{Driver: DriverSQLite, DataSource: backupPath})
+	require.NoError(t, err)
+	defer backup.Close()
+
+	up, err := migrations.Up(DriverSQLite)
+	require.NoError(t, err)
+
+	applied, err := backup.GetAppliedMigrations(ctx)
+	require.NoError(t, err)
+	require.Equal(t, up, applied)
+
+	user, err := backup.SignIn(ctx, "backupUser")
+	require.NoError(t, err)
+	require.Equal(t, "backup", user.Name)
+}
+
+func TestBackupNeedsSQLite(t *testing.T) {
+	err := Backup(context.Background(), Config{Driver: DriverMySQL, DataSource: "root:secret@tcp(localhost:3306)/api"}, filepath.Join(t.TempDir(), "backup.db"))
+	require.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+// GetTasksPerDay counts the tasks of the teammates of the user created,
+// finished and deleted on each day of the period [from, to). Days without any
+// event are omitted.
+func (r *repository) GetTasksPerDay(ctx context.Context, userId int, from, to time.Time) ([]entity.TasksPerDay, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var days = []entity.TasksPerDay{}
+
+	err = r.read(ctx, func() error {
+		days = days[:0]
+		return r.db.SelectContext(ctx, &days, r.sql.getTasksPerDay,
+			organizationId, from, to, userId,
+			organizationId, from, to, userId,
+			organizationId, from, to, userId,
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return days, nil
+}
+
+// GetMeanTimeToFinish returns the mean time in seconds between creation and
+// finish of the tasks of the teammates of the user finished in the period
+// [from, to).
+func (r *repository) GetMeanTimeToFinish(ctx context.Context, userId int, from, to time.Time) (float64, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var mean float64
+
+	err = r.read(ctx, func() error {
+		return r.db.GetContext(ctx, &mean, r.sql.getMeanTimeToFinish, organizationId, from, to, userId)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return mean, nil
+}
+
+// GetOpenBacklogByTechnician counts the open tasks of every technician of the
+// teams of the user.
+func (r *repository) GetOpenBacklogByTechnician(ctx context.Context, userId int) ([]entity.TechnicianTaskCount, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var backlog = []entity.TechnicianTaskCount{}
+
+	err = r.read(ctx, func() error {
+		backlog = backlog[:0]
+		return r.db.SelectContext(ctx, &backlog, r.sql.getOpenBacklogByTechnician, organizationId, entity.TechnicianRole, userId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return backlog, nil
+}
+
+// GetTopTechniciansByFinished returns the technicians of the teams of the user
+// with most tasks finished in the period [from, to).
+func (r *repository) GetTopTechniciansByFinished(ctx context.Context, userId int, from, to time.Time, limit int) ([]entity.TechnicianTaskCount, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var top = []entity.TechnicianTaskCount{}
+
+	err = r.read(ctx, func() error {
+		top = top[:0]
+		return r.db.SelectContext(ctx, &top, r.sql.getTopTechniciansByFinished, organizationId, from, to, userId, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return top, nil
+}
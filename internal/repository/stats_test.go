@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type StatsTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestStatsTestSuite(t *testing.T) {
+	suite.Run(t, new(StatsTestSuite))
+}
+
+func (suite *StatsTestSuite) SetupSuite() {
+	suite.ctx = organizationContext()
+}
+
+func (suite *StatsTestSuite) TestStats() {
+	taskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{
+		Title:       "test stats",
+		Description: "test stats for test",
+		UserId:      TechnicianUser.Id,
+	})
+	suite.NoError(err)
+
+	_, err = repo.FinishTaskById(suite.ctx, int(taskId), TechnicianUser.Id)
+	suite.NoError(err)
+
+	_, err = repo.CreateTask(suite.ctx, entity.TaskRequest{
+		Title:       "test stats open",
+		Description: "test stats open for test",
+		UserId:      TechnicianUser.Id,
+	})
+	suite.NoError(err)
+
+	now := time.Now().UTC()
+	from, to := now.Add(-time.Hour), now.Add(time.Hour)
+
+	days, err := repo.GetTasksPerDay(suite.ctx, ManagerUser.Id, from, to)
+	suite.NoError(err)
+	suite.NotEmpty(days)
+
+	var created, finished int
+	for _, d := range days {
+		created += d.Created
+		finished += d.Finished
+	}
+	suite.GreaterOrEqual(created, 2)
+	suite.GreaterOrEqual(finished, 1)
+
+	mean, err := repo.GetMeanTimeToFinish(suite.ctx, ManagerUser.Id, from, to)
+	suite.NoError(err)
+	suite.GreaterOrEqual(mean, float64(0))
+
+	backlog, err := repo.GetOpenBacklogByTechnician(suite.ctx, ManagerUser.Id)
+	suite.NoError(err)
+	suite.NotEmpty(backlog)
+	for _, b := range backlog {
+		suite.NotEqual(ManagerUser.Id, b.UserId)
+	}
+
+	top, err := repo.GetTopTechniciansByFinished(suite.ctx, ManagerUser.Id, from, to, 1)
+	suite.NoError(err)
+	suite.Len(top, 1)
+	suite.Equal(TechnicianUser.Id, top[0].UserId)
+}
+
+func (suite *StatsTestSuite) TestStatsOfOtherTeams() {
+	manager := SignUpManager(entity.User{Name: "lia", Username: "liaStats", Password: "123456"})
+	technician := SignUpTechnician(entity.User{Name: "rui", Username: "ruiStats", Password: "123456"})
+
+	teamId, err := repo.CreateTeam(suite.ctx, entity.TeamRequest{Name: "stats depot", UserId: manager})
+	suite.Require().NoError(err)
+
+	err = repo.AddTeamMember(suite.ctx, entity.TeamMemberRequest{TeamId: int(teamId), UserId: technician, ManagerId: manager})
+	suite.Require().NoError(err)
+
+	taskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{Title: "other team", Description: "other team stats", UserId: technician})
+	suite.Require().NoError(err)
+
+	_, err = repo.FinishTaskById(suite.ctx, int(taskId), technician)
+	suite.Require().NoError(err)
+
+	now := time.Now().UTC()
+	from, to := now.Add(-time.Hour), now.Add(time.Hour)
+
+	// the manager only sees the technician of their team
+	backlog, err := repo.GetOpenBacklogByTechnician(suite.ctx, manager)
+	suite.NoError(err)
+	suite.Len(backlog, 1)
+	suite.Equal(technician, backlog[0].UserId)
+
+	top, err := repo.GetTopTechniciansByFinished(suite.ctx, manager, from, to, 10)
+	suite.NoError(err)
+	suite.Len(top, 1)
+	suite.Equal(technician, top[0].UserId)
+
+	// and the technician isn't in the stats of other managers
+	backlog, err = repo.GetOpenBacklogByTechnician(suite.ctx, ManagerUser.Id)
+	suite.NoError(err)
+	for _, b := range backlog {
+		suite.NotEqual(technician, b.UserId)
+	}
+
+	top, err = repo.GetTopTechniciansByFinished(suite.ctx, ManagerUser.Id, from, to, 10)
+	suite.NoError(err)
+	for _, t := range top {
+		suite.NotEqual(technician, t.UserId)
+	}
+
+	days, err := repo.GetTasksPerDay(suite.ctx, manager, from, to)
+	suite.NoError(err)
+
+	var created int
+	for _, d := range days {
+		created += d.Created
+	}
+	suite.Equal(1, created)
+}
@@ -2,109 +2,125 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
-	"strings"
 
+	"github.com/lucas-simao/api-tasks/internal/apperror"
 	"github.com/lucas-simao/api-tasks/internal/entity"
 )
 
 var (
-	ErrTaskWithoutUser = errors.New("user id cannot be empty")
-	ErrNoTaskInResult  = errors.New("task not found")
+	ErrTaskWithoutUser = apperror.Invalid("task_without_user", "user id cannot be empty")
+	ErrNoTaskInResult  = apperror.NotFound("task_not_found", "task not found")
 )
 
+// CreateTask creates a task of a user of the organization of the context.
 func (r *repository) CreateTask(ctx context.Context, t entity.TaskRequest) (int64, error) {
-	result, err := r.db.ExecContext(ctx, sqlCreateTask, t.Title, t.Description, t.UserId)
+	organizationId, err := organization(ctx)
 	if err != nil {
-		if strings.Contains(err.Error(), "user_id") {
-			return 0, ErrTaskWithoutUser
-		}
 		return 0, err
 	}
 
-	id, err := result.LastInsertId()
+	id, err := r.insert(ctx, r.sql.createTask, t.Title, t.Description, organizationId, t.UserId)
 	if err != nil {
+		// nothing is inserted when the user isn't of the organization
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrTaskWithoutUser
+		}
 		return 0, err
 	}
 
 	return id, nil
 }
 
-func (r *repository) GetTasks(ctx context.Context, userId, roleCode int) ([]entity.TaskResponse, error) {
-	var args []interface{}
-
-	sql := sqlGetTasks
-
-	if roleCode == entity.TechnicianRole {
-		sql += ` AND created_by_user_id=?`
-		args = append(args, userId)
-	}
-
-	rows, err := r.db.QueryContext(ctx, sql, args...)
+func (r *repository) GetTasks(ctx context.Context, userId int, scope entity.Scope) ([]entity.TaskResponse, error) {
+	organizationId, err := organization(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	defer rows.Close()
+	query, args := scopeTasks(r.sql.getTasks, []interface{}{organizationId}, userId, scope)
 
-	var tasks = []entity.TaskResponse{}
+	var tasks []entity.TaskResponse
 
-	for rows.Next() {
-		t := entity.TaskResponse{}
+	err = r.read(ctx, func() error {
+		tasks = []entity.TaskResponse{}
 
-		err := rows.Scan(
-			&t.Id,
-			&t.Title,
-			&t.Description,
-			&t.CreatedBy.Id,
-			&t.CreatedBy.Name,
-			&t.CreatedBy.Date,
-			&t.DeletedBy.Id,
-			&t.DeletedBy.Name,
-			&t.DeletedBy.Date,
-			&t.UpdatedAt,
-			&t.FinishedAt,
-		)
+		rows, err := r.db.QueryContext(ctx, query, args...)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		tasks = append(tasks, t)
+		defer rows.Close()
+
+		for rows.Next() {
+			t := entity.TaskResponse{}
+
+			err := rows.Scan(
+				&t.Id,
+				&t.Title,
+				&t.Description,
+				&t.CreatedBy.Id,
+				&t.CreatedBy.Name,
+				&t.CreatedBy.Date,
+				&t.DeletedBy.Id,
+				&t.DeletedBy.Name,
+				&t.DeletedBy.Date,
+				&t.UpdatedAt,
+				&t.FinishedAt,
+				&t.TimeSpentSeconds,
+			)
+			if err != nil {
+				return err
+			}
+
+			tasks = append(tasks, t)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return tasks, nil
 }
 
-func (r *repository) GetTaskById(ctx context.Context, taskId, userId, roleCode int) (entity.TaskResponse, error) {
-	var args []interface{}
+func (r *repository) GetTaskById(ctx context.Context, taskId, userId int, scope entity.Scope) (entity.TaskResponse, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return entity.TaskResponse{}, err
+	}
+
+	args := []interface{}{organizationId}
 
-	sql := sqlGetTasks
+	query := r.sql.getTasks
 
-	sql += ` AND t.id=?`
+	query += ` AND t.id=?`
 	args = append(args, taskId)
 
-	if roleCode == entity.TechnicianRole {
-		sql += ` AND created_by_user_id=?`
-		args = append(args, userId)
-	}
+	query, args = scopeTasks(query, args, userId, scope)
 
 	t := entity.TaskResponse{}
 
-	err := r.db.QueryRowContext(ctx, sql, args...).Scan(
-		&t.Id,
-		&t.Title,
-		&t.Description,
-		&t.CreatedBy.Id,
-		&t.CreatedBy.Name,
-		&t.CreatedBy.Date,
-		&t.DeletedBy.Id,
-		&t.DeletedBy.Name,
-		&t.DeletedBy.Date,
-		&t.UpdatedAt,
-		&t.FinishedAt,
-	)
+	err = r.read(ctx, func() error {
+		return r.db.QueryRowContext(ctx, query, args...).Scan(
+			&t.Id,
+			&t.Title,
+			&t.Description,
+			&t.CreatedBy.Id,
+			&t.CreatedBy.Name,
+			&t.CreatedBy.Date,
+			&t.DeletedBy.Id,
+			&t.DeletedBy.Name,
+			&t.DeletedBy.Date,
+			&t.UpdatedAt,
+			&t.FinishedAt,
+			&t.TimeSpentSeconds,
+		)
+	})
 	if err != nil {
-		if strings.Contains(err.Error(), "no rows in result set") {
+		if errors.Is(err, sql.ErrNoRows) {
 			return entity.TaskResponse{}, ErrNoTaskInResult
 		}
 		return entity.TaskResponse{}, err
@@ -113,40 +129,61 @@ func (r *repository) GetTaskById(ctx context.Context, taskId, userId, roleCode i
 	return t, nil
 }
 
-func (r *repository) DeleteTaskById(ctx context.Context, taskId, userId int) error {
-	result, err := r.db.ExecContext(ctx, sqlDeleteTaskById, userId, taskId)
-	if err != nil {
-		return err
-	}
-
-	id, err := result.RowsAffected()
+// DeleteTaskById deletes a task in the scope of the user userId.
+func (r *repository) DeleteTaskById(ctx context.Context, taskId, userId int, scope entity.Scope) error {
+	organizationId, err := organization(ctx)
 	if err != nil {
 		return err
 	}
 
-	if id == 0 {
-		return ErrNoTaskInResult
-	}
+	query, args := scopeTasks(r.sql.deleteTaskById, []interface{}{userId, organizationId, taskId}, userId, scope)
 
-	return nil
+	return r.execTask(ctx, query, args...)
 }
 
+// UpdateTaskById returns the task as updated, the update and the read run in
+// the same transaction so a concurrent delete can't come in between.
 func (r *repository) UpdateTaskById(ctx context.Context, task entity.TaskUpdateRequest) (entity.TaskResponse, error) {
-	result, err := r.db.ExecContext(ctx, sqlUpdateTaskById, task.Title, task.Description, task.UserId, task.Id)
+	organizationId, err := organization(ctx)
 	if err != nil {
 		return entity.TaskResponse{}, err
 	}
 
-	idAffected, err := result.RowsAffected()
+	var taskUpdated entity.TaskResponse
+
+	err = r.inTx(ctx, func(tx *repository) error {
+		err := tx.execTask(ctx, r.sql.updateTaskById, task.Title, task.Description, organizationId, task.UserId, task.Id)
+		if err != nil {
+			return err
+		}
+
+		taskUpdated, err = tx.GetTaskById(ctx, task.Id, task.UserId, entity.ScopeOwn)
+		return err
+	})
 	if err != nil {
 		return entity.TaskResponse{}, err
 	}
 
-	if idAffected == 0 {
-		return entity.TaskResponse{}, ErrNoTaskInResult
+	return taskUpdated, nil
+}
+
+func (r *repository) FinishTaskById(ctx context.Context, taskId, userId int) (entity.TaskResponse, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return entity.TaskResponse{}, err
 	}
 
-	taskUpdated, err := r.GetTaskById(ctx, task.Id, task.UserId, entity.TechnicianRole)
+	var taskUpdated entity.TaskResponse
+
+	err = r.inTx(ctx, func(tx *repository) error {
+		err := tx.execTask(ctx, r.sql.doneTaskById, organizationId, userId, taskId)
+		if err != nil {
+			return err
+		}
+
+		taskUpdated, err = tx.GetTaskById(ctx, taskId, userId, entity.ScopeOwn)
+		return err
+	})
 	if err != nil {
 		return entity.TaskResponse{}, err
 	}
@@ -154,25 +191,46 @@ func (r *repository) UpdateTaskById(ctx context.Context, task entity.TaskUpdateR
 	return taskUpdated, nil
 }
 
-func (r *repository) FinishTaskById(ctx context.Context, taskId, userId int) (entity.TaskResponse, error) {
-	result, err := r.db.ExecContext(ctx, sqlDoneTaskById, userId, taskId)
+// execTask runs a statement changing a single task, ErrNoTaskInResult is
+// returned when no task matched.
+func (r *repository) execTask(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
-		return entity.TaskResponse{}, err
+		return err
 	}
 
 	idAffected, err := result.RowsAffected()
 	if err != nil {
-		return entity.TaskResponse{}, err
+		return err
 	}
 
 	if idAffected == 0 {
-		return entity.TaskResponse{}, ErrNoTaskInResult
+		return ErrNoTaskInResult
 	}
 
-	taskUpdated, err := r.GetTaskById(ctx, taskId, userId, entity.TechnicianRole)
-	if err != nil {
-		return entity.TaskResponse{}, err
-	}
+	return nil
+}
 
-	return taskUpdated, nil
+// scopeTasks restricts a tasks query to the tasks in the scope of the user,
+// their own tasks, the tasks of their teammates or every task. The query must
+// have a single created_by_user_id column, the one of tasks.
+func scopeTasks(sql string, args []interface{}, userId int, scope entity.Scope) (string, []interface{}) {
+	return scopeUsers(sql, args, "created_by_user_id", userId, scope)
+}
+
+// scopeUsers restricts the users of the column to the ones in the scope of
+// the user, the user, their teammates or every user.
+func scopeUsers(sql string, args []interface{}, column string, userId int, scope entity.Scope) (string, []interface{}) {
+	switch scope {
+	case entity.ScopeOwn:
+		sql += ` AND ` + column + `=?`
+	case entity.ScopeTeam:
+		sql += ` AND ` + column + ` IN (` + teammates + `)`
+	case entity.ScopeAny:
+		return sql, args
+	default:
+		return sql + ` AND false`, args
+	}
+
+	return sql, append(args, userId)
 }
@@ -19,7 +19,7 @@ func TestTasksTestSuite(t *testing.T) {
 }
 
 func (suite *TasksTestSuite) SetupSuite() {
-	suite.ctx = context.Background()
+	suite.ctx = organizationContext()
 }
 
 func (suite *TasksTestSuite) TestCreateTask() {
@@ -75,20 +75,21 @@ func (suite *TasksTestSuite) TestGetTasks() {
 	suite.NoError(err)
 
 	cases := map[string]struct {
-		userId, roleCode int
-		haveTasks        bool
-		err              error
+		userId    int
+		scope     entity.Scope
+		haveTasks bool
+		err       error
 	}{
 		"1 - Should return tasks": {
 			userId:    TechnicianUser.Id,
-			roleCode:  TechnicianUser.CodeRole,
+			scope:     entity.ScopeOwn,
 			haveTasks: true,
 			err:       nil,
 		},
 		"2 - Shouldn't return": {
-			userId:   ManagerUser.Id,
-			roleCode: ManagerUser.CodeRole,
-			err:      nil,
+			userId: ManagerUser.Id,
+			scope:  entity.ScopeTeam,
+			err:    nil,
 		},
 	}
 
@@ -101,7 +102,7 @@ func (suite *TasksTestSuite) TestGetTasks() {
 
 	for _, key := range keys {
 		suite.Run(key, func() {
-			tasks, err := repo.GetTasks(suite.ctx, cases[key].userId, cases[key].roleCode)
+			tasks, err := repo.GetTasks(suite.ctx, cases[key].userId, cases[key].scope)
 			if err != nil {
 				suite.Equal(cases[key].err, err)
 				return
@@ -128,20 +129,21 @@ func (suite *TasksTestSuite) TestGetTaskById() {
 	suite.NoError(err)
 
 	cases := map[string]struct {
-		userId, roleCode, taskId int
-		err                      error
+		userId, taskId int
+		scope          entity.Scope
+		err            error
 	}{
 		"1 - Should return tasks": {
-			userId:   TechnicianUser.Id,
-			roleCode: TechnicianUser.CodeRole,
-			taskId:   int(taskId),
-			err:      nil,
+			userId: TechnicianUser.Id,
+			scope:  entity.ScopeOwn,
+			taskId: int(taskId),
+			err:    nil,
 		},
 		"2 - Shouldn't return": {
-			userId:   TechnicianUser.Id,
-			roleCode: TechnicianUser.CodeRole,
-			taskId:   0,
-			err:      ErrNoTaskInResult,
+			userId: TechnicianUser.Id,
+			scope:  entity.ScopeOwn,
+			taskId: 0,
+			err:    ErrNoTaskInResult,
 		},
 	}
 
@@ -154,7 +156,7 @@ func (suite *TasksTestSuite) TestGetTaskById() {
 
 	for _, key := range keys {
 		suite.Run(key, func() {
-			task, err := repo.GetTaskById(suite.ctx, cases[key].taskId, cases[key].userId, cases[key].roleCode)
+			task, err := repo.GetTaskById(suite.ctx, cases[key].taskId, cases[key].userId, cases[key].scope)
 			if cases[key].err != nil {
 				suite.Equal(cases[key].err, err)
 				return
@@ -207,7 +209,7 @@ func (suite *TasksTestSuite) TestDeleteTaskById() {
 
 	for _, key := range keys {
 		suite.Run(key, func() {
-			err := repo.DeleteTaskById(suite.ctx, cases[key].taskId, cases[key].userId)
+			err := repo.DeleteTaskById(suite.ctx, cases[key].taskId, cases[key].userId, entity.ScopeTeam)
 			if cases[key].err != nil {
 				suite.Equal(cases[key].err, err)
 				return
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+var (
+	ErrTeamNameUnavailable = apperror.Conflict("team_name_unavailable", "team name is unavailable")
+	ErrTeamNotFound        = apperror.NotFound("team_not_found", "team not found")
+	ErrTeamMemberNotFound  = apperror.NotFound("team_member_not_found", "user isn't a member of the team")
+	ErrUserInOtherTeam     = apperror.Forbidden("user_in_other_team", "user is a member of a team of another manager")
+)
+
+// CreateTeam creates a team with the user creating it as the first member.
+// Team names are unique in the organization.
+func (r *repository) CreateTeam(ctx context.Context, t entity.TeamRequest) (int64, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int64
+
+	err = r.inTx(ctx, func(tx *repository) error {
+		var err error
+		id, err = tx.insert(ctx, r.sql.createTeam, organizationId, t.Name)
+		if err != nil {
+			// the name is the only unique value of a team, SQLite doesn't name
+			// the columns of a unique index of several columns
+			var duplicate *ErrDuplicate
+			if errors.As(err, &duplicate) {
+				return ErrTeamNameUnavailable
+			}
+			return err
+		}
+
+		return tx.addTeamMember(ctx, int(id), t.UserId)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetTeamsByUser returns the teams of the user with all of their members.
+func (r *repository) GetTeamsByUser(ctx context.Context, userId int) ([]entity.TeamResponse, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var teams []entity.TeamResponse
+
+	err = r.read(ctx, func() error {
+		teams = []entity.TeamResponse{}
+
+		rows, err := r.db.QueryContext(ctx, r.sql.getTeamsByUser, organizationId, userId)
+		if err != nil {
+			return err
+		}
+
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				team     entity.TeamResponse
+				member   entity.TeamMember
+				codeRole int
+			)
+
+			err := rows.Scan(&team.Id, &team.Name, &member.Id, &member.Name, &member.Username, &codeRole)
+			if err != nil {
+				return err
+			}
+
+			member.Role = entity.RoleName(codeRole)
+
+			// rows are sorted by team, a new team starts when the id changes
+			if len(teams) == 0 || teams[len(teams)-1].Id != team.Id {
+				team.Members = []entity.TeamMember{}
+				teams = append(teams, team)
+			}
+
+			last := &teams[len(teams)-1]
+			last.Members = append(last.Members, member)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+// GetTeamManagersByUser returns the managers sharing a team with the user.
+func (r *repository) GetTeamManagersByUser(ctx context.Context, userId int) ([]entity.User, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var managers = []entity.User{}
+
+	err = r.read(ctx, func() error {
+		managers = managers[:0]
+		return r.db.SelectContext(ctx, &managers, r.sql.getTeamManagersByUser, organizationId, entity.ManagerRole, userId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return managers, nil
+}
+
+// AddTeamMember adds the user to a team of the manager, adding a member twice
+// is not an error. Users of teams of other managers aren't added, the manager
+// would see their tasks, ErrUserInOtherTeam is returned.
+func (r *repository) AddTeamMember(ctx context.Context, m entity.TeamMemberRequest) error {
+	return r.inTx(ctx, func(tx *repository) error {
+		if err := tx.checkTeamManager(ctx, m); err != nil {
+			return err
+		}
+
+		// checked before the insert, a failed statement aborts the transaction
+		// on PostgreSQL
+		count, err := tx.countTeamMember(ctx, m.TeamId, m.UserId)
+		if err != nil || count > 0 {
+			return err
+		}
+
+		if err := tx.checkOtherTeams(ctx, m); err != nil {
+			return err
+		}
+
+		return tx.addTeamMember(ctx, m.TeamId, m.UserId)
+	})
+}
+
+// addTeamMember adds the user to the team, ErrUserNotExist is returned when
+// the user isn't of the organization of the team.
+func (r *repository) addTeamMember(ctx context.Context, teamId, userId int) error {
+	result, err := r.db.ExecContext(ctx, r.sql.addTeamMember, teamId, userId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrUserNotExist
+	}
+
+	return nil
+}
+
+// RemoveTeamMember removes the user from a team of the manager.
+func (r *repository) RemoveTeamMember(ctx context.Context, m entity.TeamMemberRequest) error {
+	return r.inTx(ctx, func(tx *repository) error {
+		if err := tx.checkTeamManager(ctx, m); err != nil {
+			return err
+		}
+
+		result, err := tx.db.ExecContext(ctx, r.sql.removeTeamMember, m.TeamId, m.UserId)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return ErrTeamMemberNotFound
+		}
+
+		return nil
+	})
+}
+
+// checkTeamManager returns ErrTeamNotFound unless the manager is a member of
+// the team, teams of other managers are not revealed.
+func (r *repository) checkTeamManager(ctx context.Context, m entity.TeamMemberRequest) error {
+	count, err := r.countTeamMember(ctx, m.TeamId, m.ManagerId)
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrTeamNotFound
+	}
+
+	return nil
+}
+
+// checkOtherTeams returns ErrUserInOtherTeam when the user is a member of a
+// team the manager isn't, users without a team or only in teams of the manager
+// are added.
+func (r *repository) checkOtherTeams(ctx context.Context, m entity.TeamMemberRequest) error {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var count int
+
+	err = r.db.GetContext(ctx, &count, r.sql.countOtherTeams, organizationId, m.UserId, m.ManagerId)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return ErrUserInOtherTeam
+	}
+
+	return nil
+}
+
+// countTeamMember counts the user in the team, teams of other organizations
+// have no members.
+func (r *repository) countTeamMember(ctx context.Context, teamId, userId int) (int, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+
+	err = r.db.GetContext(ctx, &count, r.sql.countTeamMember, organizationId, teamId, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
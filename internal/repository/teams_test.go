@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type TeamsTestSuite struct {
+	suite.Suite
+	ctx context.Context
+
+	// other manager and technician, in a team of their own
+	manager, technician int
+	teamId              int
+}
+
+func TestTeamsTestSuite(t *testing.T) {
+	suite.Run(t, new(TeamsTestSuite))
+}
+
+func (suite *TeamsTestSuite) SetupSuite() {
+	suite.ctx = organizationContext()
+
+	suite.manager = SignUpManager(entity.User{Name: "maria", Username: "mariaTeams", Password: "123456"})
+	suite.technician = SignUpTechnician(entity.User{Name: "pedro", Username: "pedroTeams", Password: "123456"})
+
+	id, err := repo.CreateTeam(suite.ctx, entity.TeamRequest{Name: "other depot", UserId: suite.manager})
+	suite.Require().NoError(err)
+	suite.teamId = int(id)
+
+	err = repo.AddTeamMember(suite.ctx, entity.TeamMemberRequest{TeamId: suite.teamId, UserId: suite.technician, ManagerId: suite.manager})
+	suite.Require().NoError(err)
+}
+
+func (suite *TeamsTestSuite) TestGetUserInScope() {
+	u, err := repo.GetUserInScope(suite.ctx, suite.technician, suite.manager, entity.ScopeTeam)
+	suite.NoError(err)
+	suite.Equal(suite.technician, u.Id)
+
+	// managers don't see the technicians of other teams
+	_, err = repo.GetUserInScope(suite.ctx, suite.technician, ManagerUser.Id, entity.ScopeTeam)
+	suite.Equal(ErrUserNotExist, err)
+
+	_, err = repo.GetUserInScope(suite.ctx, suite.technician, ManagerUser.Id, entity.ScopeAny)
+	suite.NoError(err)
+
+	_, err = repo.GetUserInScope(suite.ctx, suite.technician, suite.technician, entity.ScopeOwn)
+	suite.NoError(err)
+
+	_, err = repo.GetUserInScope(suite.ctx, suite.manager, suite.technician, entity.ScopeOwn)
+	suite.Equal(ErrUserNotExist, err)
+}
+
+func (suite *TeamsTestSuite) TestManagersOnlySeeTheirTeams() {
+	taskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{Title: "team", Description: "team task", UserId: suite.technician})
+	suite.Require().NoError(err)
+
+	_, err = repo.GetTaskById(suite.ctx, int(taskId), suite.manager, entity.ScopeTeam)
+	suite.NoError(err)
+
+	_, err = repo.GetTaskById(suite.ctx, int(taskId), ManagerUser.Id, entity.ScopeTeam)
+	suite.Equal(ErrNoTaskInResult, err)
+
+	tasks, err := repo.GetTasks(suite.ctx, ManagerUser.Id, entity.ScopeTeam)
+	suite.NoError(err)
+	for _, t := range tasks {
+		suite.NotEqual(suite.technician, t.CreatedBy.Id)
+	}
+
+	err = repo.DeleteTaskById(suite.ctx, int(taskId), ManagerUser.Id, entity.ScopeTeam)
+	suite.Equal(ErrNoTaskInResult, err)
+
+	err = repo.DeleteTaskById(suite.ctx, int(taskId), suite.manager, entity.ScopeTeam)
+	suite.NoError(err)
+}
+
+func (suite *TeamsTestSuite) TestVisitorsSeeNoTasks() {
+	tasks, err := repo.GetTasks(suite.ctx, suite.technician, entity.ScopeNone)
+	suite.NoError(err)
+	suite.Empty(tasks)
+}
+
+func (suite *TeamsTestSuite) TestGetTeamManagersByUser() {
+	managers, err := repo.GetTeamManagersByUser(suite.ctx, suite.technician)
+	suite.NoError(err)
+	suite.Len(managers, 1)
+	suite.Equal(suite.manager, managers[0].Id)
+	suite.Equal(entity.ManagerRole, managers[0].CodeRole)
+
+	managers, err = repo.GetTeamManagersByUser(suite.ctx, TechnicianUser.Id)
+	suite.NoError(err)
+	suite.Len(managers, 1)
+	suite.Equal(ManagerUser.Id, managers[0].Id)
+}
+
+func (suite *TeamsTestSuite) TestGetTeamsByUser() {
+	teams, err := repo.GetTeamsByUser(suite.ctx, suite.technician)
+	suite.NoError(err)
+	suite.Len(teams, 1)
+	suite.Equal("other depot", teams[0].Name)
+	suite.Len(teams[0].Members, 2)
+
+	roles := map[int]string{}
+	for _, m := range teams[0].Members {
+		roles[m.Id] = m.Role
+	}
+	suite.Equal(map[int]string{suite.manager: "manager", suite.technician: "technician"}, roles)
+}
+
+func (suite *TeamsTestSuite) TestAddTeamMemberOfNoTeam() {
+	manager := SignUpManager(entity.User{Name: "caio", Username: "caioTeams", Password: "123456"})
+	technician := SignUpTechnician(entity.User{Name: "sara", Username: "saraTeams", Password: "123456"})
+
+	day, err := repo.CreateTeam(suite.ctx, entity.TeamRequest{Name: "day shift", UserId: manager})
+	suite.Require().NoError(err)
+
+	err = repo.AddTeamMember(suite.ctx, entity.TeamMemberRequest{TeamId: int(day), UserId: technician, ManagerId: manager})
+	suite.NoError(err)
+
+	// managers can't pull the technician into their teams from then on
+	err = repo.AddTeamMember(suite.ctx, entity.TeamMemberRequest{TeamId: TeamId, UserId: technician, ManagerId: ManagerUser.Id})
+	suite.Equal(ErrUserInOtherTeam, err)
+
+	// the manager of the technician can add it to another of their teams
+	night, err := repo.CreateTeam(suite.ctx, entity.TeamRequest{Name: "night shift", UserId: manager})
+	suite.Require().NoError(err)
+
+	err = repo.AddTeamMember(suite.ctx, entity.TeamMemberRequest{TeamId: int(night), UserId: technician, ManagerId: manager})
+	suite.NoError(err)
+}
+
+func (suite *TeamsTestSuite) TestTeamMembers() {
+	cases := map[string]struct {
+		member entity.TeamMemberRequest
+		remove bool
+		err    error
+	}{
+		"1 - Should add a member twice": {
+			member: entity.TeamMemberRequest{TeamId: suite.teamId, UserId: suite.technician, ManagerId: suite.manager},
+		},
+		"2 - Shouldn't add - manager out of the team": {
+			member: entity.TeamMemberRequest{TeamId: suite.teamId, UserId: TechnicianUser.Id, ManagerId: ManagerUser.Id},
+			err:    ErrTeamNotFound,
+		},
+		"3 - Shouldn't add - user don't exist": {
+			member: entity.TeamMemberRequest{TeamId: suite.teamId, UserId: 1 << 30, ManagerId: suite.manager},
+			err:    ErrUserNotExist,
+		},
+		"4 - Shouldn't add - technician of another manager": {
+			member: entity.TeamMemberRequest{TeamId: suite.teamId, UserId: TechnicianUser.Id, ManagerId: suite.manager},
+			err:    ErrUserInOtherTeam,
+		},
+		"5 - Shouldn't remove - not a member": {
+			member: entity.TeamMemberRequest{TeamId: suite.teamId, UserId: TechnicianUser.Id, ManagerId: suite.manager},
+			remove: true,
+			err:    ErrTeamMemberNotFound,
+		},
+	}
+
+	for name, tc := range cases {
+		suite.Run(name, func() {
+			var err error
+			if tc.remove {
+				err = repo.RemoveTeamMember(suite.ctx, tc.member)
+			} else {
+				err = repo.AddTeamMember(suite.ctx, tc.member)
+			}
+
+			suite.Equal(tc.err, err)
+		})
+	}
+}
+
+func (suite *TeamsTestSuite) TestCreateTeamTwice() {
+	_, err := repo.CreateTeam(suite.ctx, entity.TeamRequest{Name: "other depot", UserId: suite.manager})
+	suite.Equal(ErrTeamNameUnavailable, err)
+}
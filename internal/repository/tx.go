@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// queryer is implemented by both *sqlx.DB and *sqlx.Tx, so every method runs
+// the same inside and outside a transaction.
+type queryer interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PreparexContext(ctx context.Context, query string) (*sqlx.Stmt, error)
+}
+
+// WithTx runs fn in a transaction, committed when fn returns nil and rolled
+// back otherwise. The repository passed to fn must not be used after fn
+// returns, nor by several goroutines. Calling WithTx on that repository
+// creates a savepoint, so a nested failure only rolls back its own changes.
+func (r *repository) WithTx(ctx context.Context, fn func(Repository) error) error {
+	return r.inTx(ctx, func(tx *repository) error {
+		return fn(tx)
+	})
+}
+
+func (r *repository) inTx(ctx context.Context, fn func(*repository) error) (err error) {
+	if r.tx != nil {
+		return r.inSavepoint(ctx, fn)
+	}
+
+	tx, err := r.conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	err = fn(&repository{dialect: r.dialect, conn: r.conn, db: rebound{tx, r.dialect}, tx: tx})
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && !errors.Is(rollbackErr, sql.ErrTxDone) {
+			return errors.Join(err, fmt.Errorf("error to rollback transaction: %w", rollbackErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) inSavepoint(ctx context.Context, fn func(*repository) error) error {
+	nested := &repository{dialect: r.dialect, conn: r.conn, db: r.db, tx: r.tx, depth: r.depth + 1}
+	name := fmt.Sprintf("sp_%d", nested.depth)
+
+	if _, err := r.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("error to create savepoint: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			r.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+			panic(p)
+		}
+	}()
+
+	if err := fn(nested); err != nil {
+		if _, rollbackErr := r.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("error to rollback savepoint: %w", rollbackErr))
+		}
+		return err
+	}
+
+	if _, err := r.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("error to release savepoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type TxTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestTxTestSuite(t *testing.T) {
+	suite.Run(t, new(TxTestSuite))
+}
+
+func (suite *TxTestSuite) SetupSuite() {
+	suite.ctx = organizationContext()
+}
+
+func (suite *TxTestSuite) createTask(r Repository) int {
+	id, err := r.CreateTask(suite.ctx, entity.TaskRequest{
+		Title:       "test tx",
+		Description: "test tx for test",
+		UserId:      TechnicianUser.Id,
+	})
+	suite.NoError(err)
+
+	return int(id)
+}
+
+func (suite *TxTestSuite) exists(taskId int) bool {
+	_, err := repo.GetTaskById(suite.ctx, taskId, ManagerUser.Id, entity.ScopeTeam)
+	if errors.Is(err, ErrNoTaskInResult) {
+		return false
+	}
+	suite.NoError(err)
+
+	return true
+}
+
+func (suite *TxTestSuite) TestCommit() {
+	var taskId int
+
+	err := repo.WithTx(suite.ctx, func(tx Repository) error {
+		taskId = suite.createTask(tx)
+		return nil
+	})
+	suite.NoError(err)
+	suite.True(suite.exists(taskId))
+}
+
+func (suite *TxTestSuite) TestRollback() {
+	var taskId int
+	errFailed := errors.New("failed")
+
+	err := repo.WithTx(suite.ctx, func(tx Repository) error {
+		taskId = suite.createTask(tx)
+
+		// visible inside the transaction only
+		_, err := tx.GetTaskById(suite.ctx, taskId, ManagerUser.Id, entity.ScopeTeam)
+		suite.NoError(err)
+
+		return errFailed
+	})
+	suite.ErrorIs(err, errFailed)
+	suite.False(suite.exists(taskId))
+}
+
+func (suite *TxTestSuite) TestRollbackOnPanic() {
+	var taskId int
+
+	suite.Panics(func() {
+		repo.WithTx(suite.ctx, func(tx Repository) error {
+			taskId = suite.createTask(tx)
+			panic("failed")
+		})
+	})
+	suite.False(suite.exists(taskId))
+}
+
+func (suite *TxTestSuite) TestNested() {
+	var outerId, innerId int
+	errFailed := errors.New("failed")
+
+	err := repo.WithTx(suite.ctx, func(tx Repository) error {
+		outerId = suite.createTask(tx)
+
+		err := tx.WithTx(suite.ctx, func(nested Repository) error {
+			innerId = suite.createTask(nested)
+			return errFailed
+		})
+		suite.ErrorIs(err, errFailed)
+
+		// repository methods using a transaction themselves nest too
+		_, err = tx.UpdateTaskById(suite.ctx, entity.TaskUpdateRequest{
+			Id:          outerId,
+			Title:       "test tx updated",
+			Description: "test tx for test",
+			UserId:      TechnicianUser.Id,
+		})
+		return err
+	})
+	suite.NoError(err)
+	suite.True(suite.exists(outerId))
+	suite.False(suite.exists(innerId))
+}
@@ -2,54 +2,110 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
-	"strings"
 
+	"github.com/lucas-simao/api-tasks/internal/apperror"
 	"github.com/lucas-simao/api-tasks/internal/entity"
 )
 
 var (
-	ErrUsernameUnavailable = errors.New("username is unavailable")
-	ErrUserNotExist        = errors.New("user don't exist")
+	ErrUsernameUnavailable = apperror.Conflict("username_unavailable", "username is unavailable")
+	ErrUserNotExist        = apperror.NotFound("user_not_found", "user don't exist")
 )
 
+// SignUp registers the user in the organization u.OrganizationId with the role
+// u.CodeRole, visitor unless set.
 func (r *repository) SignUp(ctx context.Context, u entity.SignUpRequest) error {
+	return r.inTx(ctx, func(tx *repository) error {
+		role, err := tx.GetUserRoleByCode(ctx, u.CodeRole)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.db.ExecContext(ctx, r.sql.signUp, u.Name, u.Username, u.Password, role.Id, u.OrganizationId)
+		if err != nil {
+			var duplicate *ErrDuplicate
+			if errors.As(err, &duplicate) && duplicate.Field == "username" {
+				return ErrUsernameUnavailable
+			}
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *repository) GetUserRoleByCode(ctx context.Context, code int) (entity.UserRole, error) {
+
+	var u = entity.UserRole{}
 
-	userRoleDefault, err := r.GetUserRoleByCode(ctx, entity.VisitorRole)
+	err := r.read(ctx, func() error {
+		return r.db.GetContext(ctx, &u, r.sql.getUserRoleByCode, code)
+	})
 	if err != nil {
-		return err
+		return entity.UserRole{}, err
 	}
 
-	_, err = r.db.ExecContext(ctx, sqlSignUp, u.Name, u.Username, u.Password, userRoleDefault.Id)
+	return u, nil
+}
+
+func (r *repository) SignIn(ctx context.Context, username string) (entity.User, error) {
+
+	var u = entity.User{}
+
+	err := r.read(ctx, func() error {
+		return r.db.GetContext(ctx, &u, r.sql.getUserByUsername, username)
+	})
 	if err != nil {
-		if strings.Contains(err.Error(), "users.username") {
-			return ErrUsernameUnavailable
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.User{}, ErrUserNotExist
 		}
-		return err
+		return entity.User{}, err
 	}
 
-	return nil
+	return u, nil
 }
 
-func (r *repository) GetUserRoleByCode(ctx context.Context, code int) (entity.UserRole, error) {
+// GetUserById returns a user of the organization of the context.
+func (r *repository) GetUserById(ctx context.Context, id int) (entity.User, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return entity.User{}, err
+	}
 
-	var u = entity.UserRole{}
+	var u = entity.User{}
 
-	err := r.db.GetContext(ctx, &u, sqlGetUserRoleByCode, code)
+	err = r.read(ctx, func() error {
+		return r.db.GetContext(ctx, &u, r.sql.getUserById, organizationId, id)
+	})
 	if err != nil {
-		return entity.UserRole{}, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.User{}, ErrUserNotExist
+		}
+		return entity.User{}, err
 	}
 
 	return u, nil
 }
 
-func (r *repository) SignIn(ctx context.Context, username string) (entity.User, error) {
+// GetUserInScope returns a user of the organization of the context in the
+// scope of another, ErrUserNotExist for the users out of it.
+func (r *repository) GetUserInScope(ctx context.Context, id, userId int, scope entity.Scope) (entity.User, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	query, args := scopeUsers(r.sql.getUserById, []interface{}{organizationId, id}, "users.id", userId, scope)
 
 	var u = entity.User{}
 
-	err := r.db.GetContext(ctx, &u, sqlGetUserByUsername, username)
+	err = r.read(ctx, func() error {
+		return r.db.GetContext(ctx, &u, query, args...)
+	})
 	if err != nil {
-		if strings.Contains(err.Error(), "sql: no rows in result set") {
+		if errors.Is(err, sql.ErrNoRows) {
 			return entity.User{}, ErrUserNotExist
 		}
 		return entity.User{}, err
@@ -57,3 +113,51 @@ func (r *repository) SignIn(ctx context.Context, username string) (entity.User,
 
 	return u, nil
 }
+
+// GetUserByIdentity isn't scoped, the identity provider tells the user and
+// so its organization.
+func (r *repository) GetUserByIdentity(ctx context.Context, issuer, subject string) (entity.User, error) {
+	var u = entity.User{}
+
+	err := r.read(ctx, func() error {
+		return r.db.GetContext(ctx, &u, r.sql.getUserByIdentity, issuer, subject)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.User{}, ErrUserNotExist
+		}
+		return entity.User{}, err
+	}
+
+	return u, nil
+}
+
+func (r *repository) AddUserIdentity(ctx context.Context, i entity.UserIdentity) error {
+	_, err := r.db.ExecContext(ctx, r.sql.addUserIdentity, i.Issuer, i.Subject, i.UserId)
+	return err
+}
+
+// UpdateUserRole gives the role codeRole to a user of the organization of the
+// context.
+func (r *repository) UpdateUserRole(ctx context.Context, id, codeRole int) error {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, r.sql.updateUserRole, codeRole, organizationId, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrUserNotExist
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/lucas-simao/api-tasks/internal/tenant"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -21,7 +22,7 @@ func TestUsersTestSuite(t *testing.T) {
 }
 
 func (suite *UsersTestSuite) SetupSuite() {
-	suite.ctx = context.Background()
+	suite.ctx = organizationContext()
 
 	suite.user = entity.User{
 		Name:     "lucas",
@@ -31,9 +32,10 @@ func (suite *UsersTestSuite) SetupSuite() {
 	}
 
 	suite.userSignUp = entity.SignUpRequest{
-		Name:     suite.userSignUp.Name,
-		Username: suite.userSignUp.Username,
-		Password: suite.userSignUp.Password,
+		Name:           suite.userSignUp.Name,
+		Username:       suite.userSignUp.Username,
+		Password:       suite.userSignUp.Password,
+		OrganizationId: OrganizationId,
 	}
 }
 
@@ -51,7 +53,7 @@ func (suite *UsersTestSuite) TestSignUp() {
 			err:  ErrUsernameUnavailable,
 		},
 		"3 - Should return error": {
-			user: entity.SignUpRequest{},
+			user: entity.SignUpRequest{OrganizationId: OrganizationId},
 			err:  ErrUsernameUnavailable,
 		},
 	}
@@ -89,9 +91,10 @@ func (suite *UsersTestSuite) TestSignIn() {
 	}{
 		"1 - Should return user": {
 			user: entity.SignUpRequest{
-				Name:     "João",
-				Username: "joãoOtávio",
-				Password: "123456",
+				Name:           "João",
+				Username:       "joãoOtávio",
+				Password:       "123456",
+				OrganizationId: OrganizationId,
 			},
 			err: nil,
 		},
@@ -159,3 +162,38 @@ func (suite *UsersTestSuite) TestGetUserRoleByCode() {
 		})
 	}
 }
+
+func (suite *UsersTestSuite) TestUserIdentity() {
+	id := SignUpTechnician(entity.User{Name: "ana", Username: "anaIdentity", Password: ""})
+
+	_, err := repo.GetUserByIdentity(context.Background(), "https://idp.example.com", "ana")
+	suite.Equal(ErrUserNotExist, err)
+
+	err = repo.AddUserIdentity(suite.ctx, entity.UserIdentity{Issuer: "https://idp.example.com", Subject: "ana", UserId: id})
+	suite.NoError(err)
+
+	// a subject is linked to one user
+	err = repo.AddUserIdentity(suite.ctx, entity.UserIdentity{Issuer: "https://idp.example.com", Subject: "ana", UserId: TechnicianUser.Id})
+	suite.Error(err)
+
+	userDB, err := repo.GetUserByIdentity(context.Background(), "https://idp.example.com", "ana")
+	suite.NoError(err)
+	suite.Equal(id, userDB.Id)
+	suite.Equal(entity.TechnicianRole, userDB.CodeRole)
+	suite.Equal(OrganizationId, userDB.OrganizationId)
+
+	// subjects of other issuers are other users
+	_, err = repo.GetUserByIdentity(context.Background(), "https://other.example.com", "ana")
+	suite.Equal(ErrUserNotExist, err)
+
+	err = repo.UpdateUserRole(suite.ctx, id, entity.ManagerRole)
+	suite.NoError(err)
+
+	userDB, err = repo.GetUserByIdentity(context.Background(), "https://idp.example.com", "ana")
+	suite.NoError(err)
+	suite.Equal(entity.ManagerRole, userDB.CodeRole)
+
+	// users of another organization can't be changed
+	err = repo.UpdateUserRole(tenant.WithOrganization(context.Background(), OrganizationId+1000), id, entity.TechnicianRole)
+	suite.Equal(ErrUserNotExist, err)
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/lucas-simao/api-tasks/internal/entity"
+)
+
+var (
+	ErrTimerAlreadyRunning = apperror.Conflict("timer_already_running", "user already has a running timer")
+	ErrNoRunningTimer      = apperror.Conflict("no_running_timer", "no running timer for this task")
+	ErrWorkLogOverlap      = apperror.Conflict("work_log_overlap", "work log overlaps an existing entry")
+	ErrNoWorkLogInResult   = apperror.NotFound("work_log_not_found", "work log not found")
+)
+
+func (r *repository) StartTimer(ctx context.Context, taskId, userId int) (entity.WorkLogResponse, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return entity.WorkLogResponse{}, err
+	}
+
+	var id int64
+
+	err = r.inTx(ctx, func(tx *repository) error {
+		err := tx.lockUser(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		id, err = tx.insert(ctx, tx.sql.startTimer, userId, organizationId, userId, taskId)
+		return err
+	})
+	if err != nil {
+		// the unique index of the running timers
+		var duplicate *ErrDuplicate
+		if errors.As(err, &duplicate) {
+			return entity.WorkLogResponse{}, ErrTimerAlreadyRunning
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WorkLogResponse{}, ErrNoTaskInResult
+		}
+		return entity.WorkLogResponse{}, err
+	}
+
+	return r.getWorkLogById(ctx, int(id))
+}
+
+func (r *repository) StopTimer(ctx context.Context, t entity.TimerStopRequest) (entity.WorkLogResponse, error) {
+	var runningId int
+
+	err := r.read(ctx, func() error {
+		return r.db.GetContext(ctx, &runningId, r.sql.getRunningWorkLogIdByTask, t.UserId, t.TaskId)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WorkLogResponse{}, ErrNoRunningTimer
+		}
+		return entity.WorkLogResponse{}, err
+	}
+
+	result, err := r.db.ExecContext(ctx, r.sql.stopTimer, t.Note, runningId)
+	if err != nil {
+		return entity.WorkLogResponse{}, err
+	}
+
+	idAffected, err := result.RowsAffected()
+	if err != nil {
+		return entity.WorkLogResponse{}, err
+	}
+
+	if idAffected == 0 {
+		return entity.WorkLogResponse{}, ErrNoRunningTimer
+	}
+
+	return r.getWorkLogById(ctx, runningId)
+}
+
+func (r *repository) CreateWorkLog(ctx context.Context, w entity.WorkLogRequest) (entity.WorkLogResponse, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return entity.WorkLogResponse{}, err
+	}
+
+	var id int64
+
+	err = r.inTx(ctx, func(tx *repository) error {
+		err := tx.lockUser(ctx, w.UserId)
+		if err != nil {
+			return err
+		}
+
+		id, err = tx.insert(ctx, tx.sql.createWorkLog, w.UserId, w.StartedAt, w.EndedAt, w.Note, organizationId, w.UserId, w.TaskId, w.UserId, w.EndedAt, w.StartedAt)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		var overlapping int
+
+		err = tx.db.GetContext(ctx, &overlapping, tx.sql.countOverlappingWorkLogs, w.UserId, w.EndedAt, w.StartedAt)
+		if err != nil {
+			return err
+		}
+
+		if overlapping > 0 {
+			return ErrWorkLogOverlap
+		}
+		return ErrNoTaskInResult
+	})
+	if err != nil {
+		return entity.WorkLogResponse{}, err
+	}
+
+	return r.getWorkLogById(ctx, int(id))
+}
+
+// lockUser locks the user until the end of the transaction, so the work logs
+// of a user are written one at a time and checked against each other.
+func (r *repository) lockUser(ctx context.Context, userId int) error {
+	_, err := r.db.ExecContext(ctx, r.sql.lockUser, userId)
+	return err
+}
+
+func (r *repository) GetWorkLogs(ctx context.Context, taskId, userId int, scope entity.Scope) ([]entity.WorkLogResponse, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []interface{}{organizationId}
+
+	query := r.sql.getWorkLogs
+
+	query += ` AND t.deleted_at IS NULL AND wl.task_id=?`
+	args = append(args, taskId)
+
+	query, args = scopeTasks(query, args, userId, scope)
+
+	query += ` ORDER BY wl.started_at`
+
+	var workLogs []entity.WorkLogResponse
+
+	err = r.read(ctx, func() error {
+		workLogs = []entity.WorkLogResponse{}
+
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		defer rows.Close()
+
+		for rows.Next() {
+			w := entity.WorkLogResponse{}
+
+			err := rows.Scan(
+				&w.Id,
+				&w.TaskId,
+				&w.StartedAt,
+				&w.EndedAt,
+				&w.DurationSeconds,
+				&w.Note,
+				&w.LoggedBy.Id,
+				&w.LoggedBy.Name,
+				&w.LoggedBy.Date,
+			)
+			if err != nil {
+				return err
+			}
+
+			workLogs = append(workLogs, w)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return workLogs, nil
+}
+
+func (r *repository) getWorkLogById(ctx context.Context, workLogId int) (entity.WorkLogResponse, error) {
+	organizationId, err := organization(ctx)
+	if err != nil {
+		return entity.WorkLogResponse{}, err
+	}
+
+	w := entity.WorkLogResponse{}
+
+	err = r.read(ctx, func() error {
+		return r.db.QueryRowContext(ctx, r.sql.getWorkLogs+` AND wl.id=?`, organizationId, workLogId).Scan(
+			&w.Id,
+			&w.TaskId,
+			&w.StartedAt,
+			&w.EndedAt,
+			&w.DurationSeconds,
+			&w.Note,
+			&w.LoggedBy.Id,
+			&w.LoggedBy.Name,
+			&w.LoggedBy.Date,
+		)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WorkLogResponse{}, ErrNoWorkLogInResult
+		}
+		return entity.WorkLogResponse{}, err
+	}
+
+	return w, nil
+}
@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lucas-simao/api-tasks/internal/entity"
+	"github.com/stretchr/testify/suite"
+)
+
+type WorkLogsTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestWorkLogsTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkLogsTestSuite))
+}
+
+func (suite *WorkLogsTestSuite) SetupSuite() {
+	suite.ctx = organizationContext()
+}
+
+func (suite *WorkLogsTestSuite) TearDownTest() {
+	_, err := DB.Exec(`DELETE FROM work_logs`)
+	suite.NoError(err)
+}
+
+func (suite *WorkLogsTestSuite) TestStartAndStopTimer() {
+	taskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{
+		Title:       "test timer",
+		Description: "test timer for test",
+		UserId:      TechnicianUser.Id,
+	})
+	suite.NoError(err)
+
+	otherTaskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{
+		Title:       "test timer 2",
+		Description: "test timer for test 2",
+		UserId:      TechnicianUser.Id,
+	})
+	suite.NoError(err)
+
+	workLog, err := repo.StartTimer(suite.ctx, int(taskId), TechnicianUser.Id)
+	suite.NoError(err)
+	suite.Equal(int(taskId), workLog.TaskId)
+	suite.Equal(TechnicianUser.Id, workLog.LoggedBy.Id)
+	suite.Equal("", workLog.EndedAt)
+
+	_, err = repo.StartTimer(suite.ctx, int(otherTaskId), TechnicianUser.Id)
+	suite.Equal(ErrTimerAlreadyRunning, err)
+
+	_, err = repo.StopTimer(suite.ctx, entity.TimerStopRequest{TaskId: int(otherTaskId), UserId: TechnicianUser.Id})
+	suite.Equal(ErrNoRunningTimer, err)
+
+	workLog, err = repo.StopTimer(suite.ctx, entity.TimerStopRequest{TaskId: int(taskId), UserId: TechnicianUser.Id, Note: "done"})
+	suite.NoError(err)
+	suite.NotEmpty(workLog.EndedAt)
+	suite.Equal("done", workLog.Note)
+
+	_, err = repo.StartTimer(suite.ctx, int(otherTaskId), TechnicianUser.Id)
+	suite.NoError(err)
+
+	_, err = repo.StartTimer(suite.ctx, 0, ManagerUser.Id)
+	suite.Equal(ErrNoTaskInResult, err)
+}
+
+func (suite *WorkLogsTestSuite) TestStartTimersAtOnce() {
+	const timers = 4
+
+	ids := make([]int, timers)
+	for i := range ids {
+		taskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{
+			Title:       "test timers at once",
+			Description: "test timers at once for test",
+			UserId:      TechnicianUser.Id,
+		})
+		suite.Require().NoError(err)
+		ids[i] = int(taskId)
+	}
+
+	errs := make([]error, timers)
+
+	var wg sync.WaitGroup
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = repo.StartTimer(suite.ctx, ids[i], TechnicianUser.Id)
+		}(i)
+	}
+	wg.Wait()
+
+	started := 0
+	for _, err := range errs {
+		if err == nil {
+			started++
+			continue
+		}
+		suite.Equal(ErrTimerAlreadyRunning, err)
+	}
+	suite.Equal(1, started)
+}
+
+func (suite *WorkLogsTestSuite) TestRunningTimerIsUnique() {
+	taskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{
+		Title:       "test unique timer",
+		Description: "test unique timer for test",
+		UserId:      TechnicianUser.Id,
+	})
+	suite.Require().NoError(err)
+
+	_, err = repo.StartTimer(suite.ctx, int(taskId), TechnicianUser.Id)
+	suite.Require().NoError(err)
+
+	// the database rejects a second running timer even without the checks of
+	// the repository
+	_, err = repo.(*repository).db.ExecContext(suite.ctx, `INSERT INTO work_logs (task_id, user_id, started_at) VALUES (?, ?, ?)`, taskId, TechnicianUser.Id, time.Now().UTC())
+
+	var duplicate *ErrDuplicate
+	suite.ErrorAs(err, &duplicate)
+
+	// stopped timers aren't unique
+	for i := 0; i < 2; i++ {
+		_, err = repo.(*repository).db.ExecContext(suite.ctx, `INSERT INTO work_logs (task_id, user_id, started_at, ended_at) VALUES (?, ?, ?, ?)`, taskId, TechnicianUser.Id, time.Now().UTC(), time.Now().UTC())
+		suite.NoError(err)
+	}
+}
+
+func (suite *WorkLogsTestSuite) TestCreateWorkLogsAtOnce() {
+	const workLogs = 4
+
+	taskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{
+		Title:       "test work logs at once",
+		Description: "test work logs at once for test",
+		UserId:      TechnicianUser.Id,
+	})
+	suite.Require().NoError(err)
+
+	startedAt := time.Now().UTC().Add(-4 * time.Hour).Truncate(time.Second)
+
+	errs := make([]error, workLogs)
+
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// every entry overlaps the others
+			_, errs[i] = repo.CreateWorkLog(suite.ctx, entity.WorkLogRequest{
+				TaskId:    int(taskId),
+				UserId:    TechnicianUser.Id,
+				StartedAt: startedAt.Add(time.Duration(i) * time.Minute),
+				EndedAt:   startedAt.Add(time.Hour),
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		suite.Equal(ErrWorkLogOverlap, err)
+	}
+	suite.Equal(1, created)
+}
+
+func (suite *WorkLogsTestSuite) TestCreateWorkLog() {
+	taskId, err := repo.CreateTask(suite.ctx, entity.TaskRequest{
+		Title:       "test work log",
+		Description: "test work log for test",
+		UserId:      TechnicianUser.Id,
+	})
+	suite.NoError(err)
+
+	startedAt := time.Now().UTC().Add(-4 * time.Hour).Truncate(time.Second)
+
+	cases := map[string]struct {
+		workLog entity.WorkLogRequest
+		err     error
+	}{
+		"1 - Should register work log": {
+			workLog: entity.WorkLogRequest{
+				TaskId:    int(taskId),
+				UserId:    TechnicianUser.Id,
+				StartedAt: startedAt,
+				EndedAt:   startedAt.Add(time.Hour),
+				Note:      "first visit",
+			},
+			err: nil,
+		},
+		"2 - Shouldn't register - overlaps previous entry": {
+			workLog: entity.WorkLogRequest{
+				TaskId:    int(taskId),
+				UserId:    TechnicianUser.Id,
+				StartedAt: startedAt.Add(30 * time.Minute),
+				EndedAt:   startedAt.Add(90 * time.Minute),
+			},
+			err: ErrWorkLogOverlap,
+		},
+		"3 - Should register adjacent work log": {
+			workLog: entity.WorkLogRequest{
+				TaskId:    int(taskId),
+				UserId:    TechnicianUser.Id,
+				StartedAt: startedAt.Add(time.Hour),
+				EndedAt:   startedAt.Add(2 * time.Hour),
+			},
+			err: nil,
+		},
+		"4 - Shouldn't register - is not same user task": {
+			workLog: entity.WorkLogRequest{
+				TaskId:    int(taskId),
+				UserId:    ManagerUser.Id,
+				StartedAt: startedAt,
+				EndedAt:   startedAt.Add(time.Hour),
+			},
+			err: ErrNoTaskInResult,
+		},
+	}
+
+	keys := make([]string, 0, len(cases))
+	for v := range cases {
+		keys = append(keys, v)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		suite.Run(key, func() {
+			workLog, err := repo.CreateWorkLog(suite.ctx, cases[key].workLog)
+			if cases[key].err != nil {
+				suite.Equal(cases[key].err, err)
+				return
+			}
+
+			suite.NoError(err)
+			suite.Equal(int64(3600), workLog.DurationSeconds)
+			suite.Equal(cases[key].workLog.Note, workLog.Note)
+		})
+	}
+
+	task, err := repo.GetTaskById(suite.ctx, int(taskId), TechnicianUser.Id, entity.ScopeOwn)
+	suite.NoError(err)
+	suite.Equal(int64(7200), task.TimeSpentSeconds)
+
+	workLogs, err := repo.GetWorkLogs(suite.ctx, int(taskId), ManagerUser.Id, entity.ScopeTeam)
+	suite.NoError(err)
+	suite.Len(workLogs, 2)
+}
@@ -0,0 +1,71 @@
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Backoff doubles the wait after every failed attempt, from Initial up to Max.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// Delay is the wait after the attempt (starting at 0), jittered between half
+// and the full value so clients failing together don't retry together.
+func (b Backoff) Delay(attempt int) time.Duration {
+	d := b.Initial
+	for i := 0; i < attempt && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// Policy tells which errors are retried and how many times.
+type Policy struct {
+	Backoff
+	// Attempts is the maximum number of calls, including the first one.
+	Attempts int
+	// Retryable reports whether the error is transient.
+	Retryable func(error) bool
+}
+
+// Do calls fn until it succeeds, returns an error that isn't retryable or the
+// attempts run out. The last error of fn is returned, or the error of ctx if
+// it's done while waiting.
+func Do(ctx context.Context, p Policy, fn func() error) error {
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil || !p.Retryable(err) || attempt+1 >= p.Attempts {
+			return err
+		}
+
+		if waitErr := Wait(ctx, p.Delay(attempt)); waitErr != nil {
+			return waitErr
+		}
+	}
+}
+
+// Wait sleeps for d or until ctx is done.
+func Wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errTransient = errors.New("transient")
+	errPermanent = errors.New("permanent")
+)
+
+func policy(attempts int) Policy {
+	return Policy{
+		Backoff:   Backoff{Initial: time.Millisecond, Max: 2 * time.Millisecond},
+		Attempts:  attempts,
+		Retryable: func(err error) bool { return errors.Is(err, errTransient) },
+	}
+}
+
+func TestDo(t *testing.T) {
+	cases := map[string]struct {
+		errs  []error
+		calls int
+		err   error
+	}{
+		"1 - Should return after the first success": {
+			errs:  []error{nil},
+			calls: 1,
+		},
+		"2 - Should retry transient errors": {
+			errs:  []error{errTransient, errTransient, nil},
+			calls: 3,
+		},
+		"3 - Should not retry other errors": {
+			errs:  []error{errPermanent, nil},
+			calls: 1,
+			err:   errPermanent,
+		},
+		"4 - Should return the last error when the attempts run out": {
+			errs:  []error{errTransient, errTransient, errTransient, nil},
+			calls: 3,
+			err:   errTransient,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var calls int
+
+			err := Do(context.Background(), policy(3), func() error {
+				calls++
+				return tc.errs[calls-1]
+			})
+
+			require.Equal(t, tc.calls, calls)
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestDoCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p := policy(3)
+	p.Initial, p.Max = time.Hour, time.Hour
+
+	var calls int
+	err := Do(ctx, p, func() error {
+		calls++
+		return errTransient
+	})
+
+	require.Equal(t, 1, calls)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Initial: 100 * time.Millisecond, Max: time.Second}
+
+	cases := map[string]struct {
+		attempt int
+		max     time.Duration
+	}{
+		"1 - Should start at the initial delay": {attempt: 0, max: 100 * time.Millisecond},
+		"2 - Should double every attempt":       {attempt: 2, max: 400 * time.Millisecond},
+		"3 - Should stop at the max delay":      {attempt: 10, max: time.Second},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				d := b.Delay(tc.attempt)
+				require.GreaterOrEqual(t, d, tc.max/2)
+				require.LessOrEqual(t, d, tc.max)
+			}
+		})
+	}
+}
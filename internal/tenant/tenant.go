@@ -0,0 +1,20 @@
+// Package tenant carries the organization of a request in its context. The
+// api sets it from the token of the user and the repository scopes every query
+// of tenant data to it, so one organization never reads another's data.
+package tenant
+
+import "context"
+
+type organizationKey struct{}
+
+// WithOrganization returns a copy of ctx scoped to the organization.
+func WithOrganization(ctx context.Context, organizationId int) context.Context {
+	return context.WithValue(ctx, organizationKey{}, organizationId)
+}
+
+// Organization returns the organization ctx is scoped to, false when it isn't
+// scoped to any.
+func Organization(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(organizationKey{}).(int)
+	return id, ok && id > 0
+}
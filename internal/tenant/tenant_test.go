@@ -0,0 +1,36 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganization(t *testing.T) {
+	cases := map[string]struct {
+		ctx context.Context
+		id  int
+		ok  bool
+	}{
+		"1 - Should return the organization": {
+			ctx: WithOrganization(context.Background(), 7),
+			id:  7,
+			ok:  true,
+		},
+		"2 - Shouldn't return - not scoped": {
+			ctx: context.Background(),
+		},
+		"3 - Shouldn't return - no organization in the token": {
+			ctx: WithOrganization(context.Background(), 0),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			id, ok := Organization(tc.ctx)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.id, id)
+		})
+	}
+}
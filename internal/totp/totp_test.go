@@ -0,0 +1,74 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA-1 secret of the test vectors of RFC 6238.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// the 8 digits codes of the RFC, truncated to 6 digits
+	cases := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range cases {
+		code, err := Code(rfcSecret, Step(time.Unix(unix, 0)))
+		require.NoError(t, err)
+		require.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	code, err := Code(rfcSecret, Step(now))
+	require.NoError(t, err)
+
+	step, ok := Validate(rfcSecret, code, now)
+	require.True(t, ok)
+	require.Equal(t, Step(now), step)
+
+	// a step of skew either way
+	step, ok = Validate(rfcSecret, code, now.Add(Period))
+	require.True(t, ok)
+	require.Equal(t, Step(now), step)
+
+	_, ok = Validate(rfcSecret, code, now.Add(-Period))
+	require.True(t, ok)
+
+	_, ok = Validate(rfcSecret, code, now.Add(2*Period))
+	require.False(t, ok)
+
+	_, ok = Validate(rfcSecret, "12345", now)
+	require.False(t, ok)
+
+	_, ok = Validate("not base32!", code, now)
+	require.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	u, err := url.Parse(URI("api-tasks", "lucas simao", secret))
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/api-tasks:lucas simao", u.Path)
+	require.Equal(t, secret, u.Query().Get("secret"))
+	require.Equal(t, "api-tasks", u.Query().Get("issuer"))
+	require.Equal(t, "6", u.Query().Get("digits"))
+}
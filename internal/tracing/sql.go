@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"strings"
+
+	"github.com/XSAM/otelsql"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+var (
+	sqlLiterals   = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*"|\b\d+(?:\.\d+)?\b`)
+	sqlInLists    = regexp.MustCompile(`(?i)\bIN\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	sqlWhitespace = regexp.MustCompile(`\s+`)
+)
+
+// SanitizeSQL replaces the literals of query by placeholders and collapses
+// its whitespace. IN lists expanded by sqlx.In become a single placeholder,
+// so the statement doesn't change with the number of values.
+func SanitizeSQL(query string) string {
+	query = sqlLiterals.ReplaceAllString(query, "?")
+	query = sqlInLists.ReplaceAllString(query, "IN (?)")
+	query = sqlWhitespace.ReplaceAllString(query, " ")
+
+	return strings.TrimSpace(query)
+}
+
+// SQLOptions instruments a database/sql driver with a span for every query,
+// named after its operation and carrying the sanitized statement. Arguments
+// are never recorded.
+func SQLOptions(system attribute.KeyValue) []otelsql.Option {
+	return []otelsql.Option{
+		otelsql.WithAttributes(system),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableQuery:         true,
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitConnPrepare:      true,
+			OmitRows:             true,
+		}),
+		otelsql.WithSpanNameFormatter(func(_ context.Context, method otelsql.Method, query string) string {
+			if operation := sqlOperation(query); operation != "" {
+				return operation
+			}
+			return string(method)
+		}),
+		otelsql.WithAttributesGetter(func(_ context.Context, _ otelsql.Method, query string, _ []driver.NamedValue) []attribute.KeyValue {
+			if query == "" {
+				return nil
+			}
+			return []attribute.KeyValue{
+				semconv.DBStatement(SanitizeSQL(query)),
+				semconv.DBOperation(sqlOperation(query)),
+			}
+		}),
+	}
+}
+
+func sqlOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.ToUpper(fields[0])
+}
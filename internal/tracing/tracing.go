@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/lucas-simao/api-tasks"
+	serviceName         = "api-tasks"
+)
+
+// Exporters accepted by Setup, OTLP reads its endpoint from the standard
+// OTEL_EXPORTER_OTLP_* variables.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Setup installs the global tracer provider exporting spans in batches to
+// exporter. The returned func flushes the pending spans and must be called
+// before the process exits.
+func Setup(ctx context.Context, exporter string) (func(context.Context) error, error) {
+	var (
+		spanExporter sdktrace.SpanExporter
+		err          error
+	)
+
+	switch exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterOTLP:
+		spanExporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown traces exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error to create %s exporter: %w", exporter, err)
+	}
+
+	provider, err := install(ctx, sdktrace.WithBatcher(spanExporter))
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.Shutdown, nil
+}
+
+// SetupInMemory installs a global tracer provider keeping the spans in memory,
+// spans are exported as soon as they end so tests can read them right away.
+func SetupInMemory() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+
+	if _, err := install(context.Background(), sdktrace.WithSyncer(exporter)); err != nil {
+		panic(err)
+	}
+
+	return exporter
+}
+
+func install(ctx context.Context, processor sdktrace.TracerProviderOption) (*sdktrace.TracerProvider, error) {
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the defaults
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(processor, sdktrace.WithResource(res))
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider, nil
+}
+
+// Tracer returns a tracer of the global provider, so spans started before
+// Setup are still exported once it runs.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// End records err on span and ends it. Errors the client caused, such as a
+// task not found, are kept as events and don't mark the span as failed.
+func End(span trace.Span, err error) {
+	defer span.End()
+
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) && appErr.Kind != apperror.KindInternal {
+		return
+	}
+
+	span.SetStatus(codes.Error, err.Error())
+}
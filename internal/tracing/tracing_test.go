@@ -0,0 +1,139 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lucas-simao/api-tasks/internal/apperror"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func attr(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestSanitizeSQL(t *testing.T) {
+	cases := map[string]struct {
+		query    string
+		expected string
+	}{
+		"1 - Should keep the placeholders": {
+			query: `
+				UPDATE tasks
+				SET finished_at = now()
+				WHERE id = ?`,
+			expected: "UPDATE tasks SET finished_at = now() WHERE id = ?",
+		},
+		"2 - Should replace the literals": {
+			query:    `SELECT COALESCE(name, '') FROM users WHERE username = 'lucas' AND id = 10 AND title = "secret"`,
+			expected: "SELECT COALESCE(name, ?) FROM users WHERE username = ? AND id = ? AND title = ?",
+		},
+		"3 - Should collapse in lists": {
+			query:    `SELECT id FROM users WHERE username IN (?, ?, ?)`,
+			expected: "SELECT id FROM users WHERE username IN (?)",
+		},
+		"4 - Should keep identifiers with digits": {
+			query:    `SELECT t1.id FROM tasks t1`,
+			expected: "SELECT t1.id FROM tasks t1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, SanitizeSQL(tc.query))
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	exporter := SetupInMemory()
+
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/tasks/:id", func(c echo.Context) error {
+		_, span := Tracer().Start(c.Request().Context(), "child")
+		span.End()
+
+		switch c.Param("id") {
+		case "0":
+			return echo.NewHTTPError(http.StatusNotFound)
+		case "500":
+			return errors.New("database down")
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	cases := map[string]struct {
+		path   string
+		name   string
+		status int64
+		failed bool
+	}{
+		"1 - Should name the span after the route": {path: "/tasks/1", name: "GET /tasks/:id", status: http.StatusOK},
+		"2 - Should not fail on client errors":     {path: "/tasks/0", name: "GET /tasks/:id", status: http.StatusNotFound},
+		"3 - Should fail on server errors":         {path: "/tasks/500", name: "GET /tasks/:id", status: http.StatusInternalServerError, failed: true},
+		"4 - Should group unmatched routes":        {path: "/unknown", name: "GET unmatched", status: http.StatusNotFound},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			exporter.Reset()
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			e.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := exporter.GetSpans()
+			server := spans[len(spans)-1]
+
+			require.Equal(t, tc.name, server.Name)
+			require.Equal(t, tc.status, attr(server, "http.status_code").AsInt64())
+			require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+			require.Equal(t, tc.failed, server.Status.Code == codes.Error)
+
+			if len(spans) > 1 {
+				require.Equal(t, server.SpanContext.SpanID(), spans[0].Parent.SpanID())
+			}
+		})
+	}
+}
+
+func TestEnd(t *testing.T) {
+	exporter := SetupInMemory()
+
+	cases := map[string]struct {
+		err    error
+		status codes.Code
+		events int
+	}{
+		"1 - Should end without error":    {status: codes.Unset},
+		"2 - Should keep client errors":   {err: apperror.NotFound("task_not_found", "task not found"), status: codes.Unset, events: 1},
+		"3 - Should fail on other errors": {err: errors.New("database down"), status: codes.Error, events: 1},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			exporter.Reset()
+
+			_, span := Tracer().Start(context.Background(), name)
+			End(span, tc.err)
+
+			spans := exporter.GetSpans()
+			require.Len(t, spans, 1)
+			require.Equal(t, tc.status, spans[0].Status.Code)
+			require.Len(t, spans[0].Events, tc.events)
+		})
+	}
+}
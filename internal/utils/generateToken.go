@@ -7,10 +7,11 @@ import (
 
 func GenerateToken(secret string, user entity.User) (string, error) {
 	claims := &entity.JwtCustomClaims{
-		Id:       user.Id,
-		Name:     user.Name,
-		Username: user.Username,
-		CodeRole: user.CodeRole,
+		Id:             user.Id,
+		Name:           user.Name,
+		Username:       user.Username,
+		CodeRole:       user.CodeRole,
+		OrganizationId: user.OrganizationId,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -1,39 +1,252 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
-	"github.com/joho/godotenv"
 	"github.com/lucas-simao/api-tasks/internal/api"
+	"github.com/lucas-simao/api-tasks/internal/config"
+	"github.com/lucas-simao/api-tasks/internal/domain/apikeys"
+	"github.com/lucas-simao/api-tasks/internal/domain/authz"
+	"github.com/lucas-simao/api-tasks/internal/domain/health"
+	"github.com/lucas-simao/api-tasks/internal/domain/mfa"
+	"github.com/lucas-simao/api-tasks/internal/domain/organizations"
+	"github.com/lucas-simao/api-tasks/internal/domain/reports"
+	"github.com/lucas-simao/api-tasks/internal/domain/sso"
+	"github.com/lucas-simao/api-tasks/internal/domain/stats"
 	"github.com/lucas-simao/api-tasks/internal/domain/tasks"
+	"github.com/lucas-simao/api-tasks/internal/domain/teams"
 	"github.com/lucas-simao/api-tasks/internal/domain/users"
 	"github.com/lucas-simao/api-tasks/internal/gateway/notifications"
+	"github.com/lucas-simao/api-tasks/internal/gateway/oidc"
+	"github.com/lucas-simao/api-tasks/internal/logging"
+	"github.com/lucas-simao/api-tasks/internal/metrics"
 	"github.com/lucas-simao/api-tasks/internal/repository"
+	"github.com/lucas-simao/api-tasks/internal/tracing"
+	"github.com/lucas-simao/api-tasks/scripts/migrations"
 )
 
 func main() {
-	var isProduction = os.Getenv("IS_PRODUCTION")
-	if isProduction == "" {
-		err := godotenv.Load(".env")
-		if err != nil {
-			log.Panic("Error to load .env in the root directory")
+	configFile := flag.String("config", "", "optional YAML file with the configuration")
+	flag.Parse()
+
+	c, sources, err := config.Load(config.Options{YAMLFile: *configFile, DotEnvFile: ".env"})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error to load the configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	if args := flag.Args(); len(args) > 0 {
+		switch {
+		case len(args) == 2 && args[0] == "config" && args[1] == "print":
+			os.Exit(printConfig(c, sources))
+		case len(args) == 2 && args[0] == "backup":
+			os.Exit(backup(c, args[1]))
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown command %q, expected \"config print\" or \"backup <file>\"\n", strings.Join(args, " "))
+			os.Exit(2)
 		}
 	}
 
+	if err := c.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Logging
+	logger, err := logging.New(os.Stdout, logging.Config{Level: c.LogLevel, Format: c.LogFormat})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error to setup logging: %v\n", err)
+		os.Exit(1)
+	}
+	// libraries using the log package are written by logger too
+	slog.SetDefault(logger)
+
+	if err := run(c, logger); err != nil {
+		logger.Error("api stopped", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+// printConfig writes the configuration with secrets masked, invalid values
+// are reported after it.
+func printConfig(c config.Config, sources map[string]config.Source) int {
+	if err := config.Print(os.Stdout, c, sources); err != nil {
+		fmt.Fprintf(os.Stderr, "Error to print the configuration: %v\n", err)
+		return 1
+	}
+
+	if err := c.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "\nInvalid configuration: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// backup writes a snapshot of the SQLite database to path, the api can keep
+// running while it's taken.
+func backup(c config.Config, path string) int {
+	if err := c.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := repository.Backup(ctx, repository.Config{
+		Driver:         c.DatabaseDriver,
+		DataSource:     c.DatabaseURL,
+		ConnectTimeout: c.DBConnectTimeout,
+	}, path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error to back up the database: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Database backed up to %s\n", path)
+
+	return 0
+}
+
+func run(c config.Config, logger *slog.Logger) error {
+	// Kubernetes sends SIGTERM before killing the pod
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Tracing
+	shutdownTracing, err := tracing.Setup(ctx, c.TracesExporter)
+	if err != nil {
+		return fmt.Errorf("error to setup tracing: %w", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Database
-	repo := repository.New()
+	repo, err := repository.New(ctx, repository.Config{
+		Driver:          c.DatabaseDriver,
+		DataSource:      c.DatabaseURL,
+		MaxOpenConns:    c.DBMaxOpenConns,
+		MaxIdleConns:    c.DBMaxIdleConns,
+		ConnMaxLifetime: c.DBConnMaxLifetime,
+		ConnMaxIdleTime: c.DBConnMaxIdleTime,
+		ConnectTimeout:  c.DBConnectTimeout,
+		Logger:          logger,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			logger.Error("error to close database", slog.Any("error", err))
+		}
+	}()
+
+	// migrations the database should have applied, checked by readiness
+	up, err := migrations.Up(c.DatabaseDriver)
+	if err != nil {
+		return err
+	}
+
+	// Metrics
+	metrics := metrics.New()
+	metrics.CollectDB(repo.Stats)
 
-	notifications := notifications.New()
+	notifications := metrics.Notifications(notifications.New(logger))
 
 	// Domains
-	tasks := tasks.New(repo, notifications)
-	users := users.New(repo)
+	stats := stats.New(repo)
+	tasks := tasks.New(repo, notifications, metrics, logger, stats)
+	mfa := mfa.New(repo, mfa.Config{RequiredForManagers: c.MFARequiredForManagers}, c.JWTSecret)
+	users := users.New(repo, mfa, c.JWTSecret)
+	reports := reports.New(repo)
+	teams := teams.New(repo)
+	organizations := organizations.New(repo)
+	authz := authz.New(repo)
+	apiKeys := apikeys.New(repo)
+	health := health.New(repo, up)
+
+	// Single sign-on, disabled without an issuer
+	var singleSignOn sso.Service
+	if c.OIDCIssuer != "" {
+		provider := oidc.New(oidc.Config{
+			Issuer:       c.OIDCIssuer,
+			ClientId:     c.OIDCClientId,
+			ClientSecret: c.OIDCClientSecret,
+			RedirectURL:  c.OIDCRedirectURL,
+			GroupsClaim:  c.OIDCGroupsClaim,
+		}, nil)
+
+		singleSignOn = sso.New(repo, provider, mfa, sso.Config{
+			OrganizationId:  c.OIDCOrganizationId,
+			ManagerGroup:    c.OIDCManagerGroup,
+			TechnicianGroup: c.OIDCTechnicianGroup,
+		}, c.JWTSecret)
+	}
 
 	// Api
-	a := api.New(api.Services{
-		Tasks: tasks,
-		Users: users,
+	a, err := api.New(api.Services{
+		Tasks:         tasks,
+		Users:         users,
+		MFA:           mfa,
+		Reports:       reports,
+		Stats:         stats,
+		Teams:         teams,
+		Organizations: organizations,
+		Authz:         authz,
+		ApiKeys:       apiKeys,
+		SSO:           singleSignOn,
+		Health:        health,
+		Metrics:       metrics,
+		Logger:        logger,
+	}, api.Config{
+		JWTSecret:         c.JWTSecret,
+		OpenAPIValidation: c.OpenAPIValidation,
+		UnversionedSunset: c.UnversionedSunset,
 	})
-	api.Start(a)
+	if err != nil {
+		return err
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- api.Start(a, c.Port, logger)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down", slog.Duration("timeout", c.ShutdownTimeout))
+
+	// readiness fails first so no new requests are routed here
+	health.ShutDown()
+	time.Sleep(c.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), c.ShutdownTimeout)
+	defer cancel()
+
+	// the database is closed only after requests and notifications are done
+	err = a.Shutdown(shutdownCtx)
+	if err != nil {
+		err = fmt.Errorf("error to finish in-flight requests: %w", err)
+	}
+
+	if err := errors.Join(err, tasks.Drain(shutdownCtx), <-errs); err != nil {
+		return err
+	}
+
+	logger.Info("api stopped")
+
+	return nil
 }
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS work_logs (
+  id INT(11) NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  task_id INT(11) NOT NULL,
+  user_id INT(11) NOT NULL,
+  started_at TIMESTAMP NOT NULL,
+  ended_at TIMESTAMP NULL DEFAULT NULL,
+  note VARCHAR(500) NOT NULL DEFAULT '',
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (task_id) REFERENCES tasks (id),
+  FOREIGN KEY (user_id) REFERENCES users (id),
+  INDEX idx_work_logs_user_period (user_id, started_at, ended_at)
+);
+
+INSERT INTO migrations VALUES ('0002.up.sql', NOW());
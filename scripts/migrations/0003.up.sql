@@ -0,0 +1,6 @@
+ALTER TABLE tasks ADD COLUMN finished_by_user_id INT(11) DEFAULT NULL AFTER deleted_by_user_id;
+ALTER TABLE tasks ADD CONSTRAINT fk_tasks_finished_by FOREIGN KEY (finished_by_user_id) REFERENCES users (id);
+
+UPDATE tasks SET finished_by_user_id = created_by_user_id WHERE finished_at IS NOT NULL;
+
+INSERT INTO migrations VALUES ('0003.up.sql', NOW());
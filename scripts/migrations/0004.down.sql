@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams;
+
+DELETE FROM migrations WHERE name = '0004.up.sql';
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS teams (
+  id INT(11) NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  name VARCHAR(50) NOT NULL UNIQUE,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS team_members (
+  team_id INT(11) NOT NULL,
+  user_id INT(11) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (team_id, user_id),
+  FOREIGN KEY (team_id) REFERENCES teams (id),
+  FOREIGN KEY (user_id) REFERENCES users (id),
+  INDEX idx_team_members_user (user_id)
+);
+
+-- existing users share one team, so managers keep seeing the tasks they saw
+INSERT INTO teams (name) VALUES ('default');
+INSERT INTO team_members (team_id, user_id) SELECT teams.id, users.id FROM teams, users;
+
+INSERT INTO migrations VALUES ('0004.up.sql', NOW());
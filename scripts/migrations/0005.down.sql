@@ -0,0 +1,13 @@
+ALTER TABLE teams DROP FOREIGN KEY fk_teams_organization;
+ALTER TABLE teams DROP INDEX name, ADD UNIQUE KEY name (name);
+ALTER TABLE teams DROP COLUMN organization_id;
+
+ALTER TABLE tasks DROP FOREIGN KEY fk_tasks_organization;
+ALTER TABLE tasks DROP COLUMN organization_id;
+
+ALTER TABLE users DROP FOREIGN KEY fk_users_organization;
+ALTER TABLE users DROP COLUMN organization_id;
+
+DROP TABLE IF EXISTS organizations;
+
+DELETE FROM migrations WHERE name = '0005.up.sql';
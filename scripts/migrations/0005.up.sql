@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS organizations (
+  id INT(11) NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  invite_code VARCHAR(64) NOT NULL UNIQUE,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- existing data belongs to one organization, managers should rotate its invite code
+INSERT INTO organizations (name, invite_code) VALUES ('default', LEFT(SHA2(CONCAT(UUID(), RAND()), 256), 32));
+
+ALTER TABLE users ADD COLUMN organization_id INT(11) DEFAULT NULL AFTER user_role_id;
+UPDATE users SET organization_id = (SELECT id FROM organizations WHERE name = 'default');
+ALTER TABLE users MODIFY organization_id INT(11) NOT NULL;
+ALTER TABLE users ADD CONSTRAINT fk_users_organization FOREIGN KEY (organization_id) REFERENCES organizations (id);
+
+ALTER TABLE tasks ADD COLUMN organization_id INT(11) DEFAULT NULL AFTER id;
+UPDATE tasks SET organization_id = (SELECT organization_id FROM users WHERE users.id = tasks.created_by_user_id);
+ALTER TABLE tasks MODIFY organization_id INT(11) NOT NULL;
+ALTER TABLE tasks ADD CONSTRAINT fk_tasks_organization FOREIGN KEY (organization_id) REFERENCES organizations (id);
+
+-- team names are unique in the organization
+ALTER TABLE teams ADD COLUMN organization_id INT(11) DEFAULT NULL AFTER id;
+UPDATE teams SET organization_id = (SELECT id FROM organizations WHERE name = 'default');
+ALTER TABLE teams MODIFY organization_id INT(11) NOT NULL;
+ALTER TABLE teams DROP INDEX name, ADD UNIQUE KEY name (organization_id, name);
+ALTER TABLE teams ADD CONSTRAINT fk_teams_organization FOREIGN KEY (organization_id) REFERENCES organizations (id);
+
+INSERT INTO migrations VALUES ('0005.up.sql', NOW());
@@ -0,0 +1,29 @@
+-- service users act for the api keys, they have no permissions of their own
+INSERT INTO users_role (id, name, code) VALUES (4, 'service', 30);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+  id INT(11) NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  organization_id INT(11) NOT NULL,
+  user_id INT(11) NOT NULL UNIQUE,
+  name VARCHAR(50) NOT NULL,
+  key_hash CHAR(64) NOT NULL UNIQUE,
+  created_by_user_id INT(11) NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  last_used_at TIMESTAMP NULL DEFAULT NULL,
+  revoked_at TIMESTAMP NULL DEFAULT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (organization_id) REFERENCES organizations (id),
+  FOREIGN KEY (user_id) REFERENCES users (id),
+  FOREIGN KEY (created_by_user_id) REFERENCES users (id)
+);
+
+CREATE TABLE IF NOT EXISTS api_key_permissions (
+  api_key_id INT(11) NOT NULL,
+  permission_id INT(11) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (api_key_id, permission_id),
+  FOREIGN KEY (api_key_id) REFERENCES api_keys (id),
+  FOREIGN KEY (permission_id) REFERENCES permissions (id)
+);
+
+INSERT INTO migrations VALUES ('0007.up.sql', NOW());
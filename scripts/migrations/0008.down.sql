@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_identities;
+
+-- the users provisioned by the identity provider stay, without a password they can't sign in
+DELETE FROM migrations WHERE name = '0008.up.sql';
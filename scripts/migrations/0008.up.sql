@@ -0,0 +1,12 @@
+-- users signed in with an identity provider, by the issuer and the subject of
+-- their id tokens
+CREATE TABLE IF NOT EXISTS user_identities (
+  issuer VARCHAR(255) NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  user_id INT(11) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (issuer, subject),
+  FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+INSERT INTO migrations VALUES ('0008.up.sql', NOW());
@@ -0,0 +1,23 @@
+-- the totp secret of a user, enabled once a code of it was verified
+CREATE TABLE IF NOT EXISTS user_mfa (
+  user_id INT(11) NOT NULL PRIMARY KEY,
+  secret VARCHAR(64) NOT NULL,
+  enabled_at TIMESTAMP NULL DEFAULT NULL,
+  last_step BIGINT NOT NULL DEFAULT 0,
+  failed_attempts INT(11) NOT NULL DEFAULT 0,
+  failed_at TIMESTAMP NULL DEFAULT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+-- recovery codes sign in without the authenticator, once each
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+  user_id INT(11) NOT NULL,
+  code_hash CHAR(64) NOT NULL,
+  used_at TIMESTAMP NULL DEFAULT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, code_hash),
+  FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+INSERT INTO migrations VALUES ('0009.up.sql', NOW());
@@ -0,0 +1,8 @@
+DELETE FROM users_role_permissions WHERE user_role_id = 2 AND permission_id = 17;
+
+INSERT INTO users_role_permissions (user_role_id, permission_id) VALUES (2, 13);
+
+DELETE FROM api_key_permissions WHERE permission_id = 17;
+DELETE FROM permissions WHERE id = 17;
+
+DELETE FROM migrations WHERE name = '0010.up.sql';
@@ -0,0 +1,5 @@
+ALTER TABLE work_logs
+  DROP INDEX uq_work_logs_running_user,
+  DROP COLUMN running_user_id;
+
+DELETE FROM migrations WHERE name = '0011.up.sql';
@@ -0,0 +1,13 @@
+-- users have one running timer at most, timers started at once can't both be
+-- inserted. Timers already running twice are stopped where they started.
+UPDATE work_logs wl
+JOIN (SELECT user_id, MAX(id) AS id FROM work_logs WHERE ended_at IS NULL GROUP BY user_id) latest ON latest.user_id = wl.user_id
+SET wl.ended_at = wl.started_at
+WHERE wl.ended_at IS NULL AND wl.id <> latest.id;
+
+-- MySQL has no partial indexes, the column is only set while running
+ALTER TABLE work_logs
+  ADD COLUMN running_user_id INT(11) GENERATED ALWAYS AS (IF(ended_at IS NULL, user_id, NULL)) STORED,
+  ADD UNIQUE INDEX uq_work_logs_running_user (running_user_id);
+
+INSERT INTO migrations VALUES ('0011.up.sql', NOW());
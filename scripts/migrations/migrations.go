@@ -0,0 +1,54 @@
+// Package migrations embeds the sql files of the database, so the api knows
+// which migrations a running database should have applied, and the repository
+// applies them to SQLite databases. The MySQL files are in this directory, the
+// files of other databases in a directory named after the driver.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+//go:embed *.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+const upSuffix = ".up.sql"
+
+// FS returns the migrations of the driver, mysql, postgres or sqlite.
+func FS(driver string) (fs.FS, error) {
+	switch driver {
+	case "", "mysql":
+		return files, nil
+	case "postgres", "sqlite":
+		return fs.Sub(files, driver)
+	default:
+		return nil, fmt.Errorf("no migrations for the database driver %q", driver)
+	}
+}
+
+// Up returns the names of the up migrations of the driver in the order they
+// run, the names are the ones recorded in the migrations table.
+func Up(driver string) ([]string, error) {
+	fsys, err := FS(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), upSuffix) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS migrations;
+DROP TABLE IF EXISTS tasks;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS users_role;
+DROP FUNCTION IF EXISTS set_updated_at;
@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS users_role (
+  id INTEGER NOT NULL PRIMARY KEY,
+  name VARCHAR(50) NOT NULL,
+  code INTEGER NOT NULL,
+  updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO users_role (id, name, code) VALUES (1, 'visitor', 0), (2, 'manager', 10), (3, 'technician', 20);
+
+CREATE TABLE IF NOT EXISTS users (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(50) NOT NULL,
+  username VARCHAR(30) NOT NULL UNIQUE,
+  password VARCHAR(255) NOT NULL,
+  user_role_id INTEGER NOT NULL REFERENCES users_role (id),
+  updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tasks (
+  id SERIAL PRIMARY KEY,
+  title VARCHAR(100) NOT NULL,
+  description VARCHAR(2500) NOT NULL,
+  created_by_user_id INTEGER NOT NULL REFERENCES users (id),
+  deleted_by_user_id INTEGER DEFAULT NULL REFERENCES users (id),
+  updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  finished_at TIMESTAMPTZ DEFAULT NULL,
+  deleted_at TIMESTAMPTZ DEFAULT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- MySQL's ON UPDATE CURRENT_TIMESTAMP
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+  NEW.updated_at = CURRENT_TIMESTAMP;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_role_updated_at BEFORE UPDATE ON users_role FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE TRIGGER users_updated_at BEFORE UPDATE ON users FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE TRIGGER tasks_updated_at BEFORE UPDATE ON tasks FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS migrations (
+  name VARCHAR(100) NOT NULL PRIMARY KEY,
+  applied_at TIMESTAMPTZ NOT NULL
+);
+
+INSERT INTO migrations VALUES ('0001.up.sql', NOW());
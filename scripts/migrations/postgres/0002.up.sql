@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS work_logs (
+  id SERIAL PRIMARY KEY,
+  task_id INTEGER NOT NULL REFERENCES tasks (id),
+  user_id INTEGER NOT NULL REFERENCES users (id),
+  started_at TIMESTAMPTZ NOT NULL,
+  ended_at TIMESTAMPTZ NULL DEFAULT NULL,
+  note VARCHAR(500) NOT NULL DEFAULT '',
+  updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_work_logs_user_period ON work_logs (user_id, started_at, ended_at);
+
+CREATE TRIGGER work_logs_updated_at BEFORE UPDATE ON work_logs FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+INSERT INTO migrations VALUES ('0002.up.sql', NOW());
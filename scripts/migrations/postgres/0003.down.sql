@@ -0,0 +1,4 @@
+ALTER TABLE tasks DROP CONSTRAINT fk_tasks_finished_by;
+ALTER TABLE tasks DROP COLUMN finished_by_user_id;
+
+DELETE FROM migrations WHERE name = '0003.up.sql';
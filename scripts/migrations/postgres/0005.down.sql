@@ -0,0 +1,10 @@
+ALTER TABLE teams DROP CONSTRAINT teams_name_key;
+ALTER TABLE teams ADD CONSTRAINT teams_name_key UNIQUE (name);
+ALTER TABLE teams DROP COLUMN organization_id;
+
+ALTER TABLE tasks DROP COLUMN organization_id;
+ALTER TABLE users DROP COLUMN organization_id;
+
+DROP TABLE IF EXISTS organizations;
+
+DELETE FROM migrations WHERE name = '0005.up.sql';
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS organizations (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  invite_code VARCHAR(64) NOT NULL UNIQUE,
+  updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER organizations_updated_at BEFORE UPDATE ON organizations FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- existing data belongs to one organization, managers should rotate its invite code
+INSERT INTO organizations (name, invite_code) VALUES ('default', md5(random()::text || clock_timestamp()::text));
+
+ALTER TABLE users ADD COLUMN organization_id INTEGER REFERENCES organizations (id);
+UPDATE users SET organization_id = (SELECT id FROM organizations WHERE name = 'default');
+ALTER TABLE users ALTER COLUMN organization_id SET NOT NULL;
+
+ALTER TABLE tasks ADD COLUMN organization_id INTEGER REFERENCES organizations (id);
+UPDATE tasks SET organization_id = (SELECT organization_id FROM users WHERE users.id = tasks.created_by_user_id);
+ALTER TABLE tasks ALTER COLUMN organization_id SET NOT NULL;
+
+CREATE INDEX idx_tasks_organization ON tasks (organization_id);
+
+-- team names are unique in the organization
+ALTER TABLE teams ADD COLUMN organization_id INTEGER REFERENCES organizations (id);
+UPDATE teams SET organization_id = (SELECT id FROM organizations WHERE name = 'default');
+ALTER TABLE teams ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE teams DROP CONSTRAINT teams_name_key;
+ALTER TABLE teams ADD CONSTRAINT teams_name_key UNIQUE (organization_id, name);
+
+INSERT INTO migrations VALUES ('0005.up.sql', NOW());
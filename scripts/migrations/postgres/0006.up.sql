@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS permissions (
+  id INTEGER PRIMARY KEY,
+  name VARCHAR(50) NOT NULL UNIQUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS users_role_permissions (
+  user_role_id INTEGER NOT NULL REFERENCES users_role (id),
+  permission_id INTEGER NOT NULL REFERENCES permissions (id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_role_id, permission_id)
+);
+
+-- existing roles keep their behaviour, see entity.Permission
+INSERT INTO permissions (id, name) VALUES
+  (1, 'task:create'),
+  (2, 'task:read:own'),
+  (3, 'task:read:team'),
+  (4, 'task:read:any'),
+  (5, 'task:update:own'),
+  (6, 'task:delete:own'),
+  (7, 'task:delete:team'),
+  (8, 'task:delete:any'),
+  (9, 'task:reassign:team'),
+  (10, 'task:import'),
+  (11, 'worklog:create:own'),
+  (12, 'report:read:own'),
+  (13, 'report:read:any'),
+  (14, 'stats:read'),
+  (15, 'team:manage'),
+  (16, 'user:manage');
+
+-- managers
+INSERT INTO users_role_permissions (user_role_id, permission_id) VALUES
+  (2, 3), (2, 7), (2, 9), (2, 10), (2, 13), (2, 14), (2, 15), (2, 16);
+
+-- technicians
+INSERT INTO users_role_permissions (user_role_id, permission_id) VALUES
+  (3, 1), (3, 2), (3, 5), (3, 11), (3, 12);
+
+INSERT INTO migrations VALUES ('0006.up.sql', NOW());
@@ -0,0 +1,24 @@
+-- service users act for the api keys, they have no permissions of their own
+INSERT INTO users_role (id, name, code) VALUES (4, 'service', 30);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+  id SERIAL PRIMARY KEY,
+  organization_id INTEGER NOT NULL REFERENCES organizations (id),
+  user_id INTEGER NOT NULL UNIQUE REFERENCES users (id),
+  name VARCHAR(50) NOT NULL,
+  key_hash CHAR(64) NOT NULL UNIQUE,
+  created_by_user_id INTEGER NOT NULL REFERENCES users (id),
+  expires_at TIMESTAMPTZ NOT NULL,
+  last_used_at TIMESTAMPTZ DEFAULT NULL,
+  revoked_at TIMESTAMPTZ DEFAULT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS api_key_permissions (
+  api_key_id INTEGER NOT NULL REFERENCES api_keys (id),
+  permission_id INTEGER NOT NULL REFERENCES permissions (id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (api_key_id, permission_id)
+);
+
+INSERT INTO migrations VALUES ('0007.up.sql', NOW());
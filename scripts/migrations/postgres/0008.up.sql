@@ -0,0 +1,11 @@
+-- users signed in with an identity provider, by the issuer and the subject of
+-- their id tokens
+CREATE TABLE IF NOT EXISTS user_identities (
+  issuer VARCHAR(255) NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  user_id INTEGER NOT NULL REFERENCES users (id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (issuer, subject)
+);
+
+INSERT INTO migrations VALUES ('0008.up.sql', NOW());
@@ -0,0 +1,21 @@
+-- the totp secret of a user, enabled once a code of it was verified
+CREATE TABLE IF NOT EXISTS user_mfa (
+  user_id INTEGER NOT NULL PRIMARY KEY REFERENCES users (id),
+  secret VARCHAR(64) NOT NULL,
+  enabled_at TIMESTAMPTZ DEFAULT NULL,
+  last_step BIGINT NOT NULL DEFAULT 0,
+  failed_attempts INTEGER NOT NULL DEFAULT 0,
+  failed_at TIMESTAMPTZ DEFAULT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- recovery codes sign in without the authenticator, once each
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+  user_id INTEGER NOT NULL REFERENCES users (id),
+  code_hash CHAR(64) NOT NULL,
+  used_at TIMESTAMPTZ DEFAULT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, code_hash)
+);
+
+INSERT INTO migrations VALUES ('0009.up.sql', NOW());
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS migrations;
+DROP TABLE IF EXISTS tasks;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS users_role;
@@ -0,0 +1,55 @@
+CREATE TABLE IF NOT EXISTS users_role (
+  id INTEGER NOT NULL PRIMARY KEY,
+  name VARCHAR(50) NOT NULL,
+  code INTEGER NOT NULL,
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO users_role (id, name, code) VALUES (1, 'visitor', 0), (2, 'manager', 10), (3, 'technician', 20);
+
+CREATE TABLE IF NOT EXISTS users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(50) NOT NULL,
+  username VARCHAR(30) NOT NULL UNIQUE,
+  password VARCHAR(255) NOT NULL,
+  user_role_id INTEGER NOT NULL REFERENCES users_role (id),
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tasks (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  title VARCHAR(100) NOT NULL,
+  description VARCHAR(2500) NOT NULL,
+  created_by_user_id INTEGER NOT NULL REFERENCES users (id),
+  deleted_by_user_id INTEGER DEFAULT NULL REFERENCES users (id),
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  finished_at DATETIME DEFAULT NULL,
+  deleted_at DATETIME DEFAULT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- MySQL's ON UPDATE CURRENT_TIMESTAMP, recursive triggers are off so the
+-- UPDATE doesn't fire the trigger again
+CREATE TRIGGER users_role_updated_at AFTER UPDATE ON users_role FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+  UPDATE users_role SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER users_updated_at AFTER UPDATE ON users FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+  UPDATE users SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER tasks_updated_at AFTER UPDATE ON tasks FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+  UPDATE tasks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE IF NOT EXISTS migrations (
+  name VARCHAR(100) NOT NULL PRIMARY KEY,
+  applied_at DATETIME NOT NULL
+);
+
+INSERT INTO migrations VALUES ('0001.up.sql', CURRENT_TIMESTAMP);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS work_logs;
+
+DELETE FROM migrations WHERE name = '0002.up.sql';
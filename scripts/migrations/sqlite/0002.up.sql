@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS work_logs (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  task_id INTEGER NOT NULL REFERENCES tasks (id),
+  user_id INTEGER NOT NULL REFERENCES users (id),
+  started_at DATETIME NOT NULL,
+  ended_at DATETIME NULL DEFAULT NULL,
+  note VARCHAR(500) NOT NULL DEFAULT '',
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_work_logs_user_period ON work_logs (user_id, started_at, ended_at);
+
+CREATE TRIGGER work_logs_updated_at AFTER UPDATE ON work_logs FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+  UPDATE work_logs SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+INSERT INTO migrations VALUES ('0002.up.sql', CURRENT_TIMESTAMP);
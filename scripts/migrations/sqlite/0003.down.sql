@@ -0,0 +1,30 @@
+-- SQLite can't drop a column with a foreign key, the table is rebuilt. Run
+-- outside a transaction, foreign keys can't be switched off inside one.
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE tasks_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  title VARCHAR(100) NOT NULL,
+  description VARCHAR(2500) NOT NULL,
+  created_by_user_id INTEGER NOT NULL REFERENCES users (id),
+  deleted_by_user_id INTEGER DEFAULT NULL REFERENCES users (id),
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  finished_at DATETIME DEFAULT NULL,
+  deleted_at DATETIME DEFAULT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO tasks_new (id, title, description, created_by_user_id, deleted_by_user_id, updated_at, finished_at, deleted_at, created_at)
+SELECT id, title, description, created_by_user_id, deleted_by_user_id, updated_at, finished_at, deleted_at, created_at FROM tasks;
+
+DROP TABLE tasks;
+ALTER TABLE tasks_new RENAME TO tasks;
+
+CREATE TRIGGER tasks_updated_at AFTER UPDATE ON tasks FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+  UPDATE tasks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+PRAGMA foreign_keys = ON;
+
+DELETE FROM migrations WHERE name = '0003.up.sql';
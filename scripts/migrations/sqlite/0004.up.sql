@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS teams (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(50) NOT NULL UNIQUE,
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS team_members (
+  team_id INTEGER NOT NULL REFERENCES teams (id),
+  user_id INTEGER NOT NULL REFERENCES users (id),
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX idx_team_members_user ON team_members (user_id);
+
+CREATE TRIGGER teams_updated_at AFTER UPDATE ON teams FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+  UPDATE teams SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- existing users share one team, so managers keep seeing the tasks they saw
+INSERT INTO teams (name) VALUES ('default');
+INSERT INTO team_members (team_id, user_id) SELECT teams.id, users.id FROM teams, users;
+
+INSERT INTO migrations VALUES ('0004.up.sql', CURRENT_TIMESTAMP);
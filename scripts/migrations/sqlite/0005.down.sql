@@ -0,0 +1,71 @@
+-- SQLite can't drop a column with a foreign key, the tables are rebuilt. Run
+-- outside a transaction, foreign keys can't be switched off inside one.
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE teams_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(50) NOT NULL UNIQUE,
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO teams_new (id, name, updated_at, created_at) SELECT id, name, updated_at, created_at FROM teams;
+
+DROP TABLE teams;
+ALTER TABLE teams_new RENAME TO teams;
+
+CREATE TRIGGER teams_updated_at AFTER UPDATE ON teams FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+  UPDATE teams SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE tasks_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  title VARCHAR(100) NOT NULL,
+  description VARCHAR(2500) NOT NULL,
+  created_by_user_id INTEGER NOT NULL REFERENCES users (id),
+  deleted_by_user_id INTEGER DEFAULT NULL REFERENCES users (id),
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  finished_at DATETIME DEFAULT NULL,
+  deleted_at DATETIME DEFAULT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  finished_by_user_id INTEGER DEFAULT NULL REFERENCES users (id)
+);
+
+INSERT INTO tasks_new (id, title, description, created_by_user_id, deleted_by_user_id, updated_at, finished_at, deleted_at, created_at, finished_by_user_id)
+SELECT id, title, description, created_by_user_id, deleted_by_user_id, updated_at, finished_at, deleted_at, created_at, finished_by_user_id FROM tasks;
+
+DROP TABLE tasks;
+ALTER TABLE tasks_new RENAME TO tasks;
+
+CREATE TRIGGER tasks_updated_at AFTER UPDATE ON tasks FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+  UPDATE tasks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE users_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(50) NOT NULL,
+  username VARCHAR(30) NOT NULL UNIQUE,
+  password VARCHAR(255) NOT NULL,
+  user_role_id INTEGER NOT NULL REFERENCES users_role (id),
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO users_new (id, name, username, password, user_role_id, updated_at, created_at)
+SELECT id, name, username, password, user_role_id, updated_at, created_at FROM users;
+
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+
+CREATE TRIGGER users_updated_at AFTER UPDATE ON users FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+  UPDATE users SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+DROP TABLE IF EXISTS organizations;
+
+PRAGMA foreign_keys = ON;
+
+DELETE FROM migrations WHERE name = '0005.up.sql';
@@ -0,0 +1,66 @@
+CREATE TABLE IF NOT EXISTS organizations (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(100) NOT NULL,
+  invite_code VARCHAR(64) NOT NULL UNIQUE,
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER organizations_updated_at AFTER UPDATE ON organizations FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+  UPDATE organizations SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- existing data belongs to one organization, managers should rotate its invite code
+INSERT INTO organizations (name, invite_code) VALUES ('default', lower(hex(randomblob(16))));
+
+-- SQLite only adds nullable columns with a foreign key, the api always sets them
+ALTER TABLE users ADD COLUMN organization_id INTEGER DEFAULT NULL REFERENCES organizations (id);
+UPDATE users SET organization_id = (SELECT id FROM organizations WHERE name = 'default');
+
+ALTER TABLE tasks ADD COLUMN organization_id INTEGER DEFAULT NULL REFERENCES organizations (id);
+UPDATE tasks SET organization_id = (SELECT organization_id FROM users WHERE users.id = tasks.created_by_user_id);
+
+CREATE INDEX idx_tasks_organization ON tasks (organization_id);
+
+-- team names are unique in the organization, SQLite can't change the unique
+-- constraint of a column so the teams are copied to new tables
+CREATE TEMP TABLE team_members_copy AS SELECT * FROM team_members;
+CREATE TEMP TABLE teams_copy AS SELECT * FROM teams;
+
+DROP TABLE team_members;
+DROP TABLE teams;
+
+CREATE TABLE teams (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  organization_id INTEGER NOT NULL REFERENCES organizations (id),
+  name VARCHAR(50) NOT NULL,
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (organization_id, name)
+);
+
+CREATE TABLE team_members (
+  team_id INTEGER NOT NULL REFERENCES teams (id),
+  user_id INTEGER NOT NULL REFERENCES users (id),
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (team_id, user_id)
+);
+
+INSERT INTO teams (id, organization_id, name, updated_at, created_at)
+SELECT id, (SELECT id FROM organizations WHERE name = 'default'), name, updated_at, created_at FROM teams_copy;
+
+INSERT INTO team_members (team_id, user_id, created_at)
+SELECT team_id, user_id, created_at FROM team_members_copy;
+
+DROP TABLE teams_copy;
+DROP TABLE team_members_copy;
+
+CREATE INDEX idx_team_members_user ON team_members (user_id);
+
+CREATE TRIGGER teams_updated_at AFTER UPDATE ON teams FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+  UPDATE teams SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+INSERT INTO migrations VALUES ('0005.up.sql', CURRENT_TIMESTAMP);
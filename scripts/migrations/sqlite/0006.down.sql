@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS users_role_permissions;
+DROP TABLE IF EXISTS permissions;
+
+DELETE FROM migrations WHERE name = '0006.up.sql';
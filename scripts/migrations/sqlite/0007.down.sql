@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS api_key_permissions;
+DROP TABLE IF EXISTS api_keys;
+
+-- the service users and their role stay, the tasks they created keep their author
+DELETE FROM migrations WHERE name = '0007.up.sql';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
+
+DELETE FROM migrations WHERE name = '0009.up.sql';
@@ -0,0 +1,8 @@
+-- managers read the timesheets of their teammates only, as with their tasks
+INSERT INTO permissions (id, name) VALUES (17, 'report:read:team');
+
+DELETE FROM users_role_permissions WHERE user_role_id = 2 AND permission_id = 13;
+
+INSERT INTO users_role_permissions (user_role_id, permission_id) VALUES (2, 17);
+
+INSERT INTO migrations VALUES ('0010.up.sql', CURRENT_TIMESTAMP);
@@ -0,0 +1,3 @@
+DROP INDEX uq_work_logs_running_user;
+
+DELETE FROM migrations WHERE name = '0011.up.sql';
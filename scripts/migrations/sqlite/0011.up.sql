@@ -0,0 +1,8 @@
+-- users have one running timer at most, timers started at once can't both be
+-- inserted. Timers already running twice are stopped where they started.
+UPDATE work_logs SET ended_at = started_at
+WHERE ended_at IS NULL AND id NOT IN (SELECT MAX(id) FROM work_logs WHERE ended_at IS NULL GROUP BY user_id);
+
+CREATE UNIQUE INDEX uq_work_logs_running_user ON work_logs (user_id) WHERE ended_at IS NULL;
+
+INSERT INTO migrations VALUES ('0011.up.sql', CURRENT_TIMESTAMP);